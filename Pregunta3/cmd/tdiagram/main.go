@@ -0,0 +1,104 @@
+// Comando tdiagram: simulador interactivo de diagramas T. También puede
+// evaluar archivos de comandos para CI (-reporte) o atender como servidor de
+// lenguaje para archivos .tdiag (-lsp).
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+
+	"tdiagram"
+)
+
+// ejecutarReporte procesa los archivos de comandos (o la entrada estándar si
+// no se indica ninguno), escribe el reporte en la salida estándar y devuelve
+// el código de salida: 0 si todos los programas son ejecutables, 1 si alguno
+// no lo es y 2 ante errores de entrada.
+func ejecutarReporte(formato string, archivos []string) int {
+	sesion := tdiagram.NuevaSesion()
+	sesion.RedirigirSalida(os.Stderr)
+
+	if len(archivos) == 0 {
+		if err := sesion.ProcesarEntrada(os.Stdin); err != nil {
+			fmt.Fprintf(os.Stderr, "Error leyendo entrada: %v\n", err)
+			return 2
+		}
+	}
+	for _, archivo := range archivos {
+		f, err := os.Open(archivo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error abriendo '%s': %v\n", archivo, err)
+			return 2
+		}
+		err = sesion.ProcesarEntrada(f)
+		f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error leyendo '%s': %v\n", archivo, err)
+			return 2
+		}
+	}
+
+	reporte := sesion.Actual().EvaluarTodos()
+	if err := reporte.EscribirReporte(os.Stdout, formato); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	if !reporte.Exitoso() {
+		return 1
+	}
+	return 0
+}
+
+func main() {
+	formato := flag.String("reporte", "", "evalúa los archivos de comandos y emite un reporte (json o junit)")
+	lsp := flag.Bool("lsp", false, "atiende como servidor de lenguaje (LSP) para archivos .tdiag por la entrada y salida estándar")
+	flag.Parse()
+	if *lsp {
+		os.Exit(tdiagram.ServirLSP(os.Stdin, os.Stdout, os.Stderr))
+	}
+	if *formato != "" {
+		os.Exit(ejecutarReporte(*formato, flag.Args()))
+	}
+
+	sesion := tdiagram.NuevaSesion()
+	scanner := bufio.NewScanner(os.Stdin)
+
+	fmt.Println("Simulador de Diagramas T")
+	fmt.Println("Comandos disponibles:")
+	fmt.Println("  DEFINIR PROGRAMA <nombre> <lenguaje>")
+	fmt.Println("  DEFINIR INTERPRETE <lenguaje_base> <lenguaje> [opciones]")
+	fmt.Println("  DEFINIR TRADUCTOR <lenguaje_base> <lenguaje_origen> <lenguaje_destino> [opciones]")
+	fmt.Println("    opciones: DESDE <fecha> HASTA <fecha> CONFIABLE|NOCONFIABLE PROCEDENCIA <etiqueta>")
+	fmt.Println("              VERSION <version> COSTO <numero> CARACTERISTICAS <a,b,...>")
+	fmt.Println("  EJECUTABLE <nombre> [EN <fecha> | CONFIABLE]")
+	fmt.Println("  CRONOLOGIA")
+	fmt.Println("  REPORTE <JSON|JUNIT>")
+	fmt.Println("  IMPORTAR <archivo.toml|archivo.json>")
+	fmt.Println("  DEPENDE [<lenguaje> | INTERPRETE <lenguaje_base> <lenguaje> | TRADUCTOR <lenguaje_base> <lenguaje_origen> <lenguaje_destino>]")
+	fmt.Println("  PLANIFICAR [<programa> ...]")
+	fmt.Println("  HISTORIAL [<programa> | EXPORTAR <archivo.jsonl> | REPRODUCIR <archivo.jsonl>]")
+	fmt.Println("  ENTORNO NUEVO <nombre>")
+	fmt.Println("  ENTORNO USAR <nombre>")
+	fmt.Println("  ENTORNO COPIAR <origen> <destino>")
+	fmt.Println("  ENTORNO DIFF <a> <b>")
+	fmt.Println("  SALIR")
+	fmt.Println()
+
+	for {
+		fmt.Print("$> ")
+		if !scanner.Scan() {
+			break
+		}
+
+		comando := scanner.Text()
+		if !sesion.ProcesarComando(comando) {
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error leyendo entrada: %v\n", err)
+	}
+}
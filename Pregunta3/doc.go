@@ -0,0 +1,23 @@
+// Package tdiagram simula diagramas T: programas escritos en algún
+// lenguaje, intérpretes y traductores, y decide qué programas pueden
+// ejecutarse en la máquina, cuyo lenguaje es LOCAL.
+//
+// Un System se arma con definiciones y luego se consulta:
+//
+//	s := tdiagram.NuevoSistema()
+//	s.RedirigirSalida(io.Discard)
+//	s.DefinirPrograma("servidor", "Go")
+//	s.DefinirInterprete("LOCAL", "Go")
+//	ejecutable, pasos, err := s.EsEjecutable("servidor")
+//
+// Program, Interpreter y Translator describen las definiciones, y
+// Programas, Interpretes y Traductores devuelven las de un sistema. Las
+// definiciones con opciones (ventanas de disponibilidad, confianza, costos)
+// se hacen con DefinirInterpreteCon y DefinirTraductorCon, que reciben
+// Metadatos, o con ProcesarComando, que acepta el mismo lenguaje de comandos
+// que el simulador interactivo. Interpreter.Metadatos y Translator.Metadatos
+// devuelven los metadatos de una definición. Sesion agrega entornos con
+// nombre sobre varios sistemas.
+//
+// El simulador interactivo está en tdiagram/cmd/tdiagram.
+package tdiagram
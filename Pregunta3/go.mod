@@ -0,0 +1,3 @@
+module tdiagram
+
+go 1.21
@@ -0,0 +1,111 @@
+package tdiagram
+
+import (
+	"fmt"
+	"strings"
+)
+
+// esConfiable admite solo los componentes que no están marcados como no
+// confiables
+func esConfiable(_ Paso, m Metadatos) bool {
+	return !m.noConfiable
+}
+
+// describirConfianza devuelve la confianza y la procedencia del componente
+// en texto, o una cadena vacía si es confiable y no declara procedencia
+func (m Metadatos) describirConfianza() string {
+	partes := make([]string, 0, 2)
+	if m.noConfiable {
+		partes = append(partes, "no confiable")
+	}
+	if m.procedencia != "" {
+		partes = append(partes, "procedencia: "+m.procedencia)
+	}
+	if len(partes) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(partes, ", ") + "]"
+}
+
+// EsEjecutableConfiable indica si un programa puede ejecutarse usando
+// únicamente intérpretes y traductores confiables
+func (s *System) EsEjecutableConfiable(nombre string) (bool, []Paso, error) {
+	programa, existe := s.programas[nombre]
+	if !existe {
+		return false, nil, fmt.Errorf("ERROR: No existe un programa con el nombre '%s'", nombre)
+	}
+	pasos, ok := cadena(programa.Language, s.derivacionesFiltradas(esConfiable))
+	return ok, pasos, nil
+}
+
+// PuedeEjecutarConfiable verifica si un programa puede ejecutarse usando
+// únicamente componentes confiables
+func (s *System) PuedeEjecutarConfiable(nombre string) error {
+	ejecutable, _, err := s.EsEjecutableConfiable(nombre)
+	if err != nil {
+		return err
+	}
+
+	if ejecutable {
+		fmt.Fprintf(s.salida, "Si, es posible ejecutar el programa '%s' usando solo componentes confiables\n", nombre)
+		return nil
+	}
+
+	fmt.Fprintf(s.salida, "No es posible ejecutar el programa '%s' usando solo componentes confiables\n", nombre)
+	return nil
+}
+
+// pasoConfiable indica si alguno de los componentes que aplican el paso es
+// confiable
+func (s *System) pasoConfiable(paso Paso) bool {
+	for _, m := range s.metadatosDe(paso) {
+		if !m.noConfiable {
+			return true
+		}
+	}
+	return false
+}
+
+// pasosNoConfiables devuelve los pasos de la cadena que no pueden darse con
+// ningún componente confiable
+func (s *System) pasosNoConfiables(pasos []Paso) []Paso {
+	resultado := make([]Paso, 0)
+	for _, paso := range pasos {
+		if !s.pasoConfiable(paso) {
+			resultado = append(resultado, paso)
+		}
+	}
+	return resultado
+}
+
+// metadatosDe devuelve los metadatos de todos los componentes que aplican
+// el paso dado
+func (s *System) metadatosDe(paso Paso) []Metadatos {
+	resultado := make([]Metadatos, 0, 1)
+	for _, interp := range s.interpretes {
+		if interp.paso() == paso {
+			resultado = append(resultado, interp.metadatos)
+		}
+	}
+	for _, trad := range s.traductores {
+		if trad.paso() == paso {
+			resultado = append(resultado, trad.metadatos)
+		}
+	}
+	return resultado
+}
+
+// describirProcedencia devuelve las procedencias declaradas por los
+// componentes que aplican el paso
+func (s *System) describirProcedencia(paso Paso) string {
+	procedencias := make([]string, 0)
+	for _, m := range s.metadatosDe(paso) {
+		if m.procedencia != "" {
+			procedencias = append(procedencias, m.procedencia)
+		}
+	}
+	if len(procedencias) == 0 {
+		return " (procedencia desconocida)"
+	}
+	return " (procedencia: " + strings.Join(procedencias, ", ") + ")"
+}
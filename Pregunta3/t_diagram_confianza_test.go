@@ -0,0 +1,137 @@
+package tdiagram
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// sistemaThompson modela el escenario de "Reflections on Trusting Trust": la
+// única implementación de C con la que se ejecuta el login viene de un
+// binario de procedencia externa
+func sistemaThompson() *System {
+	s := NuevoSistema()
+	s.salida = io.Discard
+	s.ProcesarComando("DEFINIR PROGRAMA login C")
+	s.ProcesarComando("DEFINIR PROGRAMA calculadora Python")
+	s.ProcesarComando("DEFINIR INTERPRETE LOCAL C NOCONFIABLE PROCEDENCIA binario-externo")
+	s.ProcesarComando("DEFINIR INTERPRETE LOCAL Python PROCEDENCIA auditado")
+	return s
+}
+
+// TestDefinirConConfianza verifica que se guardan la confianza y la procedencia
+func TestDefinirConConfianza(t *testing.T) {
+	s := sistemaThompson()
+
+	meta := s.interpretes[0].metadatos
+	if !meta.noConfiable || meta.procedencia != "binario-externo" {
+		t.Errorf("Metadatos inesperados: %+v", meta)
+	}
+	if s.interpretes[1].metadatos.noConfiable {
+		t.Error("Los componentes son confiables por omisión")
+	}
+	if _, err := analizarOpciones([]string{"PROCEDENCIA"}); err == nil {
+		t.Error("PROCEDENCIA sin etiqueta debería dar error")
+	}
+}
+
+// TestEjecutableConfiable verifica la consulta usando solo componentes confiables
+func TestEjecutableConfiable(t *testing.T) {
+	s := sistemaThompson()
+
+	if ok, _, _ := s.EsEjecutable("login"); !ok {
+		t.Error("'login' es ejecutable si se admiten componentes no confiables")
+	}
+	if ok, _, _ := s.EsEjecutableConfiable("login"); ok {
+		t.Error("'login' no debería ser ejecutable usando solo componentes confiables")
+	}
+	if ok, _, _ := s.EsEjecutableConfiable("calculadora"); !ok {
+		t.Error("'calculadora' debería ser ejecutable con componentes confiables")
+	}
+	if _, _, err := s.EsEjecutableConfiable("noexiste"); err == nil {
+		t.Error("Debería dar error con un programa inexistente")
+	}
+}
+
+// TestCadenaPrefiereConfiables verifica que la cadena reportada evita los
+// componentes no confiables cuando hay alternativa
+func TestCadenaPrefiereConfiables(t *testing.T) {
+	s := sistemaThompson()
+	s.ProcesarComando("DEFINIR INTERPRETE Python C PROCEDENCIA compilado-desde-fuentes")
+
+	_, pasos, _ := s.EsEjecutable("login")
+	if len(pasos) != 2 || pasos[1].Base != "Python" {
+		t.Errorf("La cadena debería pasar por el intérprete confiable escrito en Python: %v", pasos)
+	}
+	if ok, _, _ := s.EsEjecutableConfiable("login"); !ok {
+		t.Error("Con el intérprete confiable 'login' debería ser ejecutable")
+	}
+}
+
+// TestPropagacionConfianzaEnReporte verifica que el reporte marca los
+// programas que dependen de componentes no confiables
+func TestPropagacionConfianzaEnReporte(t *testing.T) {
+	s := sistemaThompson()
+	s.ProcesarComando("DEFINIR PROGRAMA servidor Go")
+	s.ProcesarComando("DEFINIR INTERPRETE C Go")
+
+	for _, res := range s.EvaluarTodos().Programas {
+		switch res.Nombre {
+		case "calculadora":
+			if !res.Confiable || len(res.NoConfiables) != 0 {
+				t.Errorf("'calculadora' debería ser confiable: %+v", res)
+			}
+		case "login", "servidor":
+			if !res.Ejecutable || res.Confiable {
+				t.Errorf("'%s' debería ser ejecutable pero no confiable: %+v", res.Nombre, res)
+			}
+			if len(res.NoConfiables) != 1 || res.NoConfiables[0].Destino != "C" {
+				t.Errorf("'%s' debería señalar el intérprete de C: %+v", res.Nombre, res.NoConfiables)
+			}
+		}
+	}
+}
+
+// TestComandosConfianza verifica la salida de EJECUTABLE con componentes no
+// confiables
+func TestComandosConfianza(t *testing.T) {
+	s := sistemaThompson()
+	var buf bytes.Buffer
+	s.salida = &buf
+
+	s.ProcesarComando("EJECUTABLE login")
+	if !strings.Contains(buf.String(), "solo pasando por componentes no confiables") ||
+		!strings.Contains(buf.String(), "(procedencia: binario-externo)") {
+		t.Errorf("Salida inesperada: %q", buf.String())
+	}
+
+	buf.Reset()
+	s.ProcesarComando("EJECUTABLE login CONFIABLE")
+	if !strings.HasPrefix(buf.String(), "No es posible ejecutar el programa 'login' usando solo componentes confiables") {
+		t.Errorf("Salida inesperada: %q", buf.String())
+	}
+
+	buf.Reset()
+	s.ProcesarComando("EJECUTABLE calculadora")
+	if buf.String() != "Si, es posible ejecutar el programa 'calculadora'\n" {
+		t.Errorf("Salida inesperada: %q", buf.String())
+	}
+}
+
+// TestImportarConfianza verifica la confianza declarada en manifiestos
+func TestImportarConfianza(t *testing.T) {
+	manifiesto := `{"traductor": [
+  {"base": "LOCAL", "origen": "C", "destino": "LOCAL", "confiable": false, "procedencia": "externo"},
+  {"base": "LOCAL", "origen": "Go", "destino": "LOCAL", "confiable": "no"}
+]}`
+	s, resultado := importar(t, "m.json", manifiesto)
+
+	if resultado.Traductores != 1 || !s.traductores[0].metadatos.noConfiable ||
+		s.traductores[0].metadatos.procedencia != "externo" {
+		t.Errorf("Importación inesperada: %+v %+v", resultado, s.traductores)
+	}
+	if p, ok := buscarProblema(resultado, "debe ser true o false"); !ok || p.Linea != 3 {
+		t.Errorf("Se esperaba un booleano inválido en la línea 3: %v", resultado.Problemas)
+	}
+}
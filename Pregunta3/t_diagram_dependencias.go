@@ -0,0 +1,200 @@
+package tdiagram
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Impacto describe los programas y lenguajes que dejan de ser ejecutables
+// al retirar uno o más componentes del sistema
+type Impacto struct {
+	Programas []string
+	Lenguajes []string
+}
+
+// Vacio indica si retirar el componente no afecta a nada
+func (im Impacto) Vacio() bool {
+	return len(im.Programas) == 0 && len(im.Lenguajes) == 0
+}
+
+// ClasificacionComponente indica si un componente es crítico (retirarlo deja
+// algún programa sin poder ejecutarse) o redundante
+type ClasificacionComponente struct {
+	Componente Paso
+	Critico    bool
+	Impacto    Impacto
+}
+
+// impactoSin compara la situación actual con la que resulta de usar solo los
+// componentes que acepta admitir
+func (s *System) impactoSin(admitir func(Paso) bool) Impacto {
+	antes := s.derivaciones()
+	despues := s.derivacionesFiltradas(func(p Paso, _ Metadatos) bool { return admitir(p) })
+	return s.compararDerivaciones(antes, despues)
+}
+
+// compararDerivaciones devuelve, ordenados, los lenguajes y programas que son
+// ejecutables según antes y dejan de serlo según despues
+func (s *System) compararDerivaciones(antes, despues map[string]*Paso) Impacto {
+	impacto := Impacto{Programas: make([]string, 0), Lenguajes: make([]string, 0)}
+	for lenguaje := range antes {
+		if _, sigue := despues[lenguaje]; !sigue {
+			impacto.Lenguajes = append(impacto.Lenguajes, lenguaje)
+		}
+	}
+	for nombre, programa := range s.programas {
+		_, antesOk := antes[programa.Language]
+		_, despuesOk := despues[programa.Language]
+		if antesOk && !despuesOk {
+			impacto.Programas = append(impacto.Programas, nombre)
+		}
+	}
+	sort.Strings(impacto.Lenguajes)
+	sort.Strings(impacto.Programas)
+	return impacto
+}
+
+// DependenciasDeInterprete devuelve lo que deja de ser ejecutable si se
+// retiran los intérpretes para lenguaje escritos en lenguajeBase
+func (s *System) DependenciasDeInterprete(lenguajeBase, lenguaje string) (Impacto, error) {
+	buscado := Interpreter{Base: lenguajeBase, Language: lenguaje}.paso()
+	return s.dependenciasDe(buscado)
+}
+
+// DependenciasDeTraductor devuelve lo que deja de ser ejecutable si se
+// retiran los traductores de origen a destino escritos en lenguajeBase
+func (s *System) DependenciasDeTraductor(lenguajeBase, origen, destino string) (Impacto, error) {
+	buscado := Translator{Base: lenguajeBase, Source: origen, Target: destino}.paso()
+	return s.dependenciasDe(buscado)
+}
+
+func (s *System) dependenciasDe(buscado Paso) (Impacto, error) {
+	if !s.tieneComponente(buscado) {
+		return Impacto{}, fmt.Errorf("ERROR: No existe un %s", buscado)
+	}
+	return s.impactoSin(func(p Paso) bool { return p != buscado }), nil
+}
+
+// DependenciasDeLenguaje devuelve lo que deja de ser ejecutable si el
+// lenguaje deja de estar disponible, es decir, si se retiran todos los
+// intérpretes y traductores que producen ese lenguaje. Retirar LOCAL deja
+// sin ejecutar a todo el sistema.
+func (s *System) DependenciasDeLenguaje(lenguaje string) Impacto {
+	if lenguaje == "LOCAL" {
+		return s.compararDerivaciones(s.derivaciones(), map[string]*Paso{})
+	}
+	return s.impactoSin(func(p Paso) bool { return p.Destino != lenguaje })
+}
+
+// tieneComponente indica si hay algún intérprete o traductor con ese paso
+func (s *System) tieneComponente(buscado Paso) bool {
+	for _, componente := range s.componentes() {
+		if componente == buscado {
+			return true
+		}
+	}
+	return false
+}
+
+// componentes devuelve los intérpretes y traductores del sistema sin
+// repetir los que están definidos más de una vez
+func (s *System) componentes() []Paso {
+	vistos := make(map[Paso]bool)
+	resultado := make([]Paso, 0, len(s.interpretes)+len(s.traductores))
+	agregar := func(p Paso) {
+		if !vistos[p] {
+			vistos[p] = true
+			resultado = append(resultado, p)
+		}
+	}
+	for _, interp := range s.interpretes {
+		agregar(interp.paso())
+	}
+	for _, trad := range s.traductores {
+		agregar(trad.paso())
+	}
+	return resultado
+}
+
+// ClasificarComponentes evalúa cada componente del sistema y lo clasifica
+// como crítico o redundante. Los críticos aparecen primero.
+func (s *System) ClasificarComponentes() []ClasificacionComponente {
+	clasificaciones := make([]ClasificacionComponente, 0)
+	for _, componente := range s.componentes() {
+		retirado := componente
+		impacto := s.impactoSin(func(p Paso) bool { return p != retirado })
+		clasificaciones = append(clasificaciones, ClasificacionComponente{
+			Componente: componente,
+			Critico:    len(impacto.Programas) > 0,
+			Impacto:    impacto,
+		})
+	}
+	sort.SliceStable(clasificaciones, func(i, j int) bool {
+		return clasificaciones[i].Critico && !clasificaciones[j].Critico
+	})
+	return clasificaciones
+}
+
+// mostrarImpacto imprime lo que deja de ser ejecutable al retirar algo
+func (s *System) mostrarImpacto(descripcion string, impacto Impacto) {
+	if impacto.Vacio() {
+		fmt.Fprintf(s.salida, "Nada depende de %s\n", descripcion)
+		return
+	}
+	fmt.Fprintf(s.salida, "Al retirar %s dejan de ser ejecutables:\n", descripcion)
+	if len(impacto.Programas) > 0 {
+		fmt.Fprintf(s.salida, "  Programas: %s\n", strings.Join(impacto.Programas, ", "))
+	}
+	if len(impacto.Lenguajes) > 0 {
+		fmt.Fprintf(s.salida, "  Lenguajes: %s\n", strings.Join(impacto.Lenguajes, ", "))
+	}
+}
+
+// procesarDepende atiende el comando DEPENDE con los argumentos dados
+func (s *System) procesarDepende(args []string) error {
+	if len(args) == 0 {
+		clasificaciones := s.ClasificarComponentes()
+		if len(clasificaciones) == 0 {
+			fmt.Fprintln(s.salida, "No hay intérpretes ni traductores definidos")
+		}
+		for _, c := range clasificaciones {
+			if c.Critico {
+				fmt.Fprintf(s.salida, "CRITICO: %s (programas afectados: %s)\n",
+					c.Componente, strings.Join(c.Impacto.Programas, ", "))
+			} else {
+				fmt.Fprintf(s.salida, "REDUNDANTE: %s\n", c.Componente)
+			}
+		}
+		return nil
+	}
+
+	switch strings.ToUpper(args[0]) {
+	case "INTERPRETE":
+		if len(args) != 3 {
+			return fmt.Errorf("ERROR: DEPENDE INTERPRETE requiere <lenguaje_base> <lenguaje>")
+		}
+		impacto, err := s.DependenciasDeInterprete(args[1], args[2])
+		if err != nil {
+			return err
+		}
+		s.mostrarImpacto(Interpreter{Base: args[1], Language: args[2]}.paso().String(), impacto)
+
+	case "TRADUCTOR":
+		if len(args) != 4 {
+			return fmt.Errorf("ERROR: DEPENDE TRADUCTOR requiere <lenguaje_base> <lenguaje_origen> <lenguaje_destino>")
+		}
+		impacto, err := s.DependenciasDeTraductor(args[1], args[2], args[3])
+		if err != nil {
+			return err
+		}
+		s.mostrarImpacto(Translator{Base: args[1], Source: args[2], Target: args[3]}.paso().String(), impacto)
+
+	default:
+		if len(args) != 1 {
+			return fmt.Errorf("ERROR: DEPENDE requiere <lenguaje>, INTERPRETE o TRADUCTOR")
+		}
+		s.mostrarImpacto(fmt.Sprintf("el lenguaje '%s'", args[0]), s.DependenciasDeLenguaje(args[0]))
+	}
+	return nil
+}
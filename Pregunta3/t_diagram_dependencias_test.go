@@ -0,0 +1,138 @@
+package tdiagram
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sistemaConRedundancia define dos caminos hacia Java (un intérprete en C y
+// un traductor a Python) y uno solo hacia C
+func sistemaConRedundancia() *System {
+	s := NuevoSistema()
+	s.salida = io.Discard
+	s.DefinirPrograma("factorial", "Java")
+	s.DefinirPrograma("hola", "C")
+	s.DefinirPrograma("suma", "LOCAL")
+	s.DefinirInterprete("LOCAL", "C")
+	s.DefinirInterprete("C", "Java")
+	s.DefinirInterprete("LOCAL", "Python")
+	s.DefinirTraductor("LOCAL", "Python", "Java")
+	return s
+}
+
+// TestDependenciasDeInterpreteCritico verifica el impacto de retirar el
+// único intérprete de C
+func TestDependenciasDeInterpreteCritico(t *testing.T) {
+	s := sistemaConRedundancia()
+
+	impacto, err := s.DependenciasDeInterprete("LOCAL", "C")
+	if err != nil {
+		t.Fatalf("No debería dar error: %v", err)
+	}
+	if !reflect.DeepEqual(impacto.Programas, []string{"hola"}) {
+		t.Errorf("Solo 'hola' debería dejar de ser ejecutable, se obtuvo %v", impacto.Programas)
+	}
+	if !reflect.DeepEqual(impacto.Lenguajes, []string{"C"}) {
+		t.Errorf("Solo C debería dejar de ser ejecutable, se obtuvo %v", impacto.Lenguajes)
+	}
+}
+
+// TestDependenciasDeInterpreteRedundante verifica que retirar un camino
+// alternativo no afecta a nada
+func TestDependenciasDeInterpreteRedundante(t *testing.T) {
+	s := sistemaConRedundancia()
+
+	impacto, err := s.DependenciasDeInterprete("C", "Java")
+	if err != nil {
+		t.Fatalf("No debería dar error: %v", err)
+	}
+	if !impacto.Vacio() {
+		t.Errorf("Java sigue siendo ejecutable por el traductor: %+v", impacto)
+	}
+}
+
+// TestDependenciasDeTraductor verifica el impacto de retirar un traductor
+func TestDependenciasDeTraductor(t *testing.T) {
+	s := sistemaConRedundancia()
+	s.DefinirPrograma("script", "Ruby")
+	s.DefinirTraductor("Java", "Python", "Ruby")
+
+	impacto, err := s.DependenciasDeTraductor("Java", "Python", "Ruby")
+	if err != nil {
+		t.Fatalf("No debería dar error: %v", err)
+	}
+	if !reflect.DeepEqual(impacto.Programas, []string{"script"}) {
+		t.Errorf("Solo 'script' debería verse afectado, se obtuvo %v", impacto.Programas)
+	}
+	if _, err := s.DependenciasDeTraductor("C", "X", "Y"); err == nil {
+		t.Error("Debería dar error con un traductor inexistente")
+	}
+}
+
+// TestDependenciasDeLenguaje verifica el impacto de que un lenguaje deje de
+// estar disponible
+func TestDependenciasDeLenguaje(t *testing.T) {
+	s := sistemaConRedundancia()
+
+	impacto := s.DependenciasDeLenguaje("C")
+	if !reflect.DeepEqual(impacto.Programas, []string{"hola"}) {
+		t.Errorf("Solo 'hola' depende de C, se obtuvo %v", impacto.Programas)
+	}
+
+	impacto = s.DependenciasDeLenguaje("LOCAL")
+	if !reflect.DeepEqual(impacto.Programas, []string{"factorial", "hola", "suma"}) {
+		t.Errorf("Todos los programas dependen de LOCAL, se obtuvo %v", impacto.Programas)
+	}
+}
+
+// TestClasificarComponentes verifica la separación entre componentes
+// críticos y redundantes
+func TestClasificarComponentes(t *testing.T) {
+	s := sistemaConRedundancia()
+	s.DefinirInterprete("LOCAL", "C") // definición repetida
+
+	clasificaciones := s.ClasificarComponentes()
+	if len(clasificaciones) != 4 {
+		t.Fatalf("Se esperaban 4 componentes distintos, se obtuvo %d", len(clasificaciones))
+	}
+
+	criticos := 0
+	for _, c := range clasificaciones {
+		if c.Critico {
+			criticos++
+			if c.Componente.Destino != "C" {
+				t.Errorf("Componente crítico inesperado: %s", c.Componente)
+			}
+		}
+	}
+	if criticos != 1 || !clasificaciones[0].Critico {
+		t.Errorf("Se esperaba un único componente crítico y en primer lugar: %+v", clasificaciones)
+	}
+}
+
+// TestComandoDepende verifica la salida del comando DEPENDE
+func TestComandoDepende(t *testing.T) {
+	s := sistemaConRedundancia()
+	var buf bytes.Buffer
+	s.salida = &buf
+
+	s.ProcesarComando("DEPENDE INTERPRETE LOCAL C")
+	if !strings.Contains(buf.String(), "Programas: hola") {
+		t.Errorf("Salida inesperada: %q", buf.String())
+	}
+
+	buf.Reset()
+	s.ProcesarComando("DEPENDE")
+	if !strings.HasPrefix(buf.String(), "CRITICO: intérprete de 'C' escrito en 'LOCAL'") {
+		t.Errorf("Salida inesperada: %q", buf.String())
+	}
+
+	buf.Reset()
+	s.ProcesarComando("DEPENDE INTERPRETE LOCAL")
+	if !strings.HasPrefix(buf.String(), "ERROR") {
+		t.Errorf("Se esperaba un error de sintaxis: %q", buf.String())
+	}
+}
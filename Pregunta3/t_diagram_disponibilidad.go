@@ -0,0 +1,141 @@
+package tdiagram
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CambioCronologia registra que un programa gana o pierde la posibilidad de
+// ejecutarse a partir de una fecha
+type CambioCronologia struct {
+	Fecha      time.Time
+	Programa   string
+	Ejecutable bool
+}
+
+// derivacionesEn calcula las derivaciones usando solo los componentes
+// disponibles en la fecha dada
+func (s *System) derivacionesEn(fecha time.Time) map[string]*Paso {
+	return s.derivacionesFiltradas(func(_ Paso, m Metadatos) bool {
+		return m.disponibleEn(fecha)
+	})
+}
+
+// EsEjecutableEn indica si un programa puede ejecutarse en una fecha, usando
+// solo los intérpretes y traductores disponibles ese día
+func (s *System) EsEjecutableEn(nombre string, fecha time.Time) (bool, []Paso, error) {
+	programa, existe := s.programas[nombre]
+	if !existe {
+		return false, nil, fmt.Errorf("ERROR: No existe un programa con el nombre '%s'", nombre)
+	}
+	pasos, ok := cadena(programa.Language, s.derivacionesEn(fecha))
+	return ok, pasos, nil
+}
+
+// PuedeEjecutarEn verifica si un programa puede ejecutarse en una fecha
+func (s *System) PuedeEjecutarEn(nombre string, fecha time.Time) error {
+	ejecutable, _, err := s.EsEjecutableEn(nombre, fecha)
+	if err != nil {
+		return err
+	}
+
+	if ejecutable {
+		fmt.Fprintf(s.salida, "Si, es posible ejecutar el programa '%s' en %s\n", nombre, fecha.Format(formatoFecha))
+		return nil
+	}
+
+	fmt.Fprintf(s.salida, "No es posible ejecutar el programa '%s' en %s\n", nombre, fecha.Format(formatoFecha))
+	return nil
+}
+
+// fechasClave devuelve, ordenadas y sin repetir, las fechas en que algún
+// componente empieza o deja de estar disponible
+func (s *System) fechasClave() []time.Time {
+	vistas := make(map[time.Time]bool)
+	registrar := func(m Metadatos) {
+		for _, f := range []time.Time{m.desde, m.hasta} {
+			if !f.IsZero() {
+				vistas[f] = true
+			}
+		}
+	}
+	for _, interp := range s.interpretes {
+		registrar(interp.metadatos)
+	}
+	for _, trad := range s.traductores {
+		registrar(trad.metadatos)
+	}
+
+	fechas := make([]time.Time, 0, len(vistas))
+	for f := range vistas {
+		fechas = append(fechas, f)
+	}
+	sort.Slice(fechas, func(i, j int) bool { return fechas[i].Before(fechas[j]) })
+	return fechas
+}
+
+// ejecutablesEn indica, para cada programa, si puede ejecutarse en la fecha
+func (s *System) ejecutablesEn(fecha time.Time) map[string]bool {
+	derivados := s.derivacionesEn(fecha)
+	estado := make(map[string]bool, len(s.programas))
+	for nombre, programa := range s.programas {
+		_, estado[nombre] = derivados[programa.Language]
+	}
+	return estado
+}
+
+// Cronologia devuelve el estado de cada programa antes de la primera fecha
+// clave y los cambios de ejecutabilidad que ocurren en cada fecha clave
+func (s *System) Cronologia() (map[string]bool, []CambioCronologia) {
+	inicial := s.ejecutablesEn(time.Time{})
+	anterior := inicial
+	cambios := make([]CambioCronologia, 0)
+
+	for _, fecha := range s.fechasClave() {
+		actual := s.ejecutablesEn(fecha)
+		nombres := make([]string, 0)
+		for nombre, ejecutable := range actual {
+			if ejecutable != anterior[nombre] {
+				nombres = append(nombres, nombre)
+			}
+		}
+		sort.Strings(nombres)
+		for _, nombre := range nombres {
+			cambios = append(cambios, CambioCronologia{Fecha: fecha, Programa: nombre, Ejecutable: actual[nombre]})
+		}
+		anterior = actual
+	}
+	return inicial, cambios
+}
+
+// mostrarCronologia imprime el estado inicial y los cambios a lo largo del plan
+func (s *System) mostrarCronologia() {
+	if len(s.fechasClave()) == 0 {
+		fmt.Fprintln(s.salida, "Ningún intérprete o traductor tiene ventana de disponibilidad")
+		return
+	}
+
+	inicial, cambios := s.Cronologia()
+	nombres := make([]string, 0, len(inicial))
+	for nombre := range inicial {
+		nombres = append(nombres, nombre)
+	}
+	sort.Strings(nombres)
+
+	fmt.Fprintln(s.salida, "Estado inicial:")
+	for _, nombre := range nombres {
+		estado := "no ejecutable"
+		if inicial[nombre] {
+			estado = "ejecutable"
+		}
+		fmt.Fprintf(s.salida, "  '%s': %s\n", nombre, estado)
+	}
+	for _, c := range cambios {
+		if c.Ejecutable {
+			fmt.Fprintf(s.salida, "%s: '%s' pasa a ser ejecutable\n", c.Fecha.Format(formatoFecha), c.Programa)
+		} else {
+			fmt.Fprintf(s.salida, "%s: '%s' deja de ser ejecutable\n", c.Fecha.Format(formatoFecha), c.Programa)
+		}
+	}
+}
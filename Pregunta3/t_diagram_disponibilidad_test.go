@@ -0,0 +1,166 @@
+package tdiagram
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fecha construye una fecha para las pruebas
+func fecha(t *testing.T, texto string) time.Time {
+	t.Helper()
+	f, err := analizarFecha(texto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// sistemaConMigracion retira el compilador de Cobol a fines de 2024 y trae
+// uno de Go a mediados de ese año
+func sistemaConMigracion() *System {
+	s := NuevoSistema()
+	s.salida = io.Discard
+	s.ProcesarComando("DEFINIR PROGRAMA nomina Cobol")
+	s.ProcesarComando("DEFINIR PROGRAMA api Go")
+	s.ProcesarComando("DEFINIR PROGRAMA script LOCAL")
+	s.ProcesarComando("DEFINIR INTERPRETE LOCAL Cobol HASTA 2025-01-01")
+	s.ProcesarComando("DEFINIR TRADUCTOR LOCAL Go LOCAL DESDE 2024-06-01")
+	s.ProcesarComando("DEFINIR INTERPRETE LOCAL Go DESDE 2024-06-01")
+	return s
+}
+
+// TestDefinirConVentana verifica que se guardan las ventanas de disponibilidad
+func TestDefinirConVentana(t *testing.T) {
+	s := sistemaConMigracion()
+
+	if !s.interpretes[0].metadatos.hasta.Equal(fecha(t, "2025-01-01")) {
+		t.Errorf("HASTA no se guardó: %v", s.interpretes[0].metadatos)
+	}
+	if !s.traductores[0].metadatos.desde.Equal(fecha(t, "2024-06-01")) {
+		t.Errorf("DESDE no se guardó: %v", s.traductores[0].metadatos)
+	}
+}
+
+// TestOpcionesInvalidas verifica los errores de las opciones de definición
+func TestOpcionesInvalidas(t *testing.T) {
+	casos := [][]string{
+		{"DESDE"},
+		{"DESDE", "2024-13-01"},
+		{"HASTA", "ayer"},
+		{"DESDE", "2025-01-01", "HASTA", "2024-01-01"},
+		{"DURANTE", "2024"},
+	}
+	for _, opciones := range casos {
+		if _, err := analizarOpciones(opciones); err == nil {
+			t.Errorf("%v debería dar error", opciones)
+		}
+	}
+}
+
+// TestEjecutableEnFecha verifica la ejecutabilidad según la fecha
+func TestEjecutableEnFecha(t *testing.T) {
+	s := sistemaConMigracion()
+	casos := []struct {
+		programa, fecha string
+		esperado        bool
+	}{
+		{"nomina", "2024-03-01", true},
+		{"nomina", "2024-12-31", true},
+		{"nomina", "2025-01-01", false},
+		{"api", "2024-05-31", false},
+		{"api", "2024-06-01", true},
+		{"script", "1990-01-01", true},
+	}
+
+	for _, c := range casos {
+		ejecutable, _, err := s.EsEjecutableEn(c.programa, fecha(t, c.fecha))
+		if err != nil {
+			t.Fatalf("No debería dar error: %v", err)
+		}
+		if ejecutable != c.esperado {
+			t.Errorf("'%s' en %s: se esperaba %v", c.programa, c.fecha, c.esperado)
+		}
+	}
+
+	// Sin fecha se consideran todos los componentes
+	if ok, _, _ := s.EsEjecutable("nomina"); !ok {
+		t.Error("Sin fecha 'nomina' debería ser ejecutable")
+	}
+}
+
+// TestCronologia verifica los cambios de ejecutabilidad a lo largo del plan
+func TestCronologia(t *testing.T) {
+	s := sistemaConMigracion()
+
+	inicial, cambios := s.Cronologia()
+	if !inicial["nomina"] || inicial["api"] || !inicial["script"] {
+		t.Errorf("Estado inicial inesperado: %v", inicial)
+	}
+	if len(cambios) != 2 {
+		t.Fatalf("Se esperaban 2 cambios, se obtuvo %+v", cambios)
+	}
+	if cambios[0].Programa != "api" || !cambios[0].Ejecutable || !cambios[0].Fecha.Equal(fecha(t, "2024-06-01")) {
+		t.Errorf("Primer cambio inesperado: %+v", cambios[0])
+	}
+	if cambios[1].Programa != "nomina" || cambios[1].Ejecutable || !cambios[1].Fecha.Equal(fecha(t, "2025-01-01")) {
+		t.Errorf("Segundo cambio inesperado: %+v", cambios[1])
+	}
+}
+
+// TestComandosTemporales verifica la salida de EJECUTABLE ... EN y CRONOLOGIA
+func TestComandosTemporales(t *testing.T) {
+	s := sistemaConMigracion()
+	var buf bytes.Buffer
+	s.salida = &buf
+
+	s.ProcesarComando("EJECUTABLE nomina EN 2025-02-01")
+	if !strings.Contains(buf.String(), "No es posible ejecutar el programa 'nomina' en 2025-02-01") {
+		t.Errorf("Salida inesperada: %q", buf.String())
+	}
+
+	buf.Reset()
+	s.ProcesarComando("CRONOLOGIA")
+	if !strings.Contains(buf.String(), "2025-01-01: 'nomina' deja de ser ejecutable") {
+		t.Errorf("Salida inesperada: %q", buf.String())
+	}
+
+	buf.Reset()
+	s.ProcesarComando("EJECUTABLE nomina DURANTE 2025")
+	if !strings.HasPrefix(buf.String(), "ERROR") {
+		t.Errorf("Se esperaba un error de sintaxis: %q", buf.String())
+	}
+}
+
+// TestImportarVentanas verifica las fechas de disponibilidad en manifiestos
+func TestImportarVentanas(t *testing.T) {
+	manifiesto := `[[interprete]]
+base = "LOCAL"
+lenguaje = "Cobol"
+hasta = "2025-01-01"
+
+[[interprete]]
+base = "LOCAL"
+lenguaje = "Go"
+desde = "2025-01-01"
+hasta = "2024-01-01"
+
+[[interprete]]
+base = "LOCAL"
+lenguaje = "Ada"
+desde = "pronto"
+`
+	s, resultado := importar(t, "m.toml", manifiesto)
+
+	if resultado.Interpretes != 1 || !s.interpretes[0].metadatos.hasta.Equal(fecha(t, "2025-01-01")) {
+		t.Errorf("Solo el intérprete de Cobol debería importarse con su ventana: %+v", resultado)
+	}
+	if p, ok := buscarProblema(resultado, "posterior a 'desde'"); !ok || p.Linea != 10 {
+		t.Errorf("Se esperaba un problema de ventana en la línea 10: %v", resultado.Problemas)
+	}
+	if p, ok := buscarProblema(resultado, "debe ser una fecha"); !ok || p.Linea != 15 {
+		t.Errorf("Se esperaba una fecha inválida en la línea 15: %v", resultado.Problemas)
+	}
+}
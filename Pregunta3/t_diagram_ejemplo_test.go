@@ -0,0 +1,57 @@
+package tdiagram_test
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"tdiagram"
+)
+
+// ExampleSystem muestra cómo usar el motor desde otro paquete
+func ExampleSystem() {
+	s := tdiagram.NuevoSistema()
+	s.RedirigirSalida(io.Discard)
+	s.DefinirPrograma("servidor", "Go")
+	s.DefinirInterprete("LOCAL", "Java")
+	s.DefinirInterprete("Java", "Go")
+
+	ejecutable, pasos, _ := s.EsEjecutable("servidor")
+	fmt.Println(ejecutable)
+	for _, paso := range pasos {
+		fmt.Println(paso)
+	}
+	// Output:
+	// true
+	// intérprete de 'Java' escrito en 'LOCAL'
+	// intérprete de 'Go' escrito en 'Java'
+}
+
+// TestAPIPublica verifica las consultas de las definiciones desde otro paquete
+func TestAPIPublica(t *testing.T) {
+	s := tdiagram.NuevoSistema()
+	s.RedirigirSalida(io.Discard)
+	s.ProcesarComando("DEFINIR PROGRAMA b C")
+	s.ProcesarComando("DEFINIR PROGRAMA a Go")
+	s.ProcesarComando("DEFINIR INTERPRETE LOCAL Go COSTO 2")
+	s.ProcesarComando("DEFINIR TRADUCTOR LOCAL Go C")
+
+	programas := s.Programas()
+	if len(programas) != 2 || programas[0] != (tdiagram.Program{Name: "a", Language: "Go"}) {
+		t.Errorf("Programas inesperados: %+v", programas)
+	}
+	interpretes := s.Interpretes()
+	if len(interpretes) != 1 || interpretes[0].Base != "LOCAL" || interpretes[0].Language != "Go" {
+		t.Errorf("Intérpretes inesperados: %+v", interpretes)
+	}
+	traductores := s.Traductores()
+	if len(traductores) != 1 || traductores[0].Source != "Go" || traductores[0].Target != "C" {
+		t.Errorf("Traductores inesperados: %+v", traductores)
+	}
+
+	// Las copias devueltas no modifican el sistema
+	traductores[0].Target = "Rust"
+	if s.Traductores()[0].Target != "C" {
+		t.Error("Modificar la copia no debería afectar al sistema")
+	}
+}
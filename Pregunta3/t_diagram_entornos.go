@@ -0,0 +1,230 @@
+package tdiagram
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// entornoInicial es el nombre del entorno con el que arranca toda sesión
+const entornoInicial = "principal"
+
+// Sesion mantiene varios sistemas con nombre (entornos) y cuál de ellos
+// recibe los comandos del usuario
+type Sesion struct {
+	entornos map[string]*System
+	actual   string
+	salida   io.Writer
+}
+
+// NuevaSesion crea una sesión con un único entorno vacío llamado "principal"
+func NuevaSesion() *Sesion {
+	return &Sesion{
+		entornos: map[string]*System{entornoInicial: NuevoSistema()},
+		actual:   entornoInicial,
+		salida:   os.Stdout,
+	}
+}
+
+// RedirigirSalida envía los mensajes de la sesión y de todos sus entornos a w
+func (se *Sesion) RedirigirSalida(w io.Writer) {
+	se.salida = w
+	for _, sistema := range se.entornos {
+		sistema.RedirigirSalida(w)
+	}
+}
+
+// Actual devuelve el sistema del entorno en uso
+func (se *Sesion) Actual() *System {
+	return se.entornos[se.actual]
+}
+
+// NuevoEntorno crea un entorno vacío y lo deja en uso
+func (se *Sesion) NuevoEntorno(nombre string) error {
+	if _, existe := se.entornos[nombre]; existe {
+		return fmt.Errorf("ERROR: Ya existe un entorno con el nombre '%s'", nombre)
+	}
+	sistema := NuevoSistema()
+	sistema.salida = se.salida
+	se.entornos[nombre] = sistema
+	se.actual = nombre
+	fmt.Fprintf(se.salida, "Se creó el entorno '%s'\n", nombre)
+	return nil
+}
+
+// UsarEntorno cambia el entorno en uso
+func (se *Sesion) UsarEntorno(nombre string) error {
+	if _, existe := se.entornos[nombre]; !existe {
+		return fmt.Errorf("ERROR: No existe un entorno con el nombre '%s'", nombre)
+	}
+	se.actual = nombre
+	fmt.Fprintf(se.salida, "Usando el entorno '%s'\n", nombre)
+	return nil
+}
+
+// CopiarEntorno crea el entorno destino como copia independiente del origen
+func (se *Sesion) CopiarEntorno(origen, destino string) error {
+	sistema, existe := se.entornos[origen]
+	if !existe {
+		return fmt.Errorf("ERROR: No existe un entorno con el nombre '%s'", origen)
+	}
+	if _, existe := se.entornos[destino]; existe {
+		return fmt.Errorf("ERROR: Ya existe un entorno con el nombre '%s'", destino)
+	}
+	se.entornos[destino] = sistema.Copiar()
+	fmt.Fprintf(se.salida, "Se copió el entorno '%s' en '%s'\n", origen, destino)
+	return nil
+}
+
+// Copiar devuelve una copia del sistema que puede modificarse sin afectar
+// al original
+func (s *System) Copiar() *System {
+	copia := &System{
+		programas:   make(map[string]Program, len(s.programas)),
+		interpretes: append(make([]Interpreter, 0, len(s.interpretes)), s.interpretes...),
+		traductores: append(make([]Translator, 0, len(s.traductores)), s.traductores...),
+		salida:      s.salida,
+		eventos:     append(make([]Evento, 0, len(s.eventos)), s.eventos...),
+		reloj:       s.reloj,
+	}
+	for nombre, programa := range s.programas {
+		copia.programas[nombre] = programa
+	}
+	return copia
+}
+
+// DiferenciaPrograma describe cómo cambia la ejecutabilidad de un programa
+// entre dos entornos. Un programa que no está definido en un entorno se
+// considera no ejecutable en él.
+type DiferenciaPrograma struct {
+	Nombre      string
+	EjecutableA bool
+	EjecutableB bool
+	DefinidoA   bool
+	DefinidoB   bool
+}
+
+// Diferencias compara la ejecutabilidad de los programas de dos entornos y
+// devuelve, ordenados por nombre, aquellos cuya situación cambia
+func (se *Sesion) Diferencias(a, b string) ([]DiferenciaPrograma, error) {
+	sistemaA, existe := se.entornos[a]
+	if !existe {
+		return nil, fmt.Errorf("ERROR: No existe un entorno con el nombre '%s'", a)
+	}
+	sistemaB, existe := se.entornos[b]
+	if !existe {
+		return nil, fmt.Errorf("ERROR: No existe un entorno con el nombre '%s'", b)
+	}
+
+	diferencias := make(map[string]*DiferenciaPrograma)
+	registrar := func(s *System, enA bool) {
+		for _, res := range s.EvaluarTodos().Programas {
+			d, ok := diferencias[res.Nombre]
+			if !ok {
+				d = &DiferenciaPrograma{Nombre: res.Nombre}
+				diferencias[res.Nombre] = d
+			}
+			if enA {
+				d.DefinidoA, d.EjecutableA = true, res.Ejecutable
+			} else {
+				d.DefinidoB, d.EjecutableB = true, res.Ejecutable
+			}
+		}
+	}
+	registrar(sistemaA, true)
+	registrar(sistemaB, false)
+
+	resultado := make([]DiferenciaPrograma, 0)
+	for _, d := range diferencias {
+		if d.EjecutableA != d.EjecutableB {
+			resultado = append(resultado, *d)
+		}
+	}
+	sort.Slice(resultado, func(i, j int) bool {
+		return resultado[i].Nombre < resultado[j].Nombre
+	})
+	return resultado, nil
+}
+
+// mostrarDiferencias imprime las diferencias entre dos entornos
+func (se *Sesion) mostrarDiferencias(a, b string) error {
+	diferencias, err := se.Diferencias(a, b)
+	if err != nil {
+		return err
+	}
+	if len(diferencias) == 0 {
+		fmt.Fprintf(se.salida, "No hay cambios de ejecutabilidad entre '%s' y '%s'\n", a, b)
+		return nil
+	}
+	for _, d := range diferencias {
+		nota := ""
+		if !d.DefinidoA {
+			nota = fmt.Sprintf(" (no definido en '%s')", a)
+		} else if !d.DefinidoB {
+			nota = fmt.Sprintf(" (no definido en '%s')", b)
+		}
+		if d.EjecutableB {
+			fmt.Fprintf(se.salida, "+ '%s' pasa a ser ejecutable%s\n", d.Nombre, nota)
+		} else {
+			fmt.Fprintf(se.salida, "- '%s' deja de ser ejecutable%s\n", d.Nombre, nota)
+		}
+	}
+	return nil
+}
+
+// procesarEntorno procesa los subcomandos de ENTORNO
+func (se *Sesion) procesarEntorno(partes []string) error {
+	if len(partes) < 2 {
+		return fmt.Errorf("ERROR: Comando ENTORNO incompleto")
+	}
+
+	subcomando := strings.ToUpper(partes[1])
+	switch subcomando {
+	case "NUEVO":
+		if len(partes) != 3 {
+			return fmt.Errorf("ERROR: ENTORNO NUEVO requiere <nombre>")
+		}
+		return se.NuevoEntorno(partes[2])
+
+	case "USAR":
+		if len(partes) != 3 {
+			return fmt.Errorf("ERROR: ENTORNO USAR requiere <nombre>")
+		}
+		return se.UsarEntorno(partes[2])
+
+	case "COPIAR":
+		if len(partes) != 4 {
+			return fmt.Errorf("ERROR: ENTORNO COPIAR requiere <origen> <destino>")
+		}
+		return se.CopiarEntorno(partes[2], partes[3])
+
+	case "DIFF":
+		if len(partes) != 4 {
+			return fmt.Errorf("ERROR: ENTORNO DIFF requiere <a> <b>")
+		}
+		return se.mostrarDiferencias(partes[2], partes[3])
+
+	default:
+		return fmt.Errorf("ERROR: Subcomando de ENTORNO desconocido '%s'", subcomando)
+	}
+}
+
+// ProcesarComando procesa un comando del usuario. Los comandos ENTORNO se
+// atienden en la sesión y el resto se delega al entorno en uso.
+func (se *Sesion) ProcesarComando(comando string) bool {
+	partes := strings.Fields(comando)
+	if len(partes) > 0 && strings.ToUpper(partes[0]) == "ENTORNO" {
+		if err := se.procesarEntorno(partes); err != nil {
+			fmt.Fprintln(se.salida, err)
+		}
+		return true
+	}
+	return se.Actual().ProcesarComando(comando)
+}
+
+// ProcesarEntrada procesa todos los comandos de un lector, uno por línea
+func (se *Sesion) ProcesarEntrada(r io.Reader) error {
+	return procesarLineas(r, se.ProcesarComando)
+}
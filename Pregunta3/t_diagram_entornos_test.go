@@ -0,0 +1,131 @@
+package tdiagram
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+// nuevaSesionSilenciosa crea una sesión que descarta sus mensajes
+func nuevaSesionSilenciosa() *Sesion {
+	se := NuevaSesion()
+	se.RedirigirSalida(io.Discard)
+	return se
+}
+
+// TestSesionEntornoInicial verifica que la sesión arranca en "principal"
+func TestSesionEntornoInicial(t *testing.T) {
+	se := nuevaSesionSilenciosa()
+
+	if se.actual != entornoInicial || se.Actual() == nil {
+		t.Errorf("La sesión debería arrancar en el entorno '%s'", entornoInicial)
+	}
+}
+
+// TestEntornosIndependientes verifica que cada entorno tiene su propio estado
+func TestEntornosIndependientes(t *testing.T) {
+	se := nuevaSesionSilenciosa()
+	se.ProcesarComando("DEFINIR PROGRAMA p Java")
+	se.ProcesarComando("ENTORNO NUEVO otro")
+	se.ProcesarComando("DEFINIR PROGRAMA q C")
+
+	if _, existe := se.Actual().programas["p"]; existe {
+		t.Error("El programa 'p' no debería existir en el entorno 'otro'")
+	}
+	se.ProcesarComando("ENTORNO USAR principal")
+	if _, existe := se.Actual().programas["q"]; existe {
+		t.Error("El programa 'q' no debería existir en el entorno 'principal'")
+	}
+}
+
+// TestEntornoDuplicado verifica que no se puede crear un entorno existente
+func TestEntornoDuplicado(t *testing.T) {
+	se := nuevaSesionSilenciosa()
+
+	if err := se.NuevoEntorno(entornoInicial); err == nil {
+		t.Error("Debería dar error al crear un entorno duplicado")
+	}
+	if err := se.UsarEntorno("noexiste"); err == nil {
+		t.Error("Debería dar error al usar un entorno inexistente")
+	}
+}
+
+// TestCopiarEntorno verifica que la copia no comparte estado con el original
+func TestCopiarEntorno(t *testing.T) {
+	se := nuevaSesionSilenciosa()
+	se.ProcesarComando("DEFINIR PROGRAMA p Java")
+	se.ProcesarComando("DEFINIR INTERPRETE LOCAL C")
+
+	if err := se.CopiarEntorno(entornoInicial, "copia"); err != nil {
+		t.Fatalf("No debería dar error: %v", err)
+	}
+	se.entornos["copia"].DefinirInterprete("C", "Java")
+	se.entornos["copia"].DefinirPrograma("q", "C")
+
+	original := se.entornos[entornoInicial]
+	if len(original.interpretes) != 1 || len(original.programas) != 1 {
+		t.Error("Modificar la copia no debería afectar al entorno original")
+	}
+	if err := se.CopiarEntorno("noexiste", "otra"); err == nil {
+		t.Error("Debería dar error al copiar un entorno inexistente")
+	}
+}
+
+// TestDiferenciasEntornos verifica los programas que ganan o pierden
+// ejecutabilidad entre dos configuraciones
+func TestDiferenciasEntornos(t *testing.T) {
+	se := nuevaSesionSilenciosa()
+	se.ProcesarComando("DEFINIR PROGRAMA viejo Cobol")
+	se.ProcesarComando("DEFINIR PROGRAMA nuevo Go")
+	se.ProcesarComando("DEFINIR PROGRAMA fijo LOCAL")
+	se.ProcesarComando("DEFINIR INTERPRETE LOCAL Cobol")
+	se.ProcesarComando("ENTORNO NUEVO migrado")
+	se.ProcesarComando("DEFINIR PROGRAMA viejo Cobol")
+	se.ProcesarComando("DEFINIR PROGRAMA nuevo Go")
+	se.ProcesarComando("DEFINIR PROGRAMA fijo LOCAL")
+	se.ProcesarComando("DEFINIR INTERPRETE LOCAL Go")
+
+	diferencias, err := se.Diferencias(entornoInicial, "migrado")
+	if err != nil {
+		t.Fatalf("No debería dar error: %v", err)
+	}
+	if len(diferencias) != 2 {
+		t.Fatalf("Se esperaban 2 diferencias, se obtuvo %+v", diferencias)
+	}
+	if diferencias[0].Nombre != "nuevo" || !diferencias[0].EjecutableB {
+		t.Errorf("'nuevo' debería pasar a ser ejecutable: %+v", diferencias[0])
+	}
+	if diferencias[1].Nombre != "viejo" || diferencias[1].EjecutableB {
+		t.Errorf("'viejo' debería dejar de ser ejecutable: %+v", diferencias[1])
+	}
+}
+
+// TestDiferenciasProgramaNoDefinido verifica que un programa ausente en un
+// entorno se considera no ejecutable en él
+func TestDiferenciasProgramaNoDefinido(t *testing.T) {
+	se := nuevaSesionSilenciosa()
+	se.ProcesarComando("ENTORNO COPIAR principal b")
+	se.ProcesarComando("ENTORNO USAR b")
+	se.ProcesarComando("DEFINIR PROGRAMA p LOCAL")
+
+	var buf bytes.Buffer
+	se.RedirigirSalida(&buf)
+	se.ProcesarComando("ENTORNO DIFF principal b")
+
+	if !strings.Contains(buf.String(), "+ 'p' pasa a ser ejecutable (no definido en 'principal')") {
+		t.Errorf("Salida inesperada: %q", buf.String())
+	}
+}
+
+// TestComandoEntornoInvalido verifica los errores de sintaxis de ENTORNO
+func TestComandoEntornoInvalido(t *testing.T) {
+	se := nuevaSesionSilenciosa()
+	comandos := []string{"ENTORNO", "ENTORNO NUEVO", "ENTORNO DIFF a", "ENTORNO BORRAR a"}
+
+	for _, comando := range comandos {
+		if err := se.procesarEntorno(strings.Fields(comando)); err == nil {
+			t.Errorf("'%s' debería dar error", comando)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package tdiagram
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// formatoMomento es el formato con que se muestran los momentos del historial
+const formatoMomento = "2006-01-02 15:04:05"
+
+// Evento registra una definición hecha en el sistema: cuándo ocurrió, el
+// comando que la reproduce y los programas cuya ejecutabilidad cambió
+type Evento struct {
+	Numero  int            `json:"numero"`
+	Momento time.Time      `json:"momento"`
+	Comando string         `json:"comando"`
+	Cambios []CambioEvento `json:"cambios"`
+}
+
+// CambioEvento indica que un programa pasó a ser (o dejó de ser) ejecutable
+type CambioEvento struct {
+	Programa   string `json:"programa"`
+	Ejecutable bool   `json:"ejecutable"`
+}
+
+// comando devuelve el comando DEFINIR que define este programa
+func (p Program) comando() string {
+	return fmt.Sprintf("DEFINIR PROGRAMA %s %s", p.Name, p.Language)
+}
+
+// comando devuelve el comando DEFINIR que define este intérprete, con sus opciones
+func (i Interpreter) comando() string {
+	partes := []string{"DEFINIR", "INTERPRETE", i.Base, i.Language}
+	return strings.Join(append(partes, i.metadatos.opciones()...), " ")
+}
+
+// comando devuelve el comando DEFINIR que define este traductor, con sus opciones
+func (t Translator) comando() string {
+	partes := []string{"DEFINIR", "TRADUCTOR", t.Base, t.Source, t.Target}
+	return strings.Join(append(partes, t.metadatos.opciones()...), " ")
+}
+
+// opciones devuelve los metadatos como opciones de DEFINIR, de modo que
+// analizarOpciones las convierta de nuevo en los mismos metadatos
+func (m Metadatos) opciones() []string {
+	opciones := make([]string, 0)
+	if !m.desde.IsZero() {
+		opciones = append(opciones, "DESDE", m.desde.Format(formatoFecha))
+	}
+	if !m.hasta.IsZero() {
+		opciones = append(opciones, "HASTA", m.hasta.Format(formatoFecha))
+	}
+	if m.noConfiable {
+		opciones = append(opciones, "NOCONFIABLE")
+	}
+	if m.procedencia != "" {
+		opciones = append(opciones, "PROCEDENCIA", m.procedencia)
+	}
+	if m.version != "" {
+		opciones = append(opciones, "VERSION", m.version)
+	}
+	if m.costoDeclarado {
+		opciones = append(opciones, "COSTO", strconv.FormatFloat(m.costo, 'g', -1, 64))
+	}
+	if len(m.caracteristicas) > 0 {
+		opciones = append(opciones, "CARACTERISTICAS", strings.Join(m.caracteristicas, ","))
+	}
+	return opciones
+}
+
+// ejecutables indica, para cada programa, si puede ejecutarse
+func (s *System) ejecutables() map[string]bool {
+	derivados := s.derivaciones()
+	estado := make(map[string]bool, len(s.programas))
+	for nombre, programa := range s.programas {
+		_, estado[nombre] = derivados[programa.Language]
+	}
+	return estado
+}
+
+// registrarEvento aplica una definición y agrega al historial el comando que
+// la reproduce junto con los cambios de ejecutabilidad que provocó
+func (s *System) registrarEvento(comando string, aplicar func()) {
+	antes := s.ejecutables()
+	aplicar()
+	despues := s.ejecutables()
+
+	nombres := make([]string, 0)
+	for nombre, ejecutable := range despues {
+		if ejecutable != antes[nombre] {
+			nombres = append(nombres, nombre)
+		}
+	}
+	sort.Strings(nombres)
+
+	cambios := make([]CambioEvento, 0, len(nombres))
+	for _, nombre := range nombres {
+		cambios = append(cambios, CambioEvento{Programa: nombre, Ejecutable: despues[nombre]})
+	}
+	s.eventos = append(s.eventos, Evento{
+		Numero:  len(s.eventos) + 1,
+		Momento: s.reloj(),
+		Comando: comando,
+		Cambios: cambios,
+	})
+}
+
+// largoMaximoEvento es el mayor tamaño de una línea del historial que
+// acepta ReproducirHistorial. Un evento que hace ejecutables a muchos
+// programas a la vez supera fácilmente el límite por omisión de bufio.Scanner.
+const largoMaximoEvento = 64 << 20
+
+// copia devuelve el evento con su propia copia de los cambios
+func (e Evento) copia() Evento {
+	e.Cambios = append(make([]CambioEvento, 0, len(e.Cambios)), e.Cambios...)
+	return e
+}
+
+// Historial devuelve todos los eventos registrados, del más antiguo al más reciente
+func (s *System) Historial() []Evento {
+	eventos := make([]Evento, 0, len(s.eventos))
+	for _, e := range s.eventos {
+		eventos = append(eventos, e.copia())
+	}
+	return eventos
+}
+
+// HistorialDe devuelve los eventos que cambiaron la ejecutabilidad de un
+// programa, lo que permite ubicar en qué definición dejó de ser ejecutable
+func (s *System) HistorialDe(nombre string) ([]Evento, error) {
+	if _, existe := s.programas[nombre]; !existe {
+		return nil, fmt.Errorf("ERROR: No existe un programa con el nombre '%s'", nombre)
+	}
+	eventos := make([]Evento, 0)
+	for _, e := range s.eventos {
+		for _, c := range e.Cambios {
+			if c.Programa == nombre {
+				eventos = append(eventos, e.copia())
+			}
+		}
+	}
+	return eventos, nil
+}
+
+// ExportarHistorial escribe el historial en formato JSON Lines, un evento por línea
+func (s *System) ExportarHistorial(w io.Writer) error {
+	codificador := json.NewEncoder(w)
+	for _, e := range s.eventos {
+		if err := codificador.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReproducirHistorial aplica, en orden, los eventos de un historial en JSON
+// Lines. Solo puede usarse en un sistema vacío; cada evento conserva su
+// momento original y debe producir los mismos cambios que se registraron.
+// Devuelve la cantidad de eventos reproducidos; si uno falla, los anteriores
+// quedan aplicados.
+func (s *System) ReproducirHistorial(r io.Reader) (int, error) {
+	if len(s.eventos) > 0 || len(s.programas) > 0 || len(s.interpretes) > 0 || len(s.traductores) > 0 {
+		return 0, fmt.Errorf("ERROR: El historial solo puede reproducirse en un entorno vacío")
+	}
+
+	reloj, salida := s.reloj, s.salida
+	defer func() { s.reloj, s.salida = reloj, salida }()
+	var mensajes bytes.Buffer
+	s.salida = &mensajes
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, largoMaximoEvento)
+	linea, reproducidos := 0, 0
+	for scanner.Scan() {
+		linea++
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var e Evento
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return reproducidos, fmt.Errorf("ERROR: Línea %d del historial inválida: %v", linea, err)
+		}
+		partes := strings.Fields(e.Comando)
+		if len(partes) == 0 || strings.ToUpper(partes[0]) != "DEFINIR" {
+			return reproducidos, fmt.Errorf("ERROR: El evento %d no es una definición: '%s'", e.Numero, e.Comando)
+		}
+
+		s.reloj = func() time.Time { return e.Momento }
+		mensajes.Reset()
+		cantidad := len(s.eventos)
+		s.ProcesarComando(e.Comando)
+		if len(s.eventos) == cantidad {
+			return reproducidos, fmt.Errorf("ERROR: El evento %d no pudo reproducirse: %s",
+				e.Numero, strings.TrimSpace(mensajes.String()))
+		}
+		if !mismosCambios(e.Cambios, s.eventos[cantidad].Cambios) {
+			return reproducidos, fmt.Errorf("ERROR: El evento %d produjo cambios de ejecutabilidad distintos a los registrados", e.Numero)
+		}
+		reproducidos++
+	}
+	return reproducidos, scanner.Err()
+}
+
+// mismosCambios compara dos listas de cambios ordenadas por programa
+func mismosCambios(a, b []CambioEvento) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// mostrarHistorial imprime los eventos con sus cambios de ejecutabilidad
+func (s *System) mostrarHistorial(eventos []Evento) {
+	for _, e := range eventos {
+		fmt.Fprintf(s.salida, "#%d %s %s\n", e.Numero, e.Momento.Format(formatoMomento), e.Comando)
+		for _, c := range e.Cambios {
+			if c.Ejecutable {
+				fmt.Fprintf(s.salida, "    + '%s' pasa a ser ejecutable\n", c.Programa)
+			} else {
+				fmt.Fprintf(s.salida, "    - '%s' deja de ser ejecutable\n", c.Programa)
+			}
+		}
+	}
+}
+
+// procesarHistorial atiende el comando HISTORIAL:
+//
+//	HISTORIAL                        muestra todos los eventos
+//	HISTORIAL <programa>             muestra los eventos que afectaron al programa
+//	HISTORIAL EXPORTAR <archivo>     guarda el historial en JSON Lines
+//	HISTORIAL REPRODUCIR <archivo>   reconstruye el entorno (vacío) desde un historial
+func (s *System) procesarHistorial(argumentos []string) error {
+	switch {
+	case len(argumentos) == 0:
+		if len(s.eventos) == 0 {
+			fmt.Fprintln(s.salida, "El historial está vacío")
+			return nil
+		}
+		s.mostrarHistorial(s.eventos)
+		return nil
+
+	case len(argumentos) == 1:
+		eventos, err := s.HistorialDe(argumentos[0])
+		if err != nil {
+			return err
+		}
+		if len(eventos) == 0 {
+			fmt.Fprintf(s.salida, "Ningún evento cambió la ejecutabilidad de '%s'\n", argumentos[0])
+			return nil
+		}
+		s.mostrarHistorial(eventos)
+		return nil
+
+	case len(argumentos) == 2 && strings.ToUpper(argumentos[0]) == "EXPORTAR":
+		f, err := os.Create(argumentos[1])
+		if err != nil {
+			return fmt.Errorf("ERROR: No se pudo crear '%s': %v", argumentos[1], err)
+		}
+		if err := s.ExportarHistorial(f); err != nil {
+			f.Close()
+			return fmt.Errorf("ERROR: No se pudo escribir '%s': %v", argumentos[1], err)
+		}
+		if err := f.Close(); err != nil {
+			return fmt.Errorf("ERROR: No se pudo escribir '%s': %v", argumentos[1], err)
+		}
+		fmt.Fprintf(s.salida, "Se exportaron %d eventos a '%s'\n", len(s.eventos), argumentos[1])
+		return nil
+
+	case len(argumentos) == 2 && strings.ToUpper(argumentos[0]) == "REPRODUCIR":
+		f, err := os.Open(argumentos[1])
+		if err != nil {
+			return fmt.Errorf("ERROR: No se pudo abrir '%s': %v", argumentos[1], err)
+		}
+		defer f.Close()
+		reproducidos, err := s.ReproducirHistorial(f)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(s.salida, "Se reprodujeron %d eventos de '%s'\n", reproducidos, argumentos[1])
+		return nil
+	}
+	return fmt.Errorf("ERROR: HISTORIAL requiere [<programa> | EXPORTAR <archivo> | REPRODUCIR <archivo>]")
+}
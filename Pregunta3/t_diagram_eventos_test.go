@@ -0,0 +1,262 @@
+package tdiagram
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sistemaConReloj crea un sistema silencioso cuyo reloj avanza un minuto en
+// cada evento, para que los momentos registrados sean predecibles
+func sistemaConReloj() *System {
+	s := NuevoSistema()
+	s.salida = io.Discard
+	momento := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	s.reloj = func() time.Time {
+		momento = momento.Add(time.Minute)
+		return momento
+	}
+	return s
+}
+
+// TestEventosRegistranCambios verifica el comando y los cambios de cada evento
+func TestEventosRegistranCambios(t *testing.T) {
+	s := sistemaConReloj()
+	s.ProcesarComando("DEFINIR PROGRAMA servidor Go")
+	s.ProcesarComando("DEFINIR PROGRAMA script LOCAL")
+	s.ProcesarComando("DEFINIR INTERPRETE LOCAL Go")
+	s.ProcesarComando("DEFINIR PROGRAMA servidor Go")
+	s.ProcesarComando("EJECUTABLE servidor")
+
+	eventos := s.Historial()
+	if len(eventos) != 3 {
+		t.Fatalf("Solo las definiciones exitosas deberían registrarse: %+v", eventos)
+	}
+	if len(eventos[0].Cambios) != 0 {
+		t.Errorf("Definir un programa no ejecutable no cambia nada: %+v", eventos[0])
+	}
+	if !reflect.DeepEqual(eventos[1].Cambios, []CambioEvento{{Programa: "script", Ejecutable: true}}) {
+		t.Errorf("Un programa en LOCAL es ejecutable al definirse: %+v", eventos[1])
+	}
+	if eventos[2].Comando != "DEFINIR INTERPRETE LOCAL Go" || eventos[2].Numero != 3 ||
+		!reflect.DeepEqual(eventos[2].Cambios, []CambioEvento{{Programa: "servidor", Ejecutable: true}}) {
+		t.Errorf("Evento inesperado: %+v", eventos[2])
+	}
+	if !eventos[2].Momento.Equal(time.Date(2024, 3, 1, 9, 3, 0, 0, time.UTC)) {
+		t.Errorf("Momento inesperado: %v", eventos[2].Momento)
+	}
+}
+
+// TestComandoCanonico verifica que el comando registrado reproduce los mismos
+// metadatos que se definieron
+func TestComandoCanonico(t *testing.T) {
+	s := sistemaConReloj()
+	s.ProcesarComando("DEFINIR TRADUCTOR LOCAL C LOCAL costo 2.5 NOCONFIABLE DESDE 2024-01-01 " +
+		"CARACTERISTICAS optimizador,depurador VERSION 11.2 PROCEDENCIA externo")
+
+	esperado := "DEFINIR TRADUCTOR LOCAL C LOCAL DESDE 2024-01-01 NOCONFIABLE PROCEDENCIA externo " +
+		"VERSION 11.2 COSTO 2.5 CARACTERISTICAS optimizador,depurador"
+	if comando := s.Historial()[0].Comando; comando != esperado {
+		t.Fatalf("Comando inesperado:\n%s\nse esperaba:\n%s", comando, esperado)
+	}
+
+	metadatos, err := analizarOpciones(strings.Fields(esperado)[5:])
+	if err != nil || !reflect.DeepEqual(metadatos, s.traductores[0].metadatos) {
+		t.Errorf("Las opciones no reproducen los metadatos: %+v %v", metadatos, err)
+	}
+	if _, err := analizarOpciones([]string{"COSTO", "-1"}); err == nil {
+		t.Error("Un costo negativo debería dar error")
+	}
+}
+
+// TestExportarYReproducir verifica que reproducir el historial exportado
+// reconstruye el mismo estado
+func TestExportarYReproducir(t *testing.T) {
+	s := sistemaConReloj()
+	s.ProcesarComando("DEFINIR PROGRAMA login C")
+	s.ProcesarComando("DEFINIR INTERPRETE LOCAL Python HASTA 2025-01-01")
+	s.ProcesarComando("DEFINIR INTERPRETE Python C NOCONFIABLE COSTO 3")
+	s.ProcesarComando("DEFINIR PROGRAMA calculadora Python")
+
+	var buf bytes.Buffer
+	if err := s.ExportarHistorial(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if lineas := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lineas) != 4 {
+		t.Fatalf("Se esperaba un evento por línea: %q", buf.String())
+	}
+
+	copia := NuevoSistema()
+	copia.salida = io.Discard
+	reproducidos, err := copia.ReproducirHistorial(&buf)
+	if err != nil || reproducidos != 4 {
+		t.Fatalf("Reproducción fallida (%d eventos): %v", reproducidos, err)
+	}
+	if !reflect.DeepEqual(copia.EvaluarTodos(), s.EvaluarTodos()) {
+		t.Error("La reproducción debería reconstruir el mismo estado")
+	}
+	if !reflect.DeepEqual(copia.interpretes, s.interpretes) {
+		t.Errorf("Los metadatos deberían conservarse: %+v", copia.interpretes)
+	}
+	for i, e := range copia.Historial() {
+		if !e.Momento.Equal(s.Historial()[i].Momento) {
+			t.Errorf("El evento %d debería conservar su momento original", e.Numero)
+		}
+	}
+}
+
+// TestReproducirErrores verifica los historiales que no pueden reproducirse
+func TestReproducirErrores(t *testing.T) {
+	casos := []struct {
+		historial, mensaje string
+	}{
+		{"{\"numero\": 1, \"comando\": \"DEFINIR PROGRAMA p C\"}\n{no es json", "Línea 2"},
+		{`{"numero": 1, "comando": "IMPORTAR otro.toml"}`, "no es una definición"},
+		{"{\"numero\": 1, \"comando\": \"DEFINIR PROGRAMA p C\"}\n{\"numero\": 2, \"comando\": \"DEFINIR PROGRAMA p Go\"}",
+			"Ya existe un programa"},
+		{`{"numero": 1, "comando": "DEFINIR PROGRAMA p LOCAL", "cambios": []}`, "cambios de ejecutabilidad distintos"},
+	}
+	for _, c := range casos {
+		s := sistemaConReloj()
+		if _, err := s.ReproducirHistorial(strings.NewReader(c.historial)); err == nil ||
+			!strings.Contains(err.Error(), c.mensaje) {
+			t.Errorf("Se esperaba un error con %q, se obtuvo: %v", c.mensaje, err)
+		}
+	}
+
+	s := sistemaConReloj()
+	s.ProcesarComando("DEFINIR PROGRAMA p C")
+	if _, err := s.ReproducirHistorial(strings.NewReader("")); err == nil {
+		t.Error("No debería poder reproducirse en un entorno con definiciones")
+	}
+}
+
+// TestHistorialDePrograma verifica la búsqueda de los eventos que afectaron
+// a un programa
+func TestHistorialDePrograma(t *testing.T) {
+	s := sistemaConReloj()
+	s.ProcesarComando("DEFINIR PROGRAMA servidor Go")
+	s.ProcesarComando("DEFINIR INTERPRETE LOCAL Java")
+	s.ProcesarComando("DEFINIR INTERPRETE Java Go")
+
+	eventos, err := s.HistorialDe("servidor")
+	if err != nil || len(eventos) != 1 || eventos[0].Numero != 3 {
+		t.Errorf("Solo el evento 3 hizo ejecutable a 'servidor': %+v %v", eventos, err)
+	}
+	if _, err := s.HistorialDe("noexiste"); err == nil {
+		t.Error("Debería dar error con un programa inexistente")
+	}
+}
+
+// TestHistorialEsCopia verifica que modificar el historial devuelto no
+// altera el registro que usa la reproducción
+func TestHistorialEsCopia(t *testing.T) {
+	s := sistemaConReloj()
+	s.ProcesarComando("DEFINIR PROGRAMA servidor Go")
+
+	s.ProcesarComando("DEFINIR INTERPRETE LOCAL Go")
+
+	historial := s.Historial()
+	historial[0].Numero = 99
+	historial[1].Cambios[0].Ejecutable = false
+	if s.Historial()[0].Numero != 1 || !s.Historial()[1].Cambios[0].Ejecutable {
+		t.Error("Historial debería devolver una copia de los eventos y sus cambios")
+	}
+}
+
+// TestReproducirEventoGrande verifica que se reproduce un evento cuya línea
+// supera los 64 KB por omisión de bufio.Scanner
+func TestReproducirEventoGrande(t *testing.T) {
+	s := sistemaConReloj()
+	for i := 0; i < 1200; i++ {
+		s.ProcesarComando(fmt.Sprintf("DEFINIR PROGRAMA programa-con-un-nombre-largo-%04d Go", i))
+	}
+	s.ProcesarComando("DEFINIR INTERPRETE LOCAL Go")
+
+	var buf bytes.Buffer
+	if err := s.ExportarHistorial(&buf); err != nil {
+		t.Fatal(err)
+	}
+	copia := NuevoSistema()
+	copia.salida = io.Discard
+	if reproducidos, err := copia.ReproducirHistorial(&buf); err != nil || reproducidos != 1201 {
+		t.Errorf("Reproducción fallida (%d eventos): %v", reproducidos, err)
+	}
+}
+
+// TestImportarRegistraEventos verifica que cada entrada importada queda en
+// el historial como un comando DEFINIR
+func TestImportarRegistraEventos(t *testing.T) {
+	manifiesto := `[[programa]]
+nombre = "app"
+lenguaje = "Go"
+
+[[interprete]]
+base = "LOCAL"
+lenguaje = "Go"
+caracteristicas = ["gc", "con espacios"]
+
+[[interprete]]
+base = "LOCAL"
+lenguaje = "Go"
+version = "1.22"
+`
+	s, resultado := importar(t, "m.toml", manifiesto)
+
+	eventos := s.Historial()
+	if resultado.Interpretes != 1 || len(eventos) != 2 {
+		t.Fatalf("Se esperaban 2 eventos: %+v %+v", resultado, eventos)
+	}
+	if eventos[1].Comando != "DEFINIR INTERPRETE LOCAL Go VERSION 1.22" || len(eventos[1].Cambios) != 1 {
+		t.Errorf("Evento inesperado: %+v", eventos[1])
+	}
+	if p, ok := buscarProblema(resultado, "sin espacios ni comas"); !ok || p.Linea != 8 {
+		t.Errorf("Se esperaba una característica inválida en la línea 8: %v", resultado.Problemas)
+	}
+}
+
+// TestComandosHistorial verifica la salida de HISTORIAL y sus subcomandos
+func TestComandosHistorial(t *testing.T) {
+	s := sistemaConReloj()
+	var buf bytes.Buffer
+	s.salida = &buf
+
+	s.ProcesarComando("HISTORIAL")
+	if buf.String() != "El historial está vacío\n" {
+		t.Errorf("Salida inesperada: %q", buf.String())
+	}
+
+	s.ProcesarComando("DEFINIR PROGRAMA servidor Go")
+	s.ProcesarComando("DEFINIR INTERPRETE LOCAL Go")
+	buf.Reset()
+	s.ProcesarComando("HISTORIAL servidor")
+	esperado := "#2 2024-03-01 09:02:00 DEFINIR INTERPRETE LOCAL Go\n    + 'servidor' pasa a ser ejecutable\n"
+	if buf.String() != esperado {
+		t.Errorf("Salida inesperada: %q", buf.String())
+	}
+
+	archivo := filepath.Join(t.TempDir(), "historial.jsonl")
+	buf.Reset()
+	s.ProcesarComando("HISTORIAL EXPORTAR " + archivo)
+	if !strings.HasPrefix(buf.String(), "Se exportaron 2 eventos") {
+		t.Errorf("Salida inesperada: %q", buf.String())
+	}
+
+	se := nuevaSesionSilenciosa()
+	se.ProcesarComando("ENTORNO NUEVO reconstruido")
+	se.ProcesarComando("HISTORIAL REPRODUCIR " + archivo)
+	if ok, _, _ := se.Actual().EsEjecutable("servidor"); !ok {
+		t.Error("El entorno reconstruido debería poder ejecutar 'servidor'")
+	}
+
+	buf.Reset()
+	s.ProcesarComando("HISTORIAL REPRODUCIR " + archivo)
+	if !strings.HasPrefix(buf.String(), "ERROR") {
+		t.Errorf("Se esperaba un error al reproducir en un entorno con definiciones: %q", buf.String())
+	}
+}
@@ -0,0 +1,820 @@
+package tdiagram
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Un manifiesto declara programas, intérpretes y traductores en TOML o en
+// JSON. En TOML cada elemento es una tabla de un arreglo:
+//
+//	[[interprete]]
+//	base = "LOCAL"
+//	lenguaje = "C"
+//	version = "11.2"
+//	costo = 3
+//	caracteristicas = ["optimizador", "depurador"]
+//	desde = 2024-01-01
+//
+// En JSON se usan las mismas secciones como arreglos de objetos:
+//
+//	{"interprete": [{"base": "LOCAL", "lenguaje": "C", "costo": 3}]}
+//
+// Del formato TOML solo se admite este subconjunto: arreglos de tablas,
+// cadenas, números, booleanos, fechas locales (AAAA-MM-DD, sin hora) y
+// arreglos en una sola línea. Las fechas también pueden escribirse como
+// cadenas, que es la única forma posible en JSON.
+
+// camposManifiesto indica, para cada sección, los campos admitidos y su tipo
+var camposManifiesto = map[string]map[string]string{
+	"programa": {
+		"nombre":   "texto",
+		"lenguaje": "texto",
+	},
+	"interprete": {
+		"base":            "texto",
+		"lenguaje":        "texto",
+		"version":         "texto",
+		"costo":           "numero",
+		"caracteristicas": "lista",
+		"desde":           "fecha",
+		"hasta":           "fecha",
+		"confiable":       "booleano",
+		"procedencia":     "texto",
+	},
+	"traductor": {
+		"base":            "texto",
+		"origen":          "texto",
+		"destino":         "texto",
+		"version":         "texto",
+		"costo":           "numero",
+		"caracteristicas": "lista",
+		"desde":           "fecha",
+		"hasta":           "fecha",
+		"confiable":       "booleano",
+		"procedencia":     "texto",
+	},
+}
+
+// camposObligatorios indica los campos que toda entrada de la sección debe tener
+var camposObligatorios = map[string][]string{
+	"programa":   {"nombre", "lenguaje"},
+	"interprete": {"base", "lenguaje"},
+	"traductor":  {"base", "origen", "destino"},
+}
+
+// posicion identifica una ubicación (línea y columna, desde 1) en un manifiesto
+type posicion struct {
+	linea, columna int
+}
+
+// ProblemaImportacion describe un error, un conflicto o un campo desconocido
+// encontrado al importar un manifiesto
+type ProblemaImportacion struct {
+	Archivo string
+	Linea   int
+	Columna int
+	Mensaje string
+}
+
+// String devuelve el problema con el formato archivo:línea:columna: mensaje
+func (p ProblemaImportacion) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", p.Archivo, p.Linea, p.Columna, p.Mensaje)
+}
+
+// ResultadoImportacion resume lo que se agregó al sistema y los problemas
+// encontrados. Las entradas con errores o conflictos no se importan; los
+// campos desconocidos se reportan pero no impiden importar la entrada.
+type ResultadoImportacion struct {
+	Programas   int
+	Interpretes int
+	Traductores int
+	Problemas   []ProblemaImportacion
+}
+
+// valorManifiesto es un valor leído del manifiesto junto con su ubicación.
+// dato es string, float64, bool, fechaTOML o []interface{}.
+type valorManifiesto struct {
+	dato interface{}
+	pos  posicion
+}
+
+// fechaTOML es una fecha local de TOML escrita sin comillas. La validación
+// la convierte en string, como las fechas escritas entre comillas.
+type fechaTOML string
+
+// entradaManifiesto es un elemento de una sección del manifiesto
+type entradaManifiesto struct {
+	seccion string
+	pos     posicion
+	campos  map[string]valorManifiesto
+	claves  []string // orden de aparición de los campos
+}
+
+// lectorManifiesto acumula las entradas y los problemas durante el análisis
+type lectorManifiesto struct {
+	entradas  []*entradaManifiesto
+	problemas []ProblemaImportacion
+}
+
+func (l *lectorManifiesto) problema(pos posicion, formato string, args ...interface{}) {
+	l.problemas = append(l.problemas, ProblemaImportacion{
+		Linea:   pos.linea,
+		Columna: pos.columna,
+		Mensaje: fmt.Sprintf(formato, args...),
+	})
+}
+
+// agregarCampo registra un campo en la entrada, reportando los duplicados
+func (l *lectorManifiesto) agregarCampo(e *entradaManifiesto, clave string, v valorManifiesto, posClave posicion) {
+	if anterior, existe := e.campos[clave]; existe {
+		l.problema(posClave, "campo '%s' duplicado (definido antes en la línea %d)", clave, anterior.pos.linea)
+		return
+	}
+	e.campos[clave] = v
+	e.claves = append(e.claves, clave)
+}
+
+// ImportarArchivo importa un manifiesto desde un archivo e informa el
+// resultado en la salida del sistema
+func (s *System) ImportarArchivo(ruta string) error {
+	f, err := os.Open(ruta)
+	if err != nil {
+		return fmt.Errorf("ERROR: No se pudo abrir '%s': %v", ruta, err)
+	}
+	defer f.Close()
+
+	resultado, err := s.ImportarManifiesto(ruta, f)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(s.salida, "Se importaron %d programas, %d intérpretes y %d traductores desde '%s'\n",
+		resultado.Programas, resultado.Interpretes, resultado.Traductores, ruta)
+	for _, p := range resultado.Problemas {
+		fmt.Fprintln(s.salida, p)
+	}
+	return nil
+}
+
+// ImportarManifiesto lee un manifiesto y agrega sus definiciones al sistema.
+// El formato se deduce de la extensión de nombre (.toml o .json). Solo se
+// devuelve error si no se puede leer la entrada o el formato es desconocido;
+// el resto de los problemas se informan en el resultado.
+func (s *System) ImportarManifiesto(nombre string, r io.Reader) (ResultadoImportacion, error) {
+	datos, err := io.ReadAll(r)
+	if err != nil {
+		return ResultadoImportacion{}, fmt.Errorf("ERROR: No se pudo leer '%s': %v", nombre, err)
+	}
+
+	lector := &lectorManifiesto{}
+	switch strings.ToLower(filepath.Ext(nombre)) {
+	case ".toml":
+		lector.analizarTOML(datos)
+	case ".json":
+		lector.analizarJSON(datos)
+	default:
+		return ResultadoImportacion{}, fmt.Errorf("ERROR: Formato de manifiesto desconocido '%s'", nombre)
+	}
+
+	resultado := s.aplicarManifiesto(lector)
+	for i := range resultado.Problemas {
+		resultado.Problemas[i].Archivo = nombre
+	}
+	sort.SliceStable(resultado.Problemas, func(i, j int) bool {
+		a, b := resultado.Problemas[i], resultado.Problemas[j]
+		if a.Linea != b.Linea {
+			return a.Linea < b.Linea
+		}
+		return a.Columna < b.Columna
+	})
+	return resultado, nil
+}
+
+// aplicarManifiesto valida las entradas leídas y agrega al sistema las que
+// no tienen errores ni conflictos
+func (s *System) aplicarManifiesto(lector *lectorManifiesto) ResultadoImportacion {
+	// Ubicación de las definiciones hechas por este mismo manifiesto, para
+	// poder señalar dónde estaba la definición con la que se choca
+	definidos := make(map[string]posicion)
+	resultado := ResultadoImportacion{}
+
+	for _, e := range lector.entradas {
+		if !lector.validarEntrada(e) {
+			continue
+		}
+
+		texto := func(campo string) string {
+			if v, ok := e.campos[campo]; ok {
+				return v.dato.(string)
+			}
+			return ""
+		}
+
+		var clave, descripcion string
+		var existe bool
+		switch e.seccion {
+		case "programa":
+			clave = "programa " + texto("nombre")
+			descripcion = fmt.Sprintf("un programa con el nombre '%s'", texto("nombre"))
+			_, existe = s.programas[texto("nombre")]
+		case "interprete":
+			clave = "interprete " + texto("base") + " " + texto("lenguaje")
+			descripcion = fmt.Sprintf("un intérprete para '%s' escrito en '%s'", texto("lenguaje"), texto("base"))
+			for _, interp := range s.interpretes {
+				if interp.Base == texto("base") && interp.Language == texto("lenguaje") {
+					existe = true
+				}
+			}
+		case "traductor":
+			clave = "traductor " + texto("base") + " " + texto("origen") + " " + texto("destino")
+			descripcion = fmt.Sprintf("un traductor de '%s' hacia '%s' escrito en '%s'",
+				texto("origen"), texto("destino"), texto("base"))
+			for _, trad := range s.traductores {
+				if trad.Base == texto("base") && trad.Source == texto("origen") &&
+					trad.Target == texto("destino") {
+					existe = true
+				}
+			}
+		}
+
+		if anterior, ok := definidos[clave]; ok {
+			lector.problema(e.pos, "conflicto: ya existe %s (definido en la línea %d)", descripcion, anterior.linea)
+			continue
+		}
+		if existe {
+			lector.problema(e.pos, "conflicto: ya existe %s en el sistema", descripcion)
+			continue
+		}
+		definidos[clave] = e.pos
+
+		metadatos := Metadatos{version: texto("version"), procedencia: texto("procedencia")}
+		if v, ok := e.campos["confiable"]; ok {
+			metadatos.noConfiable = !v.dato.(bool)
+		}
+		if v, ok := e.campos["costo"]; ok {
+			metadatos.costo = v.dato.(float64)
+			metadatos.costoDeclarado = true
+		}
+		if v, ok := e.campos["desde"]; ok {
+			metadatos.desde, _ = time.Parse(formatoFecha, v.dato.(string))
+		}
+		if v, ok := e.campos["hasta"]; ok {
+			metadatos.hasta, _ = time.Parse(formatoFecha, v.dato.(string))
+		}
+		if v, ok := e.campos["caracteristicas"]; ok {
+			for _, c := range v.dato.([]interface{}) {
+				metadatos.caracteristicas = append(metadatos.caracteristicas, c.(string))
+			}
+		}
+
+		// Cada definición queda en el historial con el comando que la
+		// reproduce, sin depender del manifiesto
+		switch e.seccion {
+		case "programa":
+			programa := Program{Name: texto("nombre"), Language: texto("lenguaje")}
+			s.registrarEvento(programa.comando(), func() {
+				s.programas[programa.Name] = programa
+			})
+			resultado.Programas++
+		case "interprete":
+			interp := Interpreter{
+				Base:      texto("base"),
+				Language:  texto("lenguaje"),
+				metadatos: metadatos,
+			}
+			s.registrarEvento(interp.comando(), func() {
+				s.interpretes = append(s.interpretes, interp)
+			})
+			resultado.Interpretes++
+		case "traductor":
+			trad := Translator{
+				Base:      texto("base"),
+				Source:    texto("origen"),
+				Target:    texto("destino"),
+				metadatos: metadatos,
+			}
+			s.registrarEvento(trad.comando(), func() {
+				s.traductores = append(s.traductores, trad)
+			})
+			resultado.Traductores++
+		}
+	}
+
+	resultado.Problemas = lector.problemas
+	return resultado
+}
+
+// validarEntrada reporta los campos desconocidos, los de tipo incorrecto y
+// los obligatorios que faltan. Devuelve false si la entrada no debe importarse.
+func (l *lectorManifiesto) validarEntrada(e *entradaManifiesto) bool {
+	valida := true
+	tipos := camposManifiesto[e.seccion]
+
+	for _, clave := range e.claves {
+		v := e.campos[clave]
+		tipo, conocido := tipos[clave]
+		if !conocido {
+			l.problema(v.pos, "campo desconocido '%s' en la sección '%s'", clave, e.seccion)
+			continue
+		}
+
+		switch tipo {
+		case "texto":
+			if texto, ok := v.dato.(string); !ok || texto == "" {
+				l.problema(v.pos, "el campo '%s' debe ser un texto no vacío", clave)
+				valida = false
+			} else if strings.ContainsAny(texto, " \t") {
+				l.problema(v.pos, "el campo '%s' no puede contener espacios", clave)
+				valida = false
+			}
+		case "numero":
+			if n, ok := v.dato.(float64); !ok || n < 0 || math.IsNaN(n) || math.IsInf(n, 0) {
+				l.problema(v.pos, "el campo '%s' debe ser un número finito no negativo", clave)
+				valida = false
+			}
+		case "booleano":
+			if _, ok := v.dato.(bool); !ok {
+				l.problema(v.pos, "el campo '%s' debe ser true o false", clave)
+				valida = false
+			}
+		case "fecha":
+			if f, esFecha := v.dato.(fechaTOML); esFecha {
+				v.dato = string(f)
+				e.campos[clave] = v
+			}
+			texto, ok := v.dato.(string)
+			if ok {
+				_, err := time.Parse(formatoFecha, texto)
+				ok = err == nil
+			}
+			if !ok {
+				l.problema(v.pos, "el campo '%s' debe ser una fecha AAAA-MM-DD", clave)
+				valida = false
+			}
+		case "lista":
+			lista, ok := v.dato.([]interface{})
+			for _, elemento := range lista {
+				// Sin espacios ni comas para poder escribirse como opción de DEFINIR
+				texto, esTexto := elemento.(string)
+				if !esTexto || texto == "" || strings.ContainsAny(texto, " \t,") {
+					ok = false
+				}
+			}
+			if !ok {
+				l.problema(v.pos, "el campo '%s' debe ser una lista de textos sin espacios ni comas", clave)
+				valida = false
+			}
+		}
+	}
+
+	if valida && e.campos["desde"].dato != nil && e.campos["hasta"].dato != nil &&
+		e.campos["desde"].dato.(string) >= e.campos["hasta"].dato.(string) {
+		l.problema(e.campos["hasta"].pos, "la fecha 'hasta' debe ser posterior a 'desde'")
+		valida = false
+	}
+
+	for _, clave := range camposObligatorios[e.seccion] {
+		if _, ok := e.campos[clave]; !ok {
+			l.problema(e.pos, "falta el campo obligatorio '%s' en la sección '%s'", clave, e.seccion)
+			valida = false
+		}
+	}
+	return valida
+}
+
+// Análisis de TOML
+
+// analizarTOML lee el subconjunto de TOML admitido. Los errores de sintaxis
+// se reportan y el análisis continúa en la línea siguiente.
+func (l *lectorManifiesto) analizarTOML(datos []byte) {
+	var actual *entradaManifiesto
+	seccionIgnorada := false
+
+	for i, linea := range strings.Split(string(datos), "\n") {
+		numero := i + 1
+		linea = strings.TrimSuffix(linea, "\r")
+		contenido := quitarComentarioTOML(linea)
+		recortado := strings.TrimSpace(contenido)
+		if recortado == "" {
+			continue
+		}
+		inicio := posicion{numero, utf8.RuneCountInString(contenido[:strings.Index(contenido, recortado)]) + 1}
+
+		switch {
+		case strings.HasPrefix(recortado, "[["):
+			if !strings.HasSuffix(recortado, "]]") {
+				l.problema(inicio, "encabezado de sección sin cerrar")
+				actual, seccionIgnorada = nil, true
+				continue
+			}
+			seccion := strings.TrimSpace(recortado[2 : len(recortado)-2])
+			if _, conocida := camposManifiesto[seccion]; !conocida {
+				l.problema(inicio, "sección desconocida '%s'", seccion)
+				actual, seccionIgnorada = nil, true
+				continue
+			}
+			actual = &entradaManifiesto{seccion: seccion, pos: inicio, campos: make(map[string]valorManifiesto)}
+			seccionIgnorada = false
+			l.entradas = append(l.entradas, actual)
+
+		case strings.HasPrefix(recortado, "["):
+			l.problema(inicio, "solo se admiten arreglos de tablas ([[seccion]])")
+			actual, seccionIgnorada = nil, true
+
+		default:
+			igual := indiceFueraDeCadena(contenido, '=')
+			if igual < 0 {
+				l.problema(inicio, "se esperaba 'clave = valor'")
+				continue
+			}
+			clave := strings.TrimSpace(contenido[:igual])
+			if len(clave) >= 2 && (clave[0] == '"' || clave[0] == '\'') && clave[len(clave)-1] == clave[0] {
+				clave = clave[1 : len(clave)-1]
+			} else if !claveTOMLValida(clave) {
+				l.problema(inicio, "clave inválida '%s'", clave)
+				continue
+			}
+
+			textoValor := contenido[igual+1:]
+			desplazamiento := len(textoValor) - len(strings.TrimLeft(textoValor, " \t"))
+			posValor := posicion{numero, utf8.RuneCountInString(contenido[:igual+1+desplazamiento]) + 1}
+			dato, err := analizarValorTOML(strings.TrimSpace(textoValor))
+			if err != nil {
+				l.problema(posValor, "valor inválido para '%s': %v", clave, err)
+				continue
+			}
+
+			if actual == nil {
+				if !seccionIgnorada {
+					l.problema(inicio, "la clave '%s' está fuera de una sección", clave)
+				}
+				continue
+			}
+			l.agregarCampo(actual, clave, valorManifiesto{dato: dato, pos: posValor}, inicio)
+		}
+	}
+}
+
+// quitarComentarioTOML elimina el comentario (desde '#') que no esté dentro
+// de una cadena
+func quitarComentarioTOML(linea string) string {
+	if i := indiceFueraDeCadena(linea, '#'); i >= 0 {
+		return linea[:i]
+	}
+	return linea
+}
+
+// indiceFueraDeCadena busca el primer carácter c que no esté entre comillas
+func indiceFueraDeCadena(texto string, c byte) int {
+	var comilla byte
+	for i := 0; i < len(texto); i++ {
+		switch {
+		case comilla == '"' && texto[i] == '\\':
+			i++
+		case comilla != 0:
+			if texto[i] == comilla {
+				comilla = 0
+			}
+		case texto[i] == '"' || texto[i] == '\'':
+			comilla = texto[i]
+		case texto[i] == c:
+			return i
+		}
+	}
+	return -1
+}
+
+// claveTOMLValida indica si la clave es una clave simple (sin comillas) válida
+func claveTOMLValida(clave string) bool {
+	if clave == "" {
+		return false
+	}
+	for _, r := range clave {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// analizarValorTOML interpreta un valor TOML completo
+func analizarValorTOML(texto string) (interface{}, error) {
+	dato, resto, err := leerValorTOML(texto)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(resto) != "" {
+		return nil, fmt.Errorf("contenido inesperado '%s'", strings.TrimSpace(resto))
+	}
+	return dato, nil
+}
+
+// leerValorTOML lee un valor al comienzo del texto y devuelve lo que sobra
+func leerValorTOML(texto string) (interface{}, string, error) {
+	texto = strings.TrimLeft(texto, " \t")
+	if texto == "" {
+		return nil, "", errors.New("falta el valor")
+	}
+
+	switch texto[0] {
+	case '"':
+		var b strings.Builder
+		for i := 1; i < len(texto); i++ {
+			switch texto[i] {
+			case '"':
+				return b.String(), texto[i+1:], nil
+			case '\\':
+				if i+1 >= len(texto) {
+					return nil, "", errors.New("cadena sin cerrar")
+				}
+				i++
+				switch texto[i] {
+				case '"', '\\':
+					b.WriteByte(texto[i])
+				case 'n':
+					b.WriteByte('\n')
+				case 't':
+					b.WriteByte('\t')
+				case 'u':
+					if i+4 >= len(texto) {
+						return nil, "", errors.New("escape \\u incompleto")
+					}
+					r, err := strconv.ParseUint(texto[i+1:i+5], 16, 32)
+					if err != nil {
+						return nil, "", fmt.Errorf("escape \\u inválido")
+					}
+					b.WriteRune(rune(r))
+					i += 4
+				default:
+					return nil, "", fmt.Errorf("escape desconocido '\\%c'", texto[i])
+				}
+			default:
+				b.WriteByte(texto[i])
+			}
+		}
+		return nil, "", errors.New("cadena sin cerrar")
+
+	case '\'':
+		fin := strings.IndexByte(texto[1:], '\'')
+		if fin < 0 {
+			return nil, "", errors.New("cadena sin cerrar")
+		}
+		return texto[1 : fin+1], texto[fin+2:], nil
+
+	case '[':
+		lista := make([]interface{}, 0)
+		resto := strings.TrimLeft(texto[1:], " \t")
+		for {
+			if strings.HasPrefix(resto, "]") {
+				return lista, resto[1:], nil
+			}
+			elemento, siguiente, err := leerValorTOML(resto)
+			if err != nil {
+				return nil, "", err
+			}
+			lista = append(lista, elemento)
+			resto = strings.TrimLeft(siguiente, " \t")
+			if strings.HasPrefix(resto, ",") {
+				resto = strings.TrimLeft(resto[1:], " \t")
+			} else if !strings.HasPrefix(resto, "]") {
+				return nil, "", errors.New("arreglo sin cerrar (solo se admiten arreglos en una línea)")
+			}
+		}
+	}
+
+	fin := strings.IndexAny(texto, " \t,]")
+	if fin < 0 {
+		fin = len(texto)
+	}
+	palabra := texto[:fin]
+	switch palabra {
+	case "true":
+		return true, texto[fin:], nil
+	case "false":
+		return false, texto[fin:], nil
+	}
+	if _, err := time.Parse(formatoFecha, palabra); err == nil {
+		return fechaTOML(palabra), texto[fin:], nil
+	}
+	n, err := strconv.ParseFloat(strings.ReplaceAll(palabra, "_", ""), 64)
+	if err != nil {
+		return nil, "", fmt.Errorf("valor no reconocido '%s'", palabra)
+	}
+	return n, texto[fin:], nil
+}
+
+// Análisis de JSON
+
+// analizarJSON lee un manifiesto JSON. Un error de sintaxis detiene el
+// análisis y se reporta en su ubicación.
+func (l *lectorManifiesto) analizarJSON(datos []byte) {
+	dec := json.NewDecoder(bytes.NewReader(datos))
+	dec.UseNumber()
+	// pos devuelve la ubicación del próximo token
+	pos := func() posicion {
+		return posicionEn(datos, int(dec.InputOffset()), true)
+	}
+
+	if err := l.leerRaizJSON(dec, pos); err != nil {
+		offset := int(dec.InputOffset())
+		var errSintaxis *json.SyntaxError
+		if errors.As(err, &errSintaxis) {
+			offset = int(errSintaxis.Offset) - 1
+		}
+		if offset < 0 {
+			offset = 0
+		}
+		l.problema(posicionEn(datos, offset, false), "JSON inválido: %v", err)
+	}
+}
+
+func (l *lectorManifiesto) leerRaizJSON(dec *json.Decoder, pos func() posicion) error {
+	inicio := pos()
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != json.Delim('{') {
+		l.problema(inicio, "se esperaba un objeto con las secciones del manifiesto")
+		return nil
+	}
+
+	for dec.More() {
+		posSeccion := pos()
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		seccion := tok.(string)
+		if _, conocida := camposManifiesto[seccion]; !conocida {
+			l.problema(posSeccion, "sección desconocida '%s'", seccion)
+			if err := saltarValorJSON(dec); err != nil {
+				return err
+			}
+			continue
+		}
+
+		posArreglo := pos()
+		tok, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		if tok != json.Delim('[') {
+			l.problema(posArreglo, "la sección '%s' debe ser un arreglo de objetos", seccion)
+			if err := saltarResto(dec, tok); err != nil {
+				return err
+			}
+			continue
+		}
+		for dec.More() {
+			if err := l.leerEntradaJSON(dec, pos, seccion); err != nil {
+				return err
+			}
+		}
+		if _, err := dec.Token(); err != nil { // ']'
+			return err
+		}
+	}
+	_, err = dec.Token() // '}'
+	return err
+}
+
+func (l *lectorManifiesto) leerEntradaJSON(dec *json.Decoder, pos func() posicion, seccion string) error {
+	posEntrada := pos()
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != json.Delim('{') {
+		l.problema(posEntrada, "los elementos de '%s' deben ser objetos", seccion)
+		return saltarResto(dec, tok)
+	}
+
+	entrada := &entradaManifiesto{seccion: seccion, pos: posEntrada, campos: make(map[string]valorManifiesto)}
+	l.entradas = append(l.entradas, entrada)
+	for dec.More() {
+		posClave := pos()
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		clave := tok.(string)
+
+		posValor := pos()
+		tok, err = dec.Token()
+		if err != nil {
+			return err
+		}
+		dato, err := valorJSON(dec, tok)
+		if err != nil {
+			return err
+		}
+		if dato == nil {
+			l.problema(posValor, "valor no admitido para '%s'", clave)
+			continue
+		}
+		l.agregarCampo(entrada, clave, valorManifiesto{dato: dato, pos: posValor}, posClave)
+	}
+	_, err = dec.Token() // '}'
+	return err
+}
+
+// valorJSON convierte el token leído en un dato del manifiesto. Devuelve nil
+// (y consume el valor completo) si el valor no es de un tipo admitido.
+func valorJSON(dec *json.Decoder, tok json.Token) (interface{}, error) {
+	switch v := tok.(type) {
+	case string, bool:
+		return v, nil
+	case json.Number:
+		// Fuera de rango da ±Inf, que la validación rechaza con un mensaje
+		// más claro que "valor no admitido"
+		n, err := v.Float64()
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			return nil, nil
+		}
+		return n, nil
+	case json.Delim:
+		if v != '[' {
+			return nil, saltarResto(dec, tok)
+		}
+		lista := make([]interface{}, 0)
+		admitida := true
+		for dec.More() {
+			elemento, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			dato, err := valorJSON(dec, elemento)
+			if err != nil {
+				return nil, err
+			}
+			if _, anidada := dato.([]interface{}); dato == nil || anidada {
+				admitida = false
+			}
+			lista = append(lista, dato)
+		}
+		if _, err := dec.Token(); err != nil { // ']'
+			return nil, err
+		}
+		if !admitida {
+			return nil, nil
+		}
+		return lista, nil
+	}
+	return nil, nil
+}
+
+// saltarValorJSON consume el próximo valor completo
+func saltarValorJSON(dec *json.Decoder) error {
+	var ignorado json.RawMessage
+	return dec.Decode(&ignorado)
+}
+
+// saltarResto consume lo que falta de un valor cuyo primer token ya se leyó
+func saltarResto(dec *json.Decoder, tok json.Token) error {
+	d, ok := tok.(json.Delim)
+	if !ok || d == '}' || d == ']' {
+		return nil
+	}
+	for profundidad := 1; profundidad > 0; {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			profundidad++
+		case json.Delim('}'), json.Delim(']'):
+			profundidad--
+		}
+	}
+	return nil
+}
+
+// posicionEn convierte un desplazamiento en bytes en línea y columna. Si
+// saltar es verdadero, antes avanza sobre espacios y separadores para ubicar
+// el comienzo del próximo token.
+func posicionEn(datos []byte, offset int, saltar bool) posicion {
+	if offset > len(datos) {
+		offset = len(datos)
+	}
+	for saltar && offset < len(datos) && strings.IndexByte(" \t\r\n,:", datos[offset]) >= 0 {
+		offset++
+	}
+	inicioLinea := bytes.LastIndexByte(datos[:offset], '\n') + 1
+	return posicion{
+		linea:   bytes.Count(datos[:offset], []byte("\n")) + 1,
+		columna: utf8.RuneCount(datos[inicioLinea:offset]) + 1,
+	}
+}
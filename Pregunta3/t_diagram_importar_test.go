@@ -0,0 +1,303 @@
+package tdiagram
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+// importar importa el manifiesto dado en un sistema nuevo
+func importar(t *testing.T, nombre, contenido string) (*System, ResultadoImportacion) {
+	t.Helper()
+	s := NuevoSistema()
+	s.salida = io.Discard
+	resultado, err := s.ImportarManifiesto(nombre, strings.NewReader(contenido))
+	if err != nil {
+		t.Fatalf("No debería dar error: %v", err)
+	}
+	return s, resultado
+}
+
+// buscarProblema devuelve el primer problema cuyo mensaje contiene el texto
+func buscarProblema(resultado ResultadoImportacion, texto string) (ProblemaImportacion, bool) {
+	for _, p := range resultado.Problemas {
+		if strings.Contains(p.Mensaje, texto) {
+			return p, true
+		}
+	}
+	return ProblemaImportacion{}, false
+}
+
+const manifiestoTOML = `# Inventario de herramientas
+[[programa]]
+nombre = "factorial"
+lenguaje = "Java"
+
+[[interprete]]
+base = "LOCAL"
+lenguaje = "C"
+version = "11.2"
+costo = 3
+caracteristicas = ["optimizador", 'depurador']
+
+[[traductor]]
+base = "C"
+origen = "Java"   # comentario al final
+destino = "C"
+costo = 1.5
+`
+
+// TestImportarTOML verifica la importación de un manifiesto TOML válido
+func TestImportarTOML(t *testing.T) {
+	s, resultado := importar(t, "herramientas.toml", manifiestoTOML)
+
+	if len(resultado.Problemas) != 0 {
+		t.Fatalf("No se esperaban problemas: %v", resultado.Problemas)
+	}
+	if resultado.Programas != 1 || resultado.Interpretes != 1 || resultado.Traductores != 1 {
+		t.Errorf("Conteos inesperados: %+v", resultado)
+	}
+
+	meta := s.interpretes[0].metadatos
+	if meta.version != "11.2" || meta.costo != 3 || !meta.costoDeclarado {
+		t.Errorf("Metadatos del intérprete inesperados: %+v", meta)
+	}
+	if len(meta.caracteristicas) != 2 || meta.caracteristicas[1] != "depurador" {
+		t.Errorf("Características inesperadas: %v", meta.caracteristicas)
+	}
+	if s.traductores[0].metadatos.costo != 1.5 {
+		t.Errorf("Costo del traductor inesperado: %v", s.traductores[0].metadatos.costo)
+	}
+	if !s.traductores[0].metadatos.costoDeclarado {
+		t.Error("El costo del traductor debería estar declarado")
+	}
+}
+
+// TestImportarJSON verifica la importación de un manifiesto JSON válido
+func TestImportarJSON(t *testing.T) {
+	manifiesto := `{
+  "programa": [{"nombre": "factorial", "lenguaje": "Java"}],
+  "interprete": [
+    {"base": "LOCAL", "lenguaje": "Java", "costo": 2, "caracteristicas": ["jit"]}
+  ]
+}`
+	s, resultado := importar(t, "herramientas.json", manifiesto)
+
+	if len(resultado.Problemas) != 0 {
+		t.Fatalf("No se esperaban problemas: %v", resultado.Problemas)
+	}
+	if resultado.Programas != 1 || resultado.Interpretes != 1 {
+		t.Errorf("Conteos inesperados: %+v", resultado)
+	}
+	if ok, _, _ := s.EsEjecutable("factorial"); !ok {
+		t.Error("factorial debería ser ejecutable después de importar")
+	}
+}
+
+// TestImportarCampoDesconocido verifica que se reporta la ubicación exacta de
+// un campo desconocido y que la entrada igual se importa
+func TestImportarCampoDesconocido(t *testing.T) {
+	manifiesto := "[[interprete]]\nbase = \"LOCAL\"\nlenguaje = \"C\"\n  licencia = \"GPL\"\n"
+	_, resultado := importar(t, "m.toml", manifiesto)
+
+	p, ok := buscarProblema(resultado, "campo desconocido 'licencia'")
+	if !ok {
+		t.Fatalf("Se esperaba un problema por campo desconocido: %v", resultado.Problemas)
+	}
+	if p.Linea != 4 || p.Columna != 14 {
+		t.Errorf("Ubicación inesperada %d:%d", p.Linea, p.Columna)
+	}
+	if resultado.Interpretes != 1 {
+		t.Error("Un campo desconocido no debería impedir la importación")
+	}
+
+	manifiestoJSON := "{\"traductor\": [\n  {\"base\": \"C\", \"origen\": \"A\", \"destino\": \"B\",\n   \"extra\": 1}]}"
+	_, resultado = importar(t, "m.json", manifiestoJSON)
+	p, ok = buscarProblema(resultado, "campo desconocido 'extra'")
+	if !ok || p.Linea != 3 || p.Columna != 13 {
+		t.Errorf("Se esperaba el campo desconocido en 3:13, se obtuvo %v", resultado.Problemas)
+	}
+}
+
+// TestImportarConflictos verifica los conflictos con el sistema y dentro del
+// propio manifiesto
+func TestImportarConflictos(t *testing.T) {
+	s := NuevoSistema()
+	s.salida = io.Discard
+	s.DefinirPrograma("factorial", "C")
+	manifiesto := `[[programa]]
+nombre = "factorial"
+lenguaje = "Java"
+
+[[interprete]]
+base = "LOCAL"
+lenguaje = "C"
+
+[[interprete]]
+base = "LOCAL"
+lenguaje = "C"
+costo = 2
+`
+	resultado, err := s.ImportarManifiesto("m.toml", strings.NewReader(manifiesto))
+	if err != nil {
+		t.Fatalf("No debería dar error: %v", err)
+	}
+
+	if p, ok := buscarProblema(resultado, "ya existe un programa con el nombre 'factorial' en el sistema"); !ok || p.Linea != 1 {
+		t.Errorf("Se esperaba un conflicto de programa en la línea 1: %v", resultado.Problemas)
+	}
+	if p, ok := buscarProblema(resultado, "(definido en la línea 5)"); !ok || p.Linea != 9 {
+		t.Errorf("Se esperaba un conflicto de intérprete en la línea 9: %v", resultado.Problemas)
+	}
+	if resultado.Programas != 0 || resultado.Interpretes != 1 {
+		t.Errorf("Las entradas en conflicto no deberían importarse: %+v", resultado)
+	}
+	if s.programas["factorial"].Language != "C" {
+		t.Error("El programa existente no debería modificarse")
+	}
+}
+
+// TestImportarErroresDeValidacion verifica campos faltantes y tipos inválidos
+func TestImportarErroresDeValidacion(t *testing.T) {
+	manifiesto := `[[traductor]]
+base = "C"
+origen = "Java"
+
+[[interprete]]
+base = "LOCAL"
+lenguaje = "C"
+costo = "barato"
+
+[[interprete]]
+base = "LOCAL"
+lenguaje = "Go"
+costo = -1
+`
+	_, resultado := importar(t, "m.toml", manifiesto)
+
+	if p, ok := buscarProblema(resultado, "falta el campo obligatorio 'destino'"); !ok || p.Linea != 1 {
+		t.Errorf("Se esperaba un campo faltante en la línea 1: %v", resultado.Problemas)
+	}
+	if p, ok := buscarProblema(resultado, "'costo' debe ser un número"); !ok || p.Linea != 8 || p.Columna != 9 {
+		t.Errorf("Se esperaba un costo inválido en 8:9: %v", resultado.Problemas)
+	}
+	if resultado.Traductores != 0 || resultado.Interpretes != 0 {
+		t.Errorf("Las entradas inválidas no deberían importarse: %+v", resultado)
+	}
+}
+
+// TestImportarNumerosNoFinitos verifica que nan e inf no se aceptan como
+// costo, porque romperían las comparaciones de PLANIFICAR
+func TestImportarNumerosNoFinitos(t *testing.T) {
+	manifiesto := `[[interprete]]
+base = "LOCAL"
+lenguaje = "C"
+costo = nan
+
+[[interprete]]
+base = "LOCAL"
+lenguaje = "Go"
+costo = inf
+
+[[traductor]]
+base = "C"
+origen = "Java"
+destino = "C"
+costo = -inf
+`
+	_, resultado := importar(t, "m.toml", manifiesto)
+	if resultado.Interpretes != 0 || resultado.Traductores != 0 || len(resultado.Problemas) != 3 {
+		t.Errorf("Se esperaban tres costos inválidos: %+v", resultado)
+	}
+	if _, ok := buscarProblema(resultado, "'costo' debe ser un número finito"); !ok {
+		t.Errorf("Se esperaba un costo no finito: %v", resultado.Problemas)
+	}
+
+	_, resultado = importar(t, "m.json", `{"interprete": [{"base": "LOCAL", "lenguaje": "C", "costo": 1e400}]}`)
+	if resultado.Interpretes != 0 || len(resultado.Problemas) != 1 {
+		t.Errorf("Se esperaba un costo fuera de rango en JSON: %+v", resultado)
+	}
+}
+
+// TestImportarFechasTOML verifica que las fechas locales de TOML se aceptan
+// igual que las escritas entre comillas, y solo en campos de fecha
+func TestImportarFechasTOML(t *testing.T) {
+	manifiesto := `[[interprete]]
+base = "LOCAL"
+lenguaje = "C"
+desde = 2024-01-01
+hasta = "2025-06-30"
+
+[[interprete]]
+base = "LOCAL"
+lenguaje = "Go"
+version = 2024-01-01
+`
+	s, resultado := importar(t, "m.toml", manifiesto)
+	if resultado.Interpretes != 1 {
+		t.Fatalf("Se esperaba un intérprete importado: %+v", resultado)
+	}
+	m := s.interpretes[0].metadatos
+	if m.desde.Format(formatoFecha) != "2024-01-01" || m.hasta.Format(formatoFecha) != "2025-06-30" {
+		t.Errorf("Fechas inesperadas: %v %v", m.desde, m.hasta)
+	}
+	if p, ok := buscarProblema(resultado, "'version' debe ser un texto"); !ok || p.Linea != 10 {
+		t.Errorf("Se esperaba una fecha inválida como versión en la línea 10: %v", resultado.Problemas)
+	}
+}
+
+// TestImportarErroresDeSintaxisTOML verifica que los errores de sintaxis se
+// ubican en su línea y no detienen el análisis
+func TestImportarErroresDeSintaxisTOML(t *testing.T) {
+	manifiesto := `clave = "suelta"
+[herramientas]
+[[compilador]]
+base = "x"
+[[interprete]]
+base = "LOCAL
+lenguaje = "C"
+base "LOCAL"
+`
+	_, resultado := importar(t, "m.toml", manifiesto)
+
+	esperados := map[int]string{
+		1: "fuera de una sección",
+		2: "solo se admiten arreglos de tablas",
+		3: "sección desconocida 'compilador'",
+		6: "cadena sin cerrar",
+		8: "se esperaba 'clave = valor'",
+	}
+	for linea, texto := range esperados {
+		p, ok := buscarProblema(resultado, texto)
+		if !ok || p.Linea != linea {
+			t.Errorf("Se esperaba '%s' en la línea %d: %v", texto, linea, resultado.Problemas)
+		}
+	}
+}
+
+// TestImportarJSONInvalido verifica la ubicación de un error de sintaxis JSON
+func TestImportarJSONInvalido(t *testing.T) {
+	_, resultado := importar(t, "m.json", "{\n  \"programa\": [\n    {\"nombre\": \"p\",, }\n  ]\n}")
+
+	p, ok := buscarProblema(resultado, "JSON inválido")
+	if !ok || p.Linea != 3 {
+		t.Errorf("Se esperaba un error de JSON en la línea 3: %v", resultado.Problemas)
+	}
+}
+
+// TestImportarFormatoDesconocido verifica el error con una extensión no admitida
+func TestImportarFormatoDesconocido(t *testing.T) {
+	s := NuevoSistema()
+	if _, err := s.ImportarManifiesto("m.yaml", strings.NewReader("")); err == nil {
+		t.Error("Debería dar error con un formato desconocido")
+	}
+}
+
+// TestProblemaImportacionString verifica el formato de los problemas
+func TestProblemaImportacionString(t *testing.T) {
+	p := ProblemaImportacion{Archivo: "m.toml", Linea: 3, Columna: 7, Mensaje: "algo"}
+
+	if p.String() != "m.toml:3:7: algo" {
+		t.Errorf("Formato inesperado: %s", p)
+	}
+}
@@ -0,0 +1,556 @@
+package tdiagram
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Servidor de lenguaje (LSP) para archivos de comandos .tdiag. Se comunica
+// por la entrada y la salida estándar con mensajes JSON-RPC precedidos por
+// la cabecera Content-Length y ofrece:
+//
+//   - diagnósticos para comandos mal formados, programas duplicados y
+//     lenguajes que ningún intérprete ni traductor define
+//   - información sobre la ejecutabilidad de programas y lenguajes al pasar
+//     el cursor sobre ellos
+//   - ir a la definición de programas y lenguajes
+//
+// Cada documento se analiza ejecutando sus líneas en una sesión nueva, de
+// modo que los errores son exactamente los que daría el simulador.
+
+// Severidades de los diagnósticos según el protocolo
+const (
+	severidadError       = 1
+	severidadAdvertencia = 2
+)
+
+// Códigos de error de JSON-RPC usados por el servidor
+const (
+	errorAnalisisRPC       = -32700
+	errorSolicitudRPC      = -32600
+	errorMetodoDesconocido = -32601
+	errorParametrosRPC     = -32602
+)
+
+// largoMaximoLSP es el mayor Content-Length que se acepta, para que una
+// cabecera errónea no reserve memoria sin límite
+const largoMaximoLSP = 64 << 20
+
+// posicionLSP es una posición en un documento: línea y carácter desde 0,
+// contando los caracteres en unidades UTF-16 como pide el protocolo
+type posicionLSP struct {
+	Linea    int `json:"line"`
+	Caracter int `json:"character"`
+}
+
+// rangoLSP es un rango de un documento; el fin no está incluido
+type rangoLSP struct {
+	Inicio posicionLSP `json:"start"`
+	Fin    posicionLSP `json:"end"`
+}
+
+// diagnosticoLSP es un error o advertencia asociado a un rango del documento
+type diagnosticoLSP struct {
+	Rango     rangoLSP `json:"range"`
+	Severidad int      `json:"severity"`
+	Fuente    string   `json:"source"`
+	Mensaje   string   `json:"message"`
+}
+
+// ubicacionLSP es un rango dentro de un documento identificado por su URI
+type ubicacionLSP struct {
+	URI   string   `json:"uri"`
+	Rango rangoLSP `json:"range"`
+}
+
+// tokenLinea es una palabra de una línea con sus columnas en unidades UTF-16
+type tokenLinea struct {
+	texto       string
+	inicio, fin int
+}
+
+// tokensDeLinea separa la línea en palabras igual que strings.Fields,
+// conservando la columna de cada una
+func tokensDeLinea(linea string) []tokenLinea {
+	tokens := make([]tokenLinea, 0)
+	var actual strings.Builder
+	columna, inicio := 0, -1
+	for _, r := range linea {
+		if unicode.IsSpace(r) {
+			if inicio >= 0 {
+				tokens = append(tokens, tokenLinea{texto: actual.String(), inicio: inicio, fin: columna})
+				actual.Reset()
+				inicio = -1
+			}
+		} else {
+			if inicio < 0 {
+				inicio = columna
+			}
+			actual.WriteRune(r)
+		}
+		// Los caracteres fuera del plano básico ocupan dos unidades UTF-16
+		columna++
+		if r >= 0x10000 {
+			columna++
+		}
+	}
+	if inicio >= 0 {
+		tokens = append(tokens, tokenLinea{texto: actual.String(), inicio: inicio, fin: columna})
+	}
+	return tokens
+}
+
+// simboloTDiag es la aparición de un programa o de un lenguaje en una línea
+// que el simulador aceptó. define indica si la línea lo define: un programa
+// se define con DEFINIR PROGRAMA y un lenguaje con el intérprete o traductor
+// que lo vuelve ejecutable.
+type simboloTDiag struct {
+	nombre  string
+	tipo    string // "programa" o "lenguaje"
+	define  bool
+	linea   int
+	token   tokenLinea
+	sistema *System // entorno en el que aparece
+}
+
+// rango devuelve el rango que ocupa el símbolo en el documento
+func (sim simboloTDiag) rango() rangoLSP {
+	return rangoLSP{
+		Inicio: posicionLSP{Linea: sim.linea, Caracter: sim.token.inicio},
+		Fin:    posicionLSP{Linea: sim.linea, Caracter: sim.token.fin},
+	}
+}
+
+// simbolosDeLinea devuelve los programas y lenguajes que nombra una línea
+func simbolosDeLinea(tokens []tokenLinea) []simboloTDiag {
+	simbolos := make([]simboloTDiag, 0)
+	agregar := func(i int, tipo string, define bool) {
+		if i < len(tokens) {
+			simbolos = append(simbolos, simboloTDiag{nombre: tokens[i].texto, tipo: tipo, define: define, token: tokens[i]})
+		}
+	}
+
+	switch strings.ToUpper(tokens[0].texto) {
+	case "DEFINIR":
+		if len(tokens) < 2 {
+			break
+		}
+		switch strings.ToUpper(tokens[1].texto) {
+		case "PROGRAMA":
+			agregar(2, "programa", true)
+			agregar(3, "lenguaje", false)
+		case "INTERPRETE":
+			agregar(2, "lenguaje", false)
+			agregar(3, "lenguaje", true)
+		case "TRADUCTOR":
+			agregar(2, "lenguaje", false)
+			agregar(3, "lenguaje", false)
+			agregar(4, "lenguaje", true)
+		}
+	case "EJECUTABLE":
+		agregar(1, "programa", false)
+	case "PLANIFICAR":
+		for i := 1; i < len(tokens); i++ {
+			agregar(i, "programa", false)
+		}
+	}
+	return simbolos
+}
+
+// analisisTDiag es el resultado de analizar un documento
+type analisisTDiag struct {
+	diagnosticos []diagnosticoLSP
+	simbolos     []simboloTDiag
+}
+
+// diagnosticar agrega un diagnóstico al análisis
+func (a *analisisTDiag) diagnosticar(rango rangoLSP, severidad int, mensaje string) {
+	a.diagnosticos = append(a.diagnosticos, diagnosticoLSP{
+		Rango:     rango,
+		Severidad: severidad,
+		Fuente:    "tdiagram",
+		Mensaje:   strings.TrimPrefix(mensaje, "ERROR: "),
+	})
+}
+
+// definicion busca dónde se define un programa o un lenguaje en un entorno
+func (a *analisisTDiag) definicion(nombre, tipo string, sistema *System) (simboloTDiag, bool) {
+	for _, sim := range a.simbolos {
+		if sim.define && sim.nombre == nombre && sim.tipo == tipo && sim.sistema == sistema {
+			return sim, true
+		}
+	}
+	return simboloTDiag{}, false
+}
+
+// simboloEn busca el símbolo que ocupa la posición dada
+func (a *analisisTDiag) simboloEn(pos posicionLSP) (simboloTDiag, bool) {
+	for _, sim := range a.simbolos {
+		if sim.linea == pos.Linea && sim.token.inicio <= pos.Caracter && pos.Caracter <= sim.token.fin {
+			return sim, true
+		}
+	}
+	return simboloTDiag{}, false
+}
+
+// lenguajesDefinidos devuelve LOCAL y los lenguajes que algún intérprete o
+// traductor del sistema vuelve ejecutables
+func (s *System) lenguajesDefinidos() map[string]bool {
+	definidos := map[string]bool{"LOCAL": true}
+	for _, interp := range s.interpretes {
+		definidos[interp.Language] = true
+	}
+	for _, trad := range s.traductores {
+		definidos[trad.Target] = true
+	}
+	return definidos
+}
+
+// analizarTDiag ejecuta las líneas de un documento en una sesión nueva y
+// reúne los diagnósticos y los símbolos. Los manifiestos de IMPORTAR se
+// buscan relativos a directorio. Las líneas HISTORIAL no se ejecutan porque
+// leen o escriben archivos, y SALIR termina el análisis.
+func analizarTDiag(texto, directorio string) analisisTDiag {
+	var mensajes bytes.Buffer
+	sesion := NuevaSesion()
+	sesion.RedirigirSalida(&mensajes)
+	analisis := analisisTDiag{diagnosticos: make([]diagnosticoLSP, 0), simbolos: make([]simboloTDiag, 0)}
+
+	for numero, linea := range strings.Split(texto, "\n") {
+		tokens := tokensDeLinea(linea)
+		if len(tokens) == 0 {
+			continue
+		}
+		rangoLinea := rangoLSP{
+			Inicio: posicionLSP{Linea: numero, Caracter: tokens[0].inicio},
+			Fin:    posicionLSP{Linea: numero, Caracter: tokens[len(tokens)-1].fin},
+		}
+
+		accion := strings.ToUpper(tokens[0].texto)
+		if accion == "SALIR" {
+			break
+		}
+		if accion == "HISTORIAL" {
+			continue
+		}
+		if accion == "IMPORTAR" && len(tokens) == 2 {
+			analisis.importar(sesion.Actual(), tokens[1].texto, directorio, rangoLinea)
+			continue
+		}
+
+		mensajes.Reset()
+		sesion.ProcesarComando(linea)
+		errores := make([]string, 0)
+		for _, mensaje := range strings.Split(mensajes.String(), "\n") {
+			if strings.HasPrefix(mensaje, "ERROR") {
+				errores = append(errores, mensaje)
+			}
+		}
+
+		simbolos := simbolosDeLinea(tokens)
+		if len(errores) > 0 {
+			for _, mensaje := range errores {
+				// Un programa duplicado señala dónde estaba la primera definición
+				if len(simbolos) > 0 && simbolos[0].tipo == "programa" && simbolos[0].define {
+					if anterior, ok := analisis.definicion(simbolos[0].nombre, "programa", sesion.Actual()); ok {
+						mensaje += fmt.Sprintf(" (definido en la línea %d)", anterior.linea+1)
+					}
+				}
+				analisis.diagnosticar(rangoLinea, severidadError, mensaje)
+			}
+			continue
+		}
+		for _, sim := range simbolos {
+			sim.linea, sim.sistema = numero, sesion.Actual()
+			analisis.simbolos = append(analisis.simbolos, sim)
+		}
+	}
+
+	// Las referencias se revisan al final, cuando ya se conocen todas las
+	// definiciones del entorno
+	for _, sim := range analisis.simbolos {
+		if sim.tipo == "lenguaje" && !sim.define && !sim.sistema.lenguajesDefinidos()[sim.nombre] {
+			analisis.diagnosticar(sim.rango(), severidadAdvertencia,
+				fmt.Sprintf("Ningún intérprete ni traductor define el lenguaje '%s'", sim.nombre))
+		}
+	}
+	return analisis
+}
+
+// importar aplica un manifiesto al sistema y convierte sus problemas en
+// diagnósticos de la línea IMPORTAR
+func (a *analisisTDiag) importar(s *System, archivo, directorio string, rango rangoLSP) {
+	ruta := archivo
+	if !filepath.IsAbs(ruta) {
+		ruta = filepath.Join(directorio, archivo)
+	}
+	f, err := os.Open(ruta)
+	if err != nil {
+		a.diagnosticar(rango, severidadError, fmt.Sprintf("No se pudo abrir '%s': %v", archivo, err))
+		return
+	}
+	defer f.Close()
+
+	resultado, err := s.ImportarManifiesto(archivo, f)
+	if err != nil {
+		a.diagnosticar(rango, severidadError, err.Error())
+		return
+	}
+	for _, p := range resultado.Problemas {
+		a.diagnosticar(rango, severidadAdvertencia, p.String())
+	}
+}
+
+// describirSimbolo devuelve, en markdown, la ejecutabilidad de un programa
+// o de un lenguaje según las definiciones del documento
+func describirSimbolo(sim simboloTDiag) string {
+	var encabezado string
+	var pasos []Paso
+	var ejecutable bool
+	if sim.tipo == "programa" {
+		programa := sim.sistema.programas[sim.nombre]
+		encabezado = fmt.Sprintf("**programa** `%s`, escrito en `%s`", sim.nombre, programa.Language)
+		ejecutable, pasos, _ = sim.sistema.EsEjecutable(sim.nombre)
+	} else {
+		encabezado = fmt.Sprintf("**lenguaje** `%s`", sim.nombre)
+		pasos, ejecutable = cadena(sim.nombre, sim.sistema.derivaciones())
+	}
+
+	if !ejecutable {
+		return encabezado + "\n\nNo es ejecutable con las definiciones del archivo"
+	}
+	if len(pasos) == 0 {
+		return encabezado + "\n\nEjecutable directamente en LOCAL"
+	}
+	lineas := []string{encabezado, "", "Ejecutable mediante:"}
+	for _, paso := range pasos {
+		lineas = append(lineas, "- "+paso.String())
+	}
+	return strings.Join(lineas, "\n")
+}
+
+// directorioDeURI devuelve el directorio de un documento file://, o el
+// directorio actual si la URI es de otro tipo
+func directorioDeURI(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return "."
+	}
+	return filepath.Dir(filepath.FromSlash(u.Path))
+}
+
+// leerMensajeLSP lee un mensaje con sus cabeceras y devuelve el contenido
+func leerMensajeLSP(r *bufio.Reader) ([]byte, error) {
+	cabeceras, err := textproto.NewReader(r).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	largo, err := strconv.Atoi(cabeceras.Get("Content-Length"))
+	if err != nil || largo < 0 {
+		return nil, fmt.Errorf("cabecera Content-Length inválida: %q", cabeceras.Get("Content-Length"))
+	}
+	if largo > largoMaximoLSP {
+		return nil, fmt.Errorf("mensaje de %d bytes demasiado grande (máximo %d)", largo, largoMaximoLSP)
+	}
+	contenido := make([]byte, largo)
+	if _, err := io.ReadFull(r, contenido); err != nil {
+		return nil, err
+	}
+	return contenido, nil
+}
+
+// escribirMensajeLSP escribe un mensaje precedido por su cabecera
+func escribirMensajeLSP(w io.Writer, mensaje interface{}) error {
+	datos, err := json.Marshal(mensaje)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(datos), datos)
+	return err
+}
+
+// servidorLSP mantiene los documentos abiertos y su último análisis
+type servidorLSP struct {
+	salida   io.Writer
+	errores  io.Writer // errores de lectura y escritura de mensajes
+	analisis map[string]analisisTDiag
+	apagado  bool // se recibió shutdown
+}
+
+// documentoLSP identifica un documento en los parámetros de las solicitudes
+type documentoLSP struct {
+	URI  string `json:"uri"`
+	Text string `json:"text"`
+}
+
+// parametrosLSP reúne los campos de los parámetros que usa el servidor
+type parametrosLSP struct {
+	TextDocument   documentoLSP   `json:"textDocument"`
+	Position       posicionLSP    `json:"position"`
+	ContentChanges []documentoLSP `json:"contentChanges"`
+}
+
+// ServirLSP atiende mensajes hasta recibir exit o agotar la entrada y
+// devuelve el código de salida: 0 si antes se recibió shutdown, 1 si no.
+// Los errores al leer o escribir mensajes se informan en errores.
+func ServirLSP(entrada io.Reader, salida, errores io.Writer) int {
+	sv := &servidorLSP{salida: salida, errores: errores, analisis: make(map[string]analisisTDiag)}
+	lector := bufio.NewReader(entrada)
+	for {
+		contenido, err := leerMensajeLSP(lector)
+		if err != nil {
+			if err != io.EOF {
+				fmt.Fprintf(sv.errores, "Error leyendo mensaje: %v\n", err)
+			}
+			return 1
+		}
+		if fin := sv.atender(contenido); fin {
+			if sv.apagado {
+				return 0
+			}
+			return 1
+		}
+	}
+}
+
+// atender procesa un mensaje y devuelve true si se pidió terminar
+func (sv *servidorLSP) atender(contenido []byte) bool {
+	var mensaje struct {
+		ID     json.RawMessage `json:"id"`
+		Metodo string          `json:"method"`
+		Params parametrosLSP   `json:"params"`
+	}
+	if err := json.Unmarshal(contenido, &mensaje); err != nil {
+		sv.responderError(json.RawMessage("null"), errorAnalisisRPC, fmt.Sprintf("mensaje inválido: %v", err))
+		return false
+	}
+	// Las notificaciones no tienen id y no llevan respuesta
+	esSolicitud := len(mensaje.ID) > 0
+	params := mensaje.Params
+
+	if sv.apagado && mensaje.Metodo != "exit" {
+		if esSolicitud {
+			sv.responderError(mensaje.ID, errorSolicitudRPC, "el servidor ya recibió shutdown")
+		}
+		return false
+	}
+
+	switch mensaje.Metodo {
+	case "initialize":
+		sv.responder(mensaje.ID, map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync":   1, // se envía siempre el documento completo
+				"hoverProvider":      true,
+				"definitionProvider": true,
+			},
+			"serverInfo": map[string]string{"name": "tdiagram"},
+		})
+
+	case "initialized":
+
+	case "shutdown":
+		sv.apagado = true
+		sv.responder(mensaje.ID, nil)
+
+	case "exit":
+		return true
+
+	case "textDocument/didOpen":
+		sv.actualizar(params.TextDocument.URI, params.TextDocument.Text)
+
+	case "textDocument/didChange":
+		if len(params.ContentChanges) > 0 {
+			sv.actualizar(params.TextDocument.URI, params.ContentChanges[len(params.ContentChanges)-1].Text)
+		}
+
+	case "textDocument/didClose":
+		delete(sv.analisis, params.TextDocument.URI)
+		sv.publicar(params.TextDocument.URI, make([]diagnosticoLSP, 0))
+
+	case "textDocument/hover":
+		analisis, abierto := sv.analisis[params.TextDocument.URI]
+		if !abierto {
+			sv.responderError(mensaje.ID, errorParametrosRPC, "documento no abierto: "+params.TextDocument.URI)
+			return false
+		}
+		sim, ok := analisis.simboloEn(params.Position)
+		if !ok {
+			sv.responder(mensaje.ID, nil)
+			return false
+		}
+		sv.responder(mensaje.ID, map[string]interface{}{
+			"contents": map[string]string{"kind": "markdown", "value": describirSimbolo(sim)},
+			"range":    sim.rango(),
+		})
+
+	case "textDocument/definition":
+		analisis, abierto := sv.analisis[params.TextDocument.URI]
+		if !abierto {
+			sv.responderError(mensaje.ID, errorParametrosRPC, "documento no abierto: "+params.TextDocument.URI)
+			return false
+		}
+		sim, ok := analisis.simboloEn(params.Position)
+		if ok {
+			sim, ok = analisis.definicion(sim.nombre, sim.tipo, sim.sistema)
+		}
+		if !ok {
+			sv.responder(mensaje.ID, nil)
+			return false
+		}
+		sv.responder(mensaje.ID, ubicacionLSP{URI: params.TextDocument.URI, Rango: sim.rango()})
+
+	default:
+		if esSolicitud {
+			sv.responderError(mensaje.ID, errorMetodoDesconocido, "método no soportado: "+mensaje.Metodo)
+		}
+	}
+	return false
+}
+
+// actualizar analiza el nuevo contenido de un documento y publica sus diagnósticos
+func (sv *servidorLSP) actualizar(uri, texto string) {
+	analisis := analizarTDiag(texto, directorioDeURI(uri))
+	sv.analisis[uri] = analisis
+	sv.publicar(uri, analisis.diagnosticos)
+}
+
+// publicar envía los diagnósticos de un documento
+func (sv *servidorLSP) publicar(uri string, diagnosticos []diagnosticoLSP) {
+	sv.enviar(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "textDocument/publishDiagnostics",
+		"params":  map[string]interface{}{"uri": uri, "diagnostics": diagnosticos},
+	})
+}
+
+// responder envía el resultado de una solicitud
+func (sv *servidorLSP) responder(id json.RawMessage, resultado interface{}) {
+	sv.enviar(map[string]interface{}{"jsonrpc": "2.0", "id": id, "result": resultado})
+}
+
+// responderError envía un error como respuesta a una solicitud
+func (sv *servidorLSP) responderError(id json.RawMessage, codigo int, mensaje string) {
+	sv.enviar(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"error":   map[string]interface{}{"code": codigo, "message": mensaje},
+	})
+}
+
+// enviar escribe un mensaje; si la salida falla no hay a quién avisar más
+// que a la salida de errores
+func (sv *servidorLSP) enviar(mensaje interface{}) {
+	if err := escribirMensajeLSP(sv.salida, mensaje); err != nil {
+		fmt.Fprintf(sv.errores, "Error escribiendo mensaje: %v\n", err)
+	}
+}
@@ -0,0 +1,238 @@
+package tdiagram
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// documentoDeEjemplo tiene una línea mal formada (2), un programa duplicado
+// (5) y una referencia a un lenguaje que nada define (6)
+const documentoDeEjemplo = `DEFINIR PROGRAMA servidor Go
+DEFINIR INTERPRETE LOCAL
+DEFINIR INTERPRETE LOCAL Java
+DEFINIR INTERPRETE Java Go
+DEFINIR PROGRAMA servidor C
+DEFINIR TRADUCTOR Rust Go Java
+EJECUTABLE servidor
+`
+
+// buscarDiagnostico devuelve el primer diagnóstico de la línea dada
+func buscarDiagnostico(diagnosticos []diagnosticoLSP, linea int) (diagnosticoLSP, bool) {
+	for _, d := range diagnosticos {
+		if d.Rango.Inicio.Linea == linea {
+			return d, true
+		}
+	}
+	return diagnosticoLSP{}, false
+}
+
+// TestTokensDeLinea verifica las columnas en unidades UTF-16
+func TestTokensDeLinea(t *testing.T) {
+	tokens := tokensDeLinea("  DEFINIR  PROGRAMA año 𝄞x\r")
+	esperados := []tokenLinea{{"DEFINIR", 2, 9}, {"PROGRAMA", 11, 19}, {"año", 20, 23}, {"𝄞x", 24, 27}}
+	if len(tokens) != len(esperados) {
+		t.Fatalf("Tokens inesperados: %+v", tokens)
+	}
+	for i := range tokens {
+		if tokens[i] != esperados[i] {
+			t.Errorf("Token %d: se obtuvo %+v, se esperaba %+v", i, tokens[i], esperados[i])
+		}
+	}
+}
+
+// TestAnalizarDiagnosticos verifica los tres tipos de diagnóstico
+func TestAnalizarDiagnosticos(t *testing.T) {
+	analisis := analizarTDiag(documentoDeEjemplo, ".")
+
+	if len(analisis.diagnosticos) != 3 {
+		t.Fatalf("Se esperaban 3 diagnósticos: %+v", analisis.diagnosticos)
+	}
+	d, ok := buscarDiagnostico(analisis.diagnosticos, 1)
+	if !ok || d.Severidad != severidadError || !strings.HasPrefix(d.Mensaje, "DEFINIR INTERPRETE requiere") {
+		t.Errorf("Se esperaba un error de sintaxis en la línea 2: %+v", d)
+	}
+	d, ok = buscarDiagnostico(analisis.diagnosticos, 4)
+	if !ok || d.Mensaje != "Ya existe un programa con el nombre 'servidor' (definido en la línea 1)" {
+		t.Errorf("Se esperaba un programa duplicado en la línea 5: %+v", d)
+	}
+	d, ok = buscarDiagnostico(analisis.diagnosticos, 5)
+	if !ok || d.Severidad != severidadAdvertencia || d.Rango.Inicio.Caracter != 18 || d.Rango.Fin.Caracter != 22 ||
+		!strings.Contains(d.Mensaje, "'Rust'") {
+		t.Errorf("Se esperaba una advertencia sobre 'Rust' en la línea 6: %+v", d)
+	}
+}
+
+// TestAnalizarEntornos verifica que cada entorno se analiza por separado
+func TestAnalizarEntornos(t *testing.T) {
+	documento := `DEFINIR PROGRAMA p Go
+ENTORNO NUEVO otro
+DEFINIR PROGRAMA p Go
+SALIR
+DEFINIR PROGRAMA p Go`
+	analisis := analizarTDiag(documento, ".")
+
+	for _, d := range analisis.diagnosticos {
+		if d.Severidad == severidadError {
+			t.Errorf("Un mismo nombre en otro entorno o después de SALIR no es un error: %+v", d)
+		}
+	}
+}
+
+// TestAnalizarImportar verifica que los manifiestos se buscan junto al
+// documento y que sus problemas se informan en la línea IMPORTAR
+func TestAnalizarImportar(t *testing.T) {
+	directorio := t.TempDir()
+	manifiesto := "[[interprete]]\nbase = \"LOCAL\"\nlenguaje = \"Go\"\ncolor = \"azul\"\n"
+	if err := os.WriteFile(filepath.Join(directorio, "m.toml"), []byte(manifiesto), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	analisis := analizarTDiag("DEFINIR PROGRAMA p Go\nIMPORTAR m.toml\nIMPORTAR falta.toml\n", directorio)
+	d, ok := buscarDiagnostico(analisis.diagnosticos, 1)
+	if !ok || d.Severidad != severidadAdvertencia || !strings.HasPrefix(d.Mensaje, "m.toml:4:9: campo desconocido") {
+		t.Errorf("Se esperaba el campo desconocido del manifiesto: %+v", analisis.diagnosticos)
+	}
+	if d, ok := buscarDiagnostico(analisis.diagnosticos, 2); !ok || d.Severidad != severidadError {
+		t.Errorf("Se esperaba un error por el manifiesto inexistente: %+v", analisis.diagnosticos)
+	}
+	if _, ok := buscarDiagnostico(analisis.diagnosticos, 0); ok {
+		t.Error("'Go' lo define el manifiesto importado")
+	}
+}
+
+// TestHoverYDefinicion verifica la descripción y la definición de los símbolos
+func TestHoverYDefinicion(t *testing.T) {
+	analisis := analizarTDiag(documentoDeEjemplo, ".")
+
+	sim, ok := analisis.simboloEn(posicionLSP{Linea: 6, Caracter: 12})
+	if !ok || sim.nombre != "servidor" {
+		t.Fatalf("Se esperaba el programa 'servidor': %+v", sim)
+	}
+	descripcion := describirSimbolo(sim)
+	if !strings.Contains(descripcion, "**programa** `servidor`, escrito en `Go`") ||
+		!strings.Contains(descripcion, "- intérprete de 'Go' escrito en 'Java'") {
+		t.Errorf("Descripción inesperada: %q", descripcion)
+	}
+	if def, ok := analisis.definicion(sim.nombre, sim.tipo, sim.sistema); !ok || def.linea != 0 {
+		t.Errorf("'servidor' se define en la primera línea: %+v", def)
+	}
+
+	sim, _ = analisis.simboloEn(posicionLSP{Linea: 5, Caracter: 19})
+	if descripcion := describirSimbolo(sim); !strings.Contains(descripcion, "No es ejecutable") {
+		t.Errorf("'Rust' no debería ser ejecutable: %q", descripcion)
+	}
+	sim, _ = analisis.simboloEn(posicionLSP{Linea: 0, Caracter: 27})
+	if def, ok := analisis.definicion(sim.nombre, sim.tipo, sim.sistema); !ok || def.linea != 3 {
+		t.Errorf("'Go' se define con el intérprete de la línea 4: %+v", def)
+	}
+}
+
+// conversacionLSP envía los mensajes al servidor y devuelve sus respuestas
+// junto con el código de salida
+func conversacionLSP(t *testing.T, mensajes ...string) ([]map[string]interface{}, int) {
+	t.Helper()
+	var entrada, salida bytes.Buffer
+	for _, m := range mensajes {
+		if err := escribirMensajeLSP(&entrada, json.RawMessage(m)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	codigo := ServirLSP(&entrada, &salida, io.Discard)
+
+	respuestas := make([]map[string]interface{}, 0)
+	lector := bufio.NewReader(&salida)
+	for lector.Buffered() > 0 || salida.Len() > 0 {
+		contenido, err := leerMensajeLSP(lector)
+		if err != nil {
+			t.Fatalf("Respuesta mal formada: %v", err)
+		}
+		var respuesta map[string]interface{}
+		if err := json.Unmarshal(contenido, &respuesta); err != nil {
+			t.Fatal(err)
+		}
+		respuestas = append(respuestas, respuesta)
+	}
+	return respuestas, codigo
+}
+
+// TestServidorLSP verifica una conversación completa con el servidor
+func TestServidorLSP(t *testing.T) {
+	texto, _ := json.Marshal(documentoDeEjemplo)
+	respuestas, codigo := conversacionLSP(t,
+		`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {}}`,
+		`{"jsonrpc": "2.0", "method": "initialized", "params": {}}`,
+		`{"jsonrpc": "2.0", "method": "textDocument/didOpen", "params": {"textDocument": {"uri": "file:///tmp/a.tdiag", "text": `+string(texto)+`}}}`,
+		`{"jsonrpc": "2.0", "id": 2, "method": "textDocument/hover", "params": {"textDocument": {"uri": "file:///tmp/a.tdiag"}, "position": {"line": 6, "character": 12}}}`,
+		`{"jsonrpc": "2.0", "id": 3, "method": "textDocument/definition", "params": {"textDocument": {"uri": "file:///tmp/a.tdiag"}, "position": {"line": 0, "character": 27}}}`,
+		`{"jsonrpc": "2.0", "id": 4, "method": "textDocument/formatting", "params": {}}`,
+		`{"jsonrpc": "2.0", "id": 5, "method": "shutdown"}`,
+		`{"jsonrpc": "2.0", "method": "exit"}`,
+	)
+
+	if codigo != 0 {
+		t.Errorf("Tras shutdown y exit el código debería ser 0, se obtuvo %d", codigo)
+	}
+	if len(respuestas) != 6 {
+		t.Fatalf("Se esperaban 6 mensajes: %+v", respuestas)
+	}
+	capacidades := respuestas[0]["result"].(map[string]interface{})["capabilities"].(map[string]interface{})
+	if capacidades["hoverProvider"] != true || capacidades["definitionProvider"] != true {
+		t.Errorf("Capacidades inesperadas: %+v", capacidades)
+	}
+	if respuestas[1]["method"] != "textDocument/publishDiagnostics" ||
+		len(respuestas[1]["params"].(map[string]interface{})["diagnostics"].([]interface{})) != 3 {
+		t.Errorf("Se esperaban 3 diagnósticos publicados: %+v", respuestas[1])
+	}
+	contenido := respuestas[2]["result"].(map[string]interface{})["contents"].(map[string]interface{})
+	if !strings.Contains(contenido["value"].(string), "Ejecutable mediante") {
+		t.Errorf("Hover inesperado: %+v", contenido)
+	}
+	ubicacion := respuestas[3]["result"].(map[string]interface{})
+	if ubicacion["range"].(map[string]interface{})["start"].(map[string]interface{})["line"] != 3.0 {
+		t.Errorf("Definición inesperada: %+v", ubicacion)
+	}
+	if respuestas[4]["error"].(map[string]interface{})["code"] != float64(errorMetodoDesconocido) {
+		t.Errorf("Se esperaba un método no soportado: %+v", respuestas[4])
+	}
+	if resultado, ok := respuestas[5]["result"]; !ok || resultado != nil {
+		t.Errorf("shutdown debería responder con result null: %+v", respuestas[5])
+	}
+}
+
+// TestServidorLSPSinShutdown verifica el código de salida si falta shutdown
+func TestServidorLSPSinShutdown(t *testing.T) {
+	if _, codigo := conversacionLSP(t, `{"jsonrpc": "2.0", "method": "exit"}`); codigo != 1 {
+		t.Errorf("Sin shutdown el código debería ser 1, se obtuvo %d", codigo)
+	}
+	if _, codigo := conversacionLSP(t); codigo != 1 {
+		t.Errorf("Al agotarse la entrada el código debería ser 1, se obtuvo %d", codigo)
+	}
+}
+
+// TestLeerMensajeLSPDemasiadoGrande verifica que no se acepta un
+// Content-Length mayor que el máximo
+func TestLeerMensajeLSPDemasiadoGrande(t *testing.T) {
+	entrada := fmt.Sprintf("Content-Length: %d\r\n\r\n{}", largoMaximoLSP+1)
+	if _, err := leerMensajeLSP(bufio.NewReader(strings.NewReader(entrada))); err == nil {
+		t.Error("Debería dar error con un mensaje demasiado grande")
+	}
+}
+
+// TestServidorLSPErrores verifica que los errores de lectura se informan en
+// el escritor indicado
+func TestServidorLSPErrores(t *testing.T) {
+	var salida, errores bytes.Buffer
+	if codigo := ServirLSP(strings.NewReader("Content-Length: x\r\n\r\n"), &salida, &errores); codigo != 1 {
+		t.Errorf("Con un mensaje inválido el código debería ser 1, se obtuvo %d", codigo)
+	}
+	if !strings.Contains(errores.String(), "Content-Length inválida") {
+		t.Errorf("Se esperaba el error en el escritor de errores: %q", errores.String())
+	}
+}
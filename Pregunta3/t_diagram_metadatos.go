@@ -0,0 +1,150 @@
+package tdiagram
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// Acceso a los metadatos desde otros paquetes. Los campos de Metadatos no se
+// exportan para que siempre cumplan las mismas reglas que las opciones de
+// DEFINIR: se leen con los métodos de consulta y se arman con los métodos
+// Con..., que devuelven una copia modificada. Un intérprete o traductor con
+// metadatos se define con DefinirInterpreteCon o DefinirTraductorCon:
+//
+//	m := tdiagram.Metadatos{}.ConCosto(3).ConVersion("11.2")
+//	err := s.DefinirInterpreteCon("LOCAL", "C", m)
+
+// Version devuelve la versión declarada, o una cadena vacía
+func (m Metadatos) Version() string {
+	return m.version
+}
+
+// Costo devuelve el costo de instalación y si fue declarado
+func (m Metadatos) Costo() (float64, bool) {
+	return m.costo, m.costoDeclarado
+}
+
+// Caracteristicas devuelve una copia de las características declaradas
+func (m Metadatos) Caracteristicas() []string {
+	return append([]string(nil), m.caracteristicas...)
+}
+
+// Desde devuelve el primer día de disponibilidad (cero: sin límite)
+func (m Metadatos) Desde() time.Time {
+	return m.desde
+}
+
+// Hasta devuelve el día en que deja de estar disponible (cero: sin límite)
+func (m Metadatos) Hasta() time.Time {
+	return m.hasta
+}
+
+// DisponibleEn indica si el componente está disponible en la fecha dada
+func (m Metadatos) DisponibleEn(fecha time.Time) bool {
+	return m.disponibleEn(fecha)
+}
+
+// Confiable indica si el componente es confiable
+func (m Metadatos) Confiable() bool {
+	return !m.noConfiable
+}
+
+// Procedencia devuelve el origen declarado, o una cadena vacía
+func (m Metadatos) Procedencia() string {
+	return m.procedencia
+}
+
+// ConVersion devuelve los metadatos con la versión dada
+func (m Metadatos) ConVersion(version string) Metadatos {
+	m.version = version
+	return m
+}
+
+// ConCosto devuelve los metadatos con el costo de instalación dado
+func (m Metadatos) ConCosto(costo float64) Metadatos {
+	m.costo = costo
+	m.costoDeclarado = true
+	return m
+}
+
+// ConCaracteristicas devuelve los metadatos con las características dadas
+func (m Metadatos) ConCaracteristicas(caracteristicas ...string) Metadatos {
+	m.caracteristicas = append([]string(nil), caracteristicas...)
+	return m
+}
+
+// ConVentana devuelve los metadatos con la ventana de disponibilidad dada.
+// Solo se conserva el día de cada fecha; una fecha cero deja ese extremo
+// sin límite.
+func (m Metadatos) ConVentana(desde, hasta time.Time) Metadatos {
+	m.desde, m.hasta = soloDia(desde), soloDia(hasta)
+	return m
+}
+
+// ConConfianza devuelve los metadatos con el nivel de confianza y la
+// procedencia dados
+func (m Metadatos) ConConfianza(confiable bool, procedencia string) Metadatos {
+	m.noConfiable = !confiable
+	m.procedencia = procedencia
+	return m
+}
+
+// soloDia descarta la hora de una fecha, como hace analizarFecha
+func soloDia(fecha time.Time) time.Time {
+	if fecha.IsZero() {
+		return fecha
+	}
+	return time.Date(fecha.Year(), fecha.Month(), fecha.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// validar comprueba que los metadatos puedan escribirse como opciones de
+// DEFINIR y que el costo y la ventana tengan sentido
+func (m Metadatos) validar() error {
+	if m.costoDeclarado && (m.costo < 0 || math.IsNaN(m.costo) || math.IsInf(m.costo, 0)) {
+		return fmt.Errorf("ERROR: Costo inválido '%g' (se espera un número finito no negativo)", m.costo)
+	}
+	if !m.desde.IsZero() && !m.hasta.IsZero() && !m.desde.Before(m.hasta) {
+		return fmt.Errorf("ERROR: La fecha HASTA debe ser posterior a la fecha DESDE")
+	}
+	for _, texto := range []string{m.version, m.procedencia} {
+		if strings.ContainsAny(texto, " \t") {
+			return fmt.Errorf("ERROR: '%s' no puede contener espacios", texto)
+		}
+	}
+	for _, c := range m.caracteristicas {
+		if c == "" || strings.ContainsAny(c, " \t,") {
+			return fmt.Errorf("ERROR: Característica inválida '%s' (no puede estar vacía ni contener espacios o comas)", c)
+		}
+	}
+	return nil
+}
+
+// Metadatos devuelve los metadatos del intérprete
+func (i Interpreter) Metadatos() Metadatos {
+	return i.metadatos
+}
+
+// Metadatos devuelve los metadatos del traductor
+func (t Translator) Metadatos() Metadatos {
+	return t.metadatos
+}
+
+// DefinirInterpreteCon define un intérprete con metadatos
+func (s *System) DefinirInterpreteCon(lenguajeBase, lenguajeInterpretado string, m Metadatos) error {
+	if err := m.validar(); err != nil {
+		return err
+	}
+	s.agregarInterprete(Interpreter{Base: lenguajeBase, Language: lenguajeInterpretado, metadatos: m})
+	return nil
+}
+
+// DefinirTraductorCon define un traductor con metadatos
+func (s *System) DefinirTraductorCon(lenguajeBase, lenguajeOrigen, lenguajeDestino string, m Metadatos) error {
+	if err := m.validar(); err != nil {
+		return err
+	}
+	s.agregarTraductor(Translator{Base: lenguajeBase, Source: lenguajeOrigen, Target: lenguajeDestino, metadatos: m})
+	return nil
+}
@@ -0,0 +1,95 @@
+package tdiagram
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestDefinirConMetadatos verifica que los metadatos definidos desde la API
+// se leen con los métodos de consulta y producen el mismo comando que DEFINIR
+func TestDefinirConMetadatos(t *testing.T) {
+	s := sistemaConReloj()
+	desde := time.Date(2024, 1, 1, 15, 30, 0, 0, time.Local)
+	m := Metadatos{}.
+		ConVersion("11.2").
+		ConCosto(2.5).
+		ConCaracteristicas("optimizador", "depurador").
+		ConVentana(desde, time.Time{}).
+		ConConfianza(false, "externo")
+	if err := s.DefinirTraductorCon("LOCAL", "C", "LOCAL", m); err != nil {
+		t.Fatal(err)
+	}
+
+	leidos := s.traductores[0].Metadatos()
+	if costo, ok := leidos.Costo(); leidos.Version() != "11.2" || costo != 2.5 || !ok ||
+		!reflect.DeepEqual(leidos.Caracteristicas(), []string{"optimizador", "depurador"}) ||
+		leidos.Confiable() || leidos.Procedencia() != "externo" || !leidos.Hasta().IsZero() {
+		t.Errorf("Metadatos inesperados: %+v", leidos)
+	}
+	if !leidos.Desde().Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("La ventana debería conservar solo el día: %v", leidos.Desde())
+	}
+	if leidos.DisponibleEn(time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Error("No debería estar disponible antes de DESDE")
+	}
+	leidos.Caracteristicas()[0] = "otra"
+	if s.traductores[0].Metadatos().Caracteristicas()[0] != "optimizador" {
+		t.Error("Caracteristicas debería devolver una copia")
+	}
+
+	esperado := "DEFINIR TRADUCTOR LOCAL C LOCAL DESDE 2024-01-01 NOCONFIABLE PROCEDENCIA externo " +
+		"VERSION 11.2 COSTO 2.5 CARACTERISTICAS optimizador,depurador"
+	if comando := s.Historial()[0].Comando; comando != esperado {
+		t.Fatalf("Comando inesperado:\n%s\nse esperaba:\n%s", comando, esperado)
+	}
+
+	var buf bytes.Buffer
+	if err := s.ExportarHistorial(&buf); err != nil {
+		t.Fatal(err)
+	}
+	copia := NuevoSistema()
+	copia.salida = io.Discard
+	if _, err := copia.ReproducirHistorial(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(copia.traductores, s.traductores) {
+		t.Errorf("La reproducción debería conservar los metadatos: %+v", copia.traductores)
+	}
+}
+
+// TestDefinirConMetadatosInvalidos verifica que se rechazan los metadatos
+// que DEFINIR no aceptaría
+func TestDefinirConMetadatosInvalidos(t *testing.T) {
+	dia := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	casos := map[string]Metadatos{
+		"costo negativo":          Metadatos{}.ConCosto(-1),
+		"costo NaN":               Metadatos{}.ConCosto(math.NaN()),
+		"costo infinito":          Metadatos{}.ConCosto(math.Inf(1)),
+		"ventana vacía":           Metadatos{}.ConVentana(dia(2), dia(2)),
+		"versión con espacios":    Metadatos{}.ConVersion("11 2"),
+		"procedencia con espacio": Metadatos{}.ConConfianza(true, "sitio externo"),
+		"característica con coma": Metadatos{}.ConCaracteristicas("a,b"),
+		"característica vacía":    Metadatos{}.ConCaracteristicas(""),
+	}
+	for nombre, m := range casos {
+		s := sistemaConReloj()
+		if err := s.DefinirInterpreteCon("LOCAL", "Go", m); err == nil {
+			t.Errorf("%s: se esperaba un error", nombre)
+		}
+		if len(s.interpretes) != 0 || len(s.Historial()) != 0 {
+			t.Errorf("%s: no debería definirse nada", nombre)
+		}
+	}
+
+	s := sistemaConReloj()
+	if err := s.DefinirInterpreteCon("LOCAL", "Go", Metadatos{}.ConVentana(dia(1), dia(2))); err != nil {
+		t.Errorf("Una ventana válida no debería dar error: %v", err)
+	}
+	if m := s.interpretes[0].Metadatos(); !m.Confiable() || m.Version() != "" {
+		t.Errorf("El valor cero debería ser confiable y sin versión: %+v", m)
+	}
+}
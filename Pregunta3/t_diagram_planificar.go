@@ -0,0 +1,231 @@
+package tdiagram
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// limiteBusquedaExacta es la cantidad máxima de componentes para la que el
+// planificador busca la solución óptima; por encima usa una heurística
+const limiteBusquedaExacta = 20
+
+// costoPorDefecto es el costo de los componentes que no declaran uno
+const costoPorDefecto = 1.0
+
+// Plan es un conjunto de intérpretes y traductores que permite ejecutar los
+// programas pedidos. Exacto indica si se garantiza que su costo es mínimo.
+type Plan struct {
+	Componentes []Paso
+	Costos      []float64
+	Costo       float64
+	Exacto      bool
+}
+
+// componenteConCosto asocia un componente con su costo de instalación
+type componenteConCosto struct {
+	paso  Paso
+	costo float64
+}
+
+// catalogo devuelve los componentes del sistema con su costo. Si un
+// componente está definido varias veces se toma el menor de sus costos.
+func (s *System) catalogo() []componenteConCosto {
+	costos := make(map[Paso]float64)
+	registrar := func(p Paso, m Metadatos) {
+		costo := costoPorDefecto
+		if m.costoDeclarado {
+			costo = m.costo
+		}
+		if anterior, existe := costos[p]; !existe || costo < anterior {
+			costos[p] = costo
+		}
+	}
+	for _, interp := range s.interpretes {
+		registrar(interp.paso(), interp.metadatos)
+	}
+	for _, trad := range s.traductores {
+		registrar(trad.paso(), trad.metadatos)
+	}
+
+	resultado := make([]componenteConCosto, 0, len(costos))
+	for _, p := range s.componentes() {
+		resultado = append(resultado, componenteConCosto{paso: p, costo: costos[p]})
+	}
+	return resultado
+}
+
+// Planificar calcula un conjunto de intérpretes y traductores de costo
+// mínimo que permite ejecutar todos los programas indicados
+func (s *System) Planificar(programas []string) (Plan, error) {
+	return s.planificar(programas, limiteBusquedaExacta)
+}
+
+func (s *System) planificar(programas []string, limite int) (Plan, error) {
+	lenguajes := make([]string, 0, len(programas))
+	for _, nombre := range programas {
+		programa, existe := s.programas[nombre]
+		if !existe {
+			return Plan{}, fmt.Errorf("ERROR: No existe un programa con el nombre '%s'", nombre)
+		}
+		lenguajes = append(lenguajes, programa.Language)
+	}
+
+	catalogo := s.catalogo()
+	// Se prueban primero los componentes más caros para que la heurística
+	// los descarte antes y la búsqueda exacta pode más rápido
+	sort.SliceStable(catalogo, func(i, j int) bool { return catalogo[i].costo > catalogo[j].costo })
+
+	todos := make([]bool, len(catalogo))
+	for i := range todos {
+		todos[i] = true
+	}
+	if faltantes := s.noCubiertos(catalogo, todos, lenguajes); len(faltantes) > 0 {
+		nombres := make([]string, 0)
+		for _, nombre := range programas {
+			for _, l := range faltantes {
+				if s.programas[nombre].Language == l {
+					nombres = append(nombres, nombre)
+				}
+			}
+		}
+		return Plan{}, fmt.Errorf("ERROR: Ningún conjunto de componentes permite ejecutar: %s",
+			strings.Join(nombres, ", "))
+	}
+
+	var elegidos []bool
+	exacto := len(catalogo) <= limite
+	if exacto {
+		elegidos = s.busquedaExacta(catalogo, lenguajes)
+	} else {
+		elegidos = s.eliminacionInversa(catalogo, lenguajes)
+	}
+
+	plan := Plan{Componentes: make([]Paso, 0), Costos: make([]float64, 0), Exacto: exacto}
+	for i := len(catalogo) - 1; i >= 0; i-- {
+		if elegidos[i] {
+			plan.Componentes = append(plan.Componentes, catalogo[i].paso)
+			plan.Costos = append(plan.Costos, catalogo[i].costo)
+			plan.Costo += catalogo[i].costo
+		}
+	}
+	return plan, nil
+}
+
+// noCubiertos devuelve los lenguajes que no son ejecutables usando solo los
+// componentes elegidos del catálogo
+func (s *System) noCubiertos(catalogo []componenteConCosto, elegidos []bool, lenguajes []string) []string {
+	permitidos := make(map[Paso]bool)
+	for i, c := range catalogo {
+		if elegidos[i] {
+			permitidos[c.paso] = true
+		}
+	}
+	derivados := s.derivacionesFiltradas(func(p Paso, _ Metadatos) bool { return permitidos[p] })
+
+	faltantes := make([]string, 0)
+	for _, l := range lenguajes {
+		if _, ok := derivados[l]; !ok {
+			faltantes = append(faltantes, l)
+		}
+	}
+	return faltantes
+}
+
+// busquedaExacta recorre los subconjuntos del catálogo con ramificación y
+// poda: descarta una rama si su costo ya no mejora la mejor solución o si ni
+// siquiera incluyendo todos los componentes pendientes se cubren los
+// lenguajes. A igual costo se prefiere la solución con menos componentes,
+// para no incluir componentes de costo cero que no hacen falta.
+func (s *System) busquedaExacta(catalogo []componenteConCosto, lenguajes []string) []bool {
+	n := len(catalogo)
+	mejor := make([]bool, n)
+	mejorCosto := 0.0
+	mejorCantidad := n
+	for i := range mejor {
+		mejor[i] = true
+		mejorCosto += catalogo[i].costo
+	}
+
+	actual := make([]bool, n)
+	var explorar func(i int, costo float64, cantidad int)
+	explorar = func(i int, costo float64, cantidad int) {
+		if costo > mejorCosto || (costo == mejorCosto && cantidad >= mejorCantidad) {
+			return
+		}
+		// Cota: con los elegidos y todos los pendientes, ¿se cubre todo?
+		optimista := make([]bool, n)
+		copy(optimista, actual)
+		for j := i; j < n; j++ {
+			optimista[j] = true
+		}
+		if len(s.noCubiertos(catalogo, optimista, lenguajes)) > 0 {
+			return
+		}
+		if len(s.noCubiertos(catalogo, actual, lenguajes)) == 0 {
+			mejorCosto = costo
+			mejorCantidad = cantidad
+			copy(mejor, actual)
+			return
+		}
+		if i == n {
+			return
+		}
+
+		actual[i] = true
+		explorar(i+1, costo+catalogo[i].costo, cantidad+1)
+		actual[i] = false
+		explorar(i+1, costo, cantidad)
+	}
+	explorar(0, 0, 0)
+	return mejor
+}
+
+// eliminacionInversa parte de todos los componentes y retira, del más caro
+// al más barato, cada uno cuya ausencia no impida ejecutar los programas.
+// El resultado no tiene componentes sobrantes, aunque puede no ser óptimo.
+func (s *System) eliminacionInversa(catalogo []componenteConCosto, lenguajes []string) []bool {
+	elegidos := make([]bool, len(catalogo))
+	for i := range elegidos {
+		elegidos[i] = true
+	}
+	for i := range catalogo {
+		elegidos[i] = false
+		if len(s.noCubiertos(catalogo, elegidos, lenguajes)) > 0 {
+			elegidos[i] = true
+		}
+	}
+	return elegidos
+}
+
+// mostrarPlan imprime el plan calculado
+func (s *System) mostrarPlan(plan Plan) {
+	tipo := "óptimo"
+	if !plan.Exacto {
+		tipo = "heurístico"
+	}
+	fmt.Fprintf(s.salida, "Plan %s de costo %g:\n", tipo, plan.Costo)
+	if len(plan.Componentes) == 0 {
+		fmt.Fprintln(s.salida, "  no se necesita ningún componente")
+	}
+	for i, componente := range plan.Componentes {
+		fmt.Fprintf(s.salida, "  %s (costo %g)\n", componente, plan.Costos[i])
+	}
+}
+
+// procesarPlanificar atiende el comando PLANIFICAR. Sin argumentos planifica
+// para todos los programas definidos.
+func (s *System) procesarPlanificar(programas []string) error {
+	if len(programas) == 0 {
+		for nombre := range s.programas {
+			programas = append(programas, nombre)
+		}
+		sort.Strings(programas)
+	}
+	plan, err := s.Planificar(programas)
+	if err != nil {
+		return err
+	}
+	s.mostrarPlan(plan)
+	return nil
+}
@@ -0,0 +1,194 @@
+package tdiagram
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// catalogoConCostos construye un sistema con dos formas de ejecutar Java:
+// un intérprete directo caro y una cadena de dos componentes más barata
+func catalogoConCostos(t *testing.T) *System {
+	t.Helper()
+	s := NuevoSistema()
+	s.salida = io.Discard
+	manifiesto := `
+[[programa]]
+nombre = "factorial"
+lenguaje = "Java"
+
+[[programa]]
+nombre = "hola"
+lenguaje = "C"
+
+[[interprete]]
+base = "LOCAL"
+lenguaje = "Java"
+costo = 10
+
+[[interprete]]
+base = "LOCAL"
+lenguaje = "C"
+costo = 2
+
+[[interprete]]
+base = "C"
+lenguaje = "Java"
+costo = 3
+
+[[interprete]]
+base = "LOCAL"
+lenguaje = "Python"
+costo = 1
+`
+	resultado, err := s.ImportarManifiesto("catalogo.toml", strings.NewReader(manifiesto))
+	if err != nil || len(resultado.Problemas) > 0 {
+		t.Fatalf("No se pudo importar el catálogo: %v %v", err, resultado.Problemas)
+	}
+	return s
+}
+
+// TestPlanificarOptimo verifica que se elige la combinación más barata
+func TestPlanificarOptimo(t *testing.T) {
+	s := catalogoConCostos(t)
+
+	plan, err := s.Planificar([]string{"factorial"})
+	if err != nil {
+		t.Fatalf("No debería dar error: %v", err)
+	}
+	if !plan.Exacto || plan.Costo != 5 || len(plan.Componentes) != 2 {
+		t.Errorf("Se esperaba el plan óptimo de costo 5 con 2 componentes: %+v", plan)
+	}
+	for _, c := range plan.Componentes {
+		if c.Destino == "Python" {
+			t.Error("El plan no debería incluir componentes innecesarios")
+		}
+	}
+}
+
+// TestPlanificarCostoCero verifica que un componente de costo cero que no
+// hace falta no se incluye en el plan
+func TestPlanificarCostoCero(t *testing.T) {
+	s := NuevoSistema()
+	s.salida = io.Discard
+	manifiesto := `
+[[programa]]
+nombre = "hola"
+lenguaje = "C"
+
+[[interprete]]
+base = "LOCAL"
+lenguaje = "C"
+costo = 0
+
+[[interprete]]
+base = "LOCAL"
+lenguaje = "Python"
+costo = 0
+`
+	if _, err := s.ImportarManifiesto("catalogo.toml", strings.NewReader(manifiesto)); err != nil {
+		t.Fatalf("No se pudo importar el catálogo: %v", err)
+	}
+
+	plan, err := s.Planificar([]string{"hola"})
+	if err != nil {
+		t.Fatalf("No debería dar error: %v", err)
+	}
+	if plan.Costo != 0 || len(plan.Componentes) != 1 || plan.Componentes[0].Destino != "C" {
+		t.Errorf("Se esperaba solo el intérprete de C: %+v", plan)
+	}
+}
+
+// TestPlanificarVariosProgramas verifica que los componentes compartidos se
+// aprovechan entre programas
+func TestPlanificarVariosProgramas(t *testing.T) {
+	s := catalogoConCostos(t)
+
+	plan, err := s.Planificar([]string{"factorial", "hola"})
+	if err != nil {
+		t.Fatalf("No debería dar error: %v", err)
+	}
+	if plan.Costo != 5 {
+		t.Errorf("El intérprete de C sirve a ambos programas, costo esperado 5: %+v", plan)
+	}
+}
+
+// TestPlanificarProgramaEnLOCAL verifica que un programa en LOCAL no necesita
+// ningún componente
+func TestPlanificarProgramaEnLOCAL(t *testing.T) {
+	s := catalogoConCostos(t)
+	s.DefinirPrograma("nativo", "LOCAL")
+
+	plan, err := s.Planificar([]string{"nativo"})
+	if err != nil || len(plan.Componentes) != 0 || plan.Costo != 0 {
+		t.Errorf("Se esperaba un plan vacío: %+v %v", plan, err)
+	}
+}
+
+// TestPlanificarHeuristico verifica que la heurística devuelve un conjunto
+// suficiente y sin componentes sobrantes
+func TestPlanificarHeuristico(t *testing.T) {
+	s := catalogoConCostos(t)
+
+	plan, err := s.planificar([]string{"factorial"}, 0)
+	if err != nil {
+		t.Fatalf("No debería dar error: %v", err)
+	}
+	if plan.Exacto {
+		t.Error("El plan heurístico no debería marcarse como exacto")
+	}
+	if plan.Costo != 5 {
+		t.Errorf("Al retirar primero lo más caro se esperaba costo 5: %+v", plan)
+	}
+}
+
+// TestPlanificarCatalogoGrande verifica que con muchos componentes se usa la
+// heurística y el plan sigue siendo válido
+func TestPlanificarCatalogoGrande(t *testing.T) {
+	s := NuevoSistema()
+	s.salida = io.Discard
+	s.DefinirPrograma("p", "L30")
+	base := "LOCAL"
+	for i := 1; i <= 30; i++ {
+		s.DefinirInterprete(base, fmt.Sprintf("L%d", i))
+		s.DefinirInterprete("LOCAL", fmt.Sprintf("X%d", i))
+		base = fmt.Sprintf("L%d", i)
+	}
+
+	plan, err := s.Planificar([]string{"p"})
+	if err != nil {
+		t.Fatalf("No debería dar error: %v", err)
+	}
+	if plan.Exacto || len(plan.Componentes) != 30 {
+		t.Errorf("Se esperaba un plan heurístico con la cadena de 30 intérpretes: %d componentes",
+			len(plan.Componentes))
+	}
+}
+
+// TestPlanificarImposible verifica el error cuando ningún conjunto sirve
+func TestPlanificarImposible(t *testing.T) {
+	s := catalogoConCostos(t)
+	s.DefinirPrograma("script", "Ruby")
+
+	if _, err := s.Planificar([]string{"factorial", "script"}); err == nil ||
+		!strings.Contains(err.Error(), "script") {
+		t.Errorf("Se esperaba un error que mencione 'script': %v", err)
+	}
+	if _, err := s.Planificar([]string{"noexiste"}); err == nil {
+		t.Error("Debería dar error con un programa inexistente")
+	}
+}
+
+// TestComandoPlanificar verifica la salida del comando PLANIFICAR
+func TestComandoPlanificar(t *testing.T) {
+	s := catalogoConCostos(t)
+	var buf bytes.Buffer
+	s.salida = &buf
+
+	s.ProcesarComando("PLANIFICAR")
+	if !strings.HasPrefix(buf.String(), "Plan óptimo de costo 5:") {
+		t.Errorf("Salida inesperada: %q", buf.String())
+	}
+}
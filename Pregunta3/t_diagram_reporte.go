@@ -0,0 +1,166 @@
+package tdiagram
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ResultadoPrograma resume la ejecutabilidad de un programa. Confiable
+// indica si el programa puede ejecutarse usando solo componentes confiables;
+// si no, NoConfiables lista los pasos de la cadena que no lo son.
+type ResultadoPrograma struct {
+	Nombre       string `json:"nombre"`
+	Lenguaje     string `json:"lenguaje"`
+	Ejecutable   bool   `json:"ejecutable"`
+	Confiable    bool   `json:"confiable"`
+	Cadena       []Paso `json:"cadena"`
+	NoConfiables []Paso `json:"no_confiables,omitempty"`
+}
+
+// Reporte contiene el resultado de evaluar todos los programas del sistema
+type Reporte struct {
+	Programas     []ResultadoPrograma `json:"programas"`
+	Ejecutables   int                 `json:"ejecutables"`
+	NoEjecutables int                 `json:"no_ejecutables"`
+}
+
+// EvaluarTodos evalúa todos los programas definidos con un único cálculo
+// del punto fijo. Los resultados se ordenan por nombre de programa.
+func (s *System) EvaluarTodos() Reporte {
+	derivados := s.derivaciones()
+	confiables := s.derivacionesFiltradas(esConfiable)
+
+	nombres := make([]string, 0, len(s.programas))
+	for nombre := range s.programas {
+		nombres = append(nombres, nombre)
+	}
+	sort.Strings(nombres)
+
+	reporte := Reporte{Programas: make([]ResultadoPrograma, 0, len(nombres))}
+	for _, nombre := range nombres {
+		programa := s.programas[nombre]
+		pasos, ok := cadena(programa.Language, derivados)
+		if pasos == nil {
+			pasos = make([]Paso, 0)
+		}
+		_, confiable := confiables[programa.Language]
+		resultado := ResultadoPrograma{
+			Nombre:     nombre,
+			Lenguaje:   programa.Language,
+			Ejecutable: ok,
+			Confiable:  confiable,
+			Cadena:     pasos,
+		}
+		if ok && !confiable {
+			resultado.NoConfiables = s.pasosNoConfiables(pasos)
+		}
+		reporte.Programas = append(reporte.Programas, resultado)
+		if ok {
+			reporte.Ejecutables++
+		} else {
+			reporte.NoEjecutables++
+		}
+	}
+	return reporte
+}
+
+// Exitoso indica si todos los programas del reporte son ejecutables
+func (r Reporte) Exitoso() bool {
+	return r.NoEjecutables == 0
+}
+
+// EscribirJSON escribe el reporte en formato JSON
+func (r Reporte) EscribirJSON(w io.Writer) error {
+	codificador := json.NewEncoder(w)
+	codificador.SetIndent("", "  ")
+	return codificador.Encode(r)
+}
+
+// Estructuras del formato JUnit XML
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Nombre  string      `xml:"name,attr"`
+	Pruebas int         `xml:"tests,attr"`
+	Fallos  int         `xml:"failures,attr"`
+	Casos   []junitCaso `xml:"testcase"`
+}
+
+type junitCaso struct {
+	Nombre string      `xml:"name,attr"`
+	Clase  string      `xml:"classname,attr"`
+	Fallo  *junitFallo `xml:"failure,omitempty"`
+	Salida string      `xml:"system-out,omitempty"`
+}
+
+type junitFallo struct {
+	Mensaje string `xml:"message,attr"`
+	Texto   string `xml:",chardata"`
+}
+
+// EscribirJUnit escribe el reporte en formato JUnit XML. Cada programa es
+// un caso de prueba que falla si el programa no es ejecutable.
+func (r Reporte) EscribirJUnit(w io.Writer) error {
+	suite := junitSuite{
+		Nombre:  "diagramas-t",
+		Pruebas: len(r.Programas),
+		Fallos:  r.NoEjecutables,
+	}
+	for _, res := range r.Programas {
+		caso := junitCaso{Nombre: res.Nombre, Clase: "programas." + res.Lenguaje}
+		if res.Ejecutable {
+			caso.Salida = describirCadena(res.Cadena)
+			if !res.Confiable {
+				caso.Salida += "\nPasa por componentes no confiables: " + describirCadena(res.NoConfiables)
+			}
+		} else {
+			caso.Fallo = &junitFallo{
+				Mensaje: fmt.Sprintf("No es posible ejecutar el programa '%s'", res.Nombre),
+				Texto:   fmt.Sprintf("Ningún intérprete o traductor permite ejecutar '%s'", res.Lenguaje),
+			}
+		}
+		suite.Casos = append(suite.Casos, caso)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	codificador := xml.NewEncoder(w)
+	codificador.Indent("", "  ")
+	if err := codificador.Encode(junitSuites{Suites: []junitSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// describirCadena devuelve una descripción legible de una cadena de pasos
+func describirCadena(pasos []Paso) string {
+	if len(pasos) == 0 {
+		return "ejecutable directamente en LOCAL"
+	}
+	descripciones := make([]string, len(pasos))
+	for i, paso := range pasos {
+		descripciones[i] = paso.String()
+	}
+	return strings.Join(descripciones, " -> ")
+}
+
+// EscribirReporte escribe el reporte en el formato indicado ("json" o "junit")
+func (r Reporte) EscribirReporte(w io.Writer, formato string) error {
+	switch strings.ToLower(formato) {
+	case "json":
+		return r.EscribirJSON(w)
+	case "junit":
+		return r.EscribirJUnit(w)
+	default:
+		return fmt.Errorf("ERROR: Formato de reporte desconocido '%s'", formato)
+	}
+}
@@ -0,0 +1,153 @@
+package tdiagram
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+// sistemaDeEjemplo construye el sistema del enunciado más un programa que
+// no puede ejecutarse
+func sistemaDeEjemplo() *System {
+	s := NuevoSistema()
+	s.salida = io.Discard
+	s.DefinirPrograma("fibonacci", "LOCAL")
+	s.DefinirPrograma("factorial", "Java")
+	s.DefinirPrograma("holamundo", "Python3")
+	s.DefinirInterprete("C", "Java")
+	s.DefinirInterprete("LOCAL", "C")
+	return s
+}
+
+// TestEvaluarTodos verifica el resultado de evaluar todos los programas
+func TestEvaluarTodos(t *testing.T) {
+	reporte := sistemaDeEjemplo().EvaluarTodos()
+
+	if len(reporte.Programas) != 3 {
+		t.Fatalf("Se esperaban 3 programas, se obtuvieron %d", len(reporte.Programas))
+	}
+	if reporte.Ejecutables != 2 || reporte.NoEjecutables != 1 {
+		t.Errorf("Se esperaban 2 ejecutables y 1 no ejecutable, se obtuvo %d y %d",
+			reporte.Ejecutables, reporte.NoEjecutables)
+	}
+	if reporte.Exitoso() {
+		t.Error("El reporte no debería ser exitoso si hay programas no ejecutables")
+	}
+
+	// Los resultados vienen ordenados por nombre
+	esperados := []string{"factorial", "fibonacci", "holamundo"}
+	for i, res := range reporte.Programas {
+		if res.Nombre != esperados[i] {
+			t.Errorf("Posición %d: se esperaba '%s', se obtuvo '%s'", i, esperados[i], res.Nombre)
+		}
+	}
+}
+
+// TestEvaluarTodosCadena verifica la cadena reportada para un programa
+func TestEvaluarTodosCadena(t *testing.T) {
+	reporte := sistemaDeEjemplo().EvaluarTodos()
+	factorial := reporte.Programas[0]
+
+	if !factorial.Ejecutable {
+		t.Fatal("factorial debería ser ejecutable")
+	}
+	esperada := []Paso{
+		{Tipo: "interprete", Base: "LOCAL", Destino: "C"},
+		{Tipo: "interprete", Base: "C", Destino: "Java"},
+	}
+	if len(factorial.Cadena) != len(esperada) {
+		t.Fatalf("Se esperaba una cadena de %d pasos, se obtuvo %v", len(esperada), factorial.Cadena)
+	}
+	for i := range esperada {
+		if factorial.Cadena[i] != esperada[i] {
+			t.Errorf("Paso %d: se esperaba %v, se obtuvo %v", i, esperada[i], factorial.Cadena[i])
+		}
+	}
+
+	if len(reporte.Programas[1].Cadena) != 0 {
+		t.Error("Un programa en LOCAL no necesita pasos")
+	}
+}
+
+// TestCadenaConTraductor verifica que la cadena incluye los pasos del origen
+// y de la base de un traductor
+func TestCadenaConTraductor(t *testing.T) {
+	s := NuevoSistema()
+	s.salida = io.Discard
+	s.DefinirPrograma("test", "C")
+	s.DefinirInterprete("LOCAL", "Java")
+	s.DefinirInterprete("LOCAL", "Python")
+	s.DefinirTraductor("Python", "Java", "C")
+
+	ejecutable, pasos, err := s.EsEjecutable("test")
+	if err != nil || !ejecutable {
+		t.Fatalf("test debería ser ejecutable: %v", err)
+	}
+	if len(pasos) != 3 || pasos[2].Tipo != "traductor" {
+		t.Errorf("Se esperaban dos intérpretes seguidos del traductor, se obtuvo %v", pasos)
+	}
+}
+
+// TestReporteJSON verifica que el reporte JSON es válido
+func TestReporteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sistemaDeEjemplo().EvaluarTodos().EscribirJSON(&buf); err != nil {
+		t.Fatalf("No debería dar error: %v", err)
+	}
+
+	var leido Reporte
+	if err := json.Unmarshal(buf.Bytes(), &leido); err != nil {
+		t.Fatalf("El reporte JSON no es válido: %v", err)
+	}
+	if len(leido.Programas) != 3 || leido.NoEjecutables != 1 {
+		t.Errorf("Reporte JSON inesperado: %s", buf.String())
+	}
+}
+
+// TestReporteJUnit verifica que el reporte JUnit marca como fallo los
+// programas no ejecutables
+func TestReporteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sistemaDeEjemplo().EvaluarTodos().EscribirJUnit(&buf); err != nil {
+		t.Fatalf("No debería dar error: %v", err)
+	}
+
+	var leido junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &leido); err != nil {
+		t.Fatalf("El reporte JUnit no es válido: %v", err)
+	}
+	suite := leido.Suites[0]
+	if suite.Pruebas != 3 || suite.Fallos != 1 {
+		t.Errorf("Se esperaban 3 pruebas y 1 fallo, se obtuvo %d y %d", suite.Pruebas, suite.Fallos)
+	}
+	for _, caso := range suite.Casos {
+		if (caso.Fallo != nil) != (caso.Nombre == "holamundo") {
+			t.Errorf("Fallo inesperado en el caso '%s'", caso.Nombre)
+		}
+	}
+}
+
+// TestReporteFormatoDesconocido verifica el error ante un formato inválido
+func TestReporteFormatoDesconocido(t *testing.T) {
+	if err := sistemaDeEjemplo().EvaluarTodos().EscribirReporte(io.Discard, "yaml"); err == nil {
+		t.Error("Debería dar error con un formato desconocido")
+	}
+}
+
+// TestProcesarEntrada verifica que se procesan comandos desde un lector
+func TestProcesarEntrada(t *testing.T) {
+	s := NuevoSistema()
+	s.salida = io.Discard
+	entrada := "DEFINIR PROGRAMA p Java\nDEFINIR INTERPRETE LOCAL Java\nSALIR\nDEFINIR PROGRAMA q C\n"
+
+	if err := s.ProcesarEntrada(strings.NewReader(entrada)); err != nil {
+		t.Fatalf("No debería dar error: %v", err)
+	}
+	reporte := s.EvaluarTodos()
+	if len(reporte.Programas) != 1 || !reporte.Exitoso() {
+		t.Errorf("Se esperaba solo el programa 'p', ejecutable: %+v", reporte)
+	}
+}
@@ -1,212 +1,557 @@
-package main
+package tdiagram
 
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
-// Programa representa un programa escrito en algún lenguaje
-type Programa struct {
-	nombre   string
-	lenguaje string
+// Program representa un programa escrito en algún lenguaje
+type Program struct {
+	Name     string // nombre con el que se consulta el programa
+	Language string // lenguaje en el que está escrito
 }
 
-// Interprete representa un intérprete para un lenguaje
-type Interprete struct {
-	lenguajeBase     string // lenguaje en el que está escrito el intérprete
-	lenguajeInterpretado string // lenguaje que interpreta
+// formatoFecha es el formato de las fechas en comandos y manifiestos
+const formatoFecha = "2006-01-02"
+
+// Metadatos contiene información opcional de un intérprete o traductor. El
+// valor cero no declara nada; los métodos Con... devuelven una copia con un
+// dato más y Version, Costo, Desde, etc. lo consultan.
+type Metadatos struct {
+	version         string
+	costo           float64
+	costoDeclarado  bool // distingue un costo 0 explícito de uno ausente
+	caracteristicas []string
+	desde           time.Time // primer día de disponibilidad (cero: sin límite)
+	hasta           time.Time // día en que deja de estar disponible (cero: sin límite)
+	noConfiable     bool      // los componentes son confiables salvo que se indique
+	procedencia     string    // origen declarado del componente
+}
+
+// disponibleEn indica si el componente está disponible en la fecha dada.
+// La ventana incluye el día desde y excluye el día hasta.
+func (m Metadatos) disponibleEn(fecha time.Time) bool {
+	if !m.desde.IsZero() && fecha.Before(m.desde) {
+		return false
+	}
+	return m.hasta.IsZero() || fecha.Before(m.hasta)
+}
+
+// describirVentana devuelve la ventana de disponibilidad en texto, o una
+// cadena vacía si el componente está siempre disponible
+func (m Metadatos) describirVentana() string {
+	switch {
+	case !m.desde.IsZero() && !m.hasta.IsZero():
+		return fmt.Sprintf(" (disponible desde %s hasta %s)",
+			m.desde.Format(formatoFecha), m.hasta.Format(formatoFecha))
+	case !m.desde.IsZero():
+		return fmt.Sprintf(" (disponible desde %s)", m.desde.Format(formatoFecha))
+	case !m.hasta.IsZero():
+		return fmt.Sprintf(" (disponible hasta %s)", m.hasta.Format(formatoFecha))
+	}
+	return ""
+}
+
+// describir devuelve la ventana de disponibilidad y la confianza del
+// componente en texto, o una cadena vacía si no hay nada que destacar
+func (m Metadatos) describir() string {
+	return m.describirVentana() + m.describirConfianza()
+}
+
+// Interpreter representa un intérprete para un lenguaje
+type Interpreter struct {
+	Base      string // lenguaje en el que está escrito el intérprete
+	Language  string // lenguaje que interpreta
+	metadatos Metadatos
+}
+
+// Translator representa un traductor de un lenguaje a otro
+type Translator struct {
+	Base      string // lenguaje en el que está escrito el traductor
+	Source    string // lenguaje fuente
+	Target    string // lenguaje destino
+	metadatos Metadatos
+}
+
+// paso devuelve el paso que aplica este intérprete
+func (i Interpreter) paso() Paso {
+	return Paso{Tipo: "interprete", Base: i.Base, Destino: i.Language}
 }
 
-// Traductor representa un traductor de un lenguaje a otro
-type Traductor struct {
-	lenguajeBase   string // lenguaje en el que está escrito el traductor
-	lenguajeOrigen string // lenguaje fuente
-	lenguajeDestino string // lenguaje destino
+// paso devuelve el paso que aplica este traductor
+func (t Translator) paso() Paso {
+	return Paso{
+		Tipo:    "traductor",
+		Base:    t.Base,
+		Origen:  t.Source,
+		Destino: t.Target,
+	}
+}
+
+// Paso representa la aplicación de un intérprete o de un traductor dentro
+// de una cadena de ejecución. Para los intérpretes Origen queda vacío y
+// Destino es el lenguaje interpretado.
+type Paso struct {
+	Tipo    string `json:"tipo"`
+	Base    string `json:"base"`
+	Origen  string `json:"origen,omitempty"`
+	Destino string `json:"destino"`
+}
+
+// String describe el paso en lenguaje natural
+func (p Paso) String() string {
+	if p.Tipo == "interprete" {
+		return fmt.Sprintf("intérprete de '%s' escrito en '%s'", p.Destino, p.Base)
+	}
+	return fmt.Sprintf("traductor de '%s' hacia '%s' escrito en '%s'",
+		p.Origen, p.Destino, p.Base)
 }
 
-// Sistema mantiene el estado del simulador
-type Sistema struct {
-	programas    map[string]Programa
-	interpretes  []Interprete
-	traductores  []Traductor
+// System mantiene el estado del simulador
+type System struct {
+	programas   map[string]Program
+	interpretes []Interpreter
+	traductores []Translator
+	salida      io.Writer        // destino de los mensajes del simulador
+	eventos     []Evento         // historial de definiciones, solo se agrega al final
+	reloj       func() time.Time // momento que se registra en cada evento
 }
 
 // NuevoSistema crea un nuevo sistema vacío
-func NuevoSistema() *Sistema {
-	return &Sistema{
-		programas:   make(map[string]Programa),
-		interpretes: make([]Interprete, 0),
-		traductores: make([]Traductor, 0),
+func NuevoSistema() *System {
+	return &System{
+		programas:   make(map[string]Program),
+		interpretes: make([]Interpreter, 0),
+		traductores: make([]Translator, 0),
+		salida:      os.Stdout,
+		eventos:     make([]Evento, 0),
+		reloj:       time.Now,
+	}
+}
+
+// RedirigirSalida envía los mensajes del sistema a w (por omisión, la
+// salida estándar)
+func (s *System) RedirigirSalida(w io.Writer) {
+	s.salida = w
+}
+
+// Programas devuelve los programas definidos, ordenados por nombre
+func (s *System) Programas() []Program {
+	programas := make([]Program, 0, len(s.programas))
+	for _, programa := range s.programas {
+		programas = append(programas, programa)
 	}
+	sort.Slice(programas, func(i, j int) bool { return programas[i].Name < programas[j].Name })
+	return programas
+}
+
+// Interpretes devuelve los intérpretes en el orden en que se definieron
+func (s *System) Interpretes() []Interpreter {
+	return append(make([]Interpreter, 0, len(s.interpretes)), s.interpretes...)
+}
+
+// Traductores devuelve los traductores en el orden en que se definieron
+func (s *System) Traductores() []Translator {
+	return append(make([]Translator, 0, len(s.traductores)), s.traductores...)
 }
 
 // DefinirPrograma define un nuevo programa
-func (s *Sistema) DefinirPrograma(nombre, lenguaje string) error {
+func (s *System) DefinirPrograma(nombre, lenguaje string) error {
 	if _, existe := s.programas[nombre]; existe {
 		return fmt.Errorf("ERROR: Ya existe un programa con el nombre '%s'", nombre)
 	}
-	s.programas[nombre] = Programa{nombre: nombre, lenguaje: lenguaje}
-	fmt.Printf("Se definió el programa '%s', ejecutable en '%s'\n", nombre, lenguaje)
+	programa := Program{Name: nombre, Language: lenguaje}
+	s.registrarEvento(programa.comando(), func() {
+		s.programas[nombre] = programa
+	})
+	fmt.Fprintf(s.salida, "Se definió el programa '%s', ejecutable en '%s'\n", nombre, lenguaje)
 	return nil
 }
 
 // DefinirInterprete define un nuevo intérprete
-func (s *Sistema) DefinirInterprete(lenguajeBase, lenguajeInterpretado string) {
-	s.interpretes = append(s.interpretes, Interprete{
-		lenguajeBase:     lenguajeBase,
-		lenguajeInterpretado: lenguajeInterpretado,
+func (s *System) DefinirInterprete(lenguajeBase, lenguajeInterpretado string) {
+	s.agregarInterprete(Interpreter{
+		Base:     lenguajeBase,
+		Language: lenguajeInterpretado,
 	})
-	fmt.Printf("Se definió un intérprete para '%s', escrito en '%s'\n", 
-		lenguajeInterpretado, lenguajeBase)
+}
+
+// agregarInterprete agrega un intérprete con sus metadatos
+func (s *System) agregarInterprete(interp Interpreter) {
+	s.registrarEvento(interp.comando(), func() {
+		s.interpretes = append(s.interpretes, interp)
+	})
+	fmt.Fprintf(s.salida, "Se definió un intérprete para '%s', escrito en '%s'%s\n",
+		interp.Language, interp.Base, interp.metadatos.describir())
 }
 
 // DefinirTraductor define un nuevo traductor
-func (s *Sistema) DefinirTraductor(lenguajeBase, lenguajeOrigen, lenguajeDestino string) {
-	s.traductores = append(s.traductores, Traductor{
-		lenguajeBase:   lenguajeBase,
-		lenguajeOrigen: lenguajeOrigen,
-		lenguajeDestino: lenguajeDestino,
+func (s *System) DefinirTraductor(lenguajeBase, lenguajeOrigen, lenguajeDestino string) {
+	s.agregarTraductor(Translator{
+		Base:   lenguajeBase,
+		Source: lenguajeOrigen,
+		Target: lenguajeDestino,
 	})
-	fmt.Printf("Se definió un traductor de '%s' hacia '%s', escrito en '%s'\n",
-		lenguajeOrigen, lenguajeDestino, lenguajeBase)
 }
 
-// PuedeEjecutar verifica si un programa puede ejecutarse
-func (s *Sistema) PuedeEjecutar(nombre string) error {
-	programa, existe := s.programas[nombre]
-	if !existe {
-		return fmt.Errorf("ERROR: No existe un programa con el nombre '%s'", nombre)
-	}
-	
-	// Usamos un conjunto de lenguajes ejecutables para búsqueda BFS
-	ejecutables := make(map[string]bool)
-	ejecutables["LOCAL"] = true
-	
+// agregarTraductor agrega un traductor con sus metadatos
+func (s *System) agregarTraductor(trad Translator) {
+	s.registrarEvento(trad.comando(), func() {
+		s.traductores = append(s.traductores, trad)
+	})
+	fmt.Fprintf(s.salida, "Se definió un traductor de '%s' hacia '%s', escrito en '%s'%s\n",
+		trad.Source, trad.Target, trad.Base, trad.metadatos.describir())
+}
+
+// derivaciones calcula el conjunto de lenguajes ejecutables mediante un
+// punto fijo y registra, para cada lenguaje, el paso que lo volvió
+// ejecutable. LOCAL es ejecutable sin necesidad de ningún paso.
+// Los lenguajes que pueden ejecutarse con componentes confiables se derivan
+// primero, de modo que sus cadenas no pasen por componentes no confiables.
+func (s *System) derivaciones() map[string]*Paso {
+	derivados := s.derivacionesFiltradas(esConfiable)
+	s.extenderDerivaciones(derivados, nil)
+	return derivados
+}
+
+// derivacionesFiltradas calcula las derivaciones usando solo los intérpretes
+// y traductores que acepta admitir (todos si admitir es nil)
+func (s *System) derivacionesFiltradas(admitir func(Paso, Metadatos) bool) map[string]*Paso {
+	derivados := map[string]*Paso{"LOCAL": nil}
+	s.extenderDerivaciones(derivados, admitir)
+	return derivados
+}
+
+// extenderDerivaciones agrega a derivados los lenguajes que se vuelven
+// ejecutables con los componentes que acepta admitir
+func (s *System) extenderDerivaciones(derivados map[string]*Paso, admitir func(Paso, Metadatos) bool) {
 	// Iteramos hasta que no haya cambios (punto fijo)
 	cambio := true
 	for cambio {
 		cambio = false
-		
+
 		// Agregar lenguajes que pueden interpretarse
 		for _, interp := range s.interpretes {
-			if ejecutables[interp.lenguajeBase] && !ejecutables[interp.lenguajeInterpretado] {
-				ejecutables[interp.lenguajeInterpretado] = true
+			paso := interp.paso()
+			_, baseOk := derivados[interp.Base]
+			_, listo := derivados[interp.Language]
+			if baseOk && !listo && (admitir == nil || admitir(paso, interp.metadatos)) {
+				derivados[interp.Language] = &paso
 				cambio = true
 			}
 		}
-		
+
 		// Agregar lenguajes a los que podemos traducir
 		for _, trad := range s.traductores {
-			if ejecutables[trad.lenguajeBase] && ejecutables[trad.lenguajeOrigen] {
-				if !ejecutables[trad.lenguajeDestino] {
-					ejecutables[trad.lenguajeDestino] = true
-					cambio = true
-				}
+			paso := trad.paso()
+			_, baseOk := derivados[trad.Base]
+			_, origenOk := derivados[trad.Source]
+			_, listo := derivados[trad.Target]
+			if baseOk && origenOk && !listo && (admitir == nil || admitir(paso, trad.metadatos)) {
+				derivados[trad.Target] = &paso
+				cambio = true
 			}
 		}
 	}
-	
-	if ejecutables[programa.lenguaje] {
-		fmt.Printf("Si, es posible ejecutar el programa '%s'\n", nombre)
+}
+
+// cadena reconstruye los pasos necesarios para ejecutar el lenguaje dado,
+// ordenados de forma que cada paso solo dependa de pasos anteriores.
+// Devuelve false si el lenguaje no es ejecutable.
+func cadena(lenguaje string, derivados map[string]*Paso) ([]Paso, bool) {
+	if _, ok := derivados[lenguaje]; !ok {
+		return nil, false
+	}
+
+	pasos := make([]Paso, 0)
+	visitados := make(map[string]bool)
+	var visitar func(l string)
+	visitar = func(l string) {
+		paso := derivados[l]
+		if paso == nil || visitados[l] {
+			return
+		}
+		visitados[l] = true
+		visitar(paso.Base)
+		if paso.Tipo == "traductor" {
+			visitar(paso.Origen)
+		}
+		pasos = append(pasos, *paso)
+	}
+	visitar(lenguaje)
+
+	return pasos, true
+}
+
+// EsEjecutable indica si un programa puede ejecutarse y, en ese caso,
+// la cadena de pasos que lo permite
+func (s *System) EsEjecutable(nombre string) (bool, []Paso, error) {
+	programa, existe := s.programas[nombre]
+	if !existe {
+		return false, nil, fmt.Errorf("ERROR: No existe un programa con el nombre '%s'", nombre)
+	}
+	pasos, ok := cadena(programa.Language, s.derivaciones())
+	return ok, pasos, nil
+}
+
+// PuedeEjecutar verifica si un programa puede ejecutarse
+func (s *System) PuedeEjecutar(nombre string) error {
+	ejecutable, pasos, err := s.EsEjecutable(nombre)
+	if err != nil {
+		return err
+	}
+
+	if ejecutable {
+		noConfiables := s.pasosNoConfiables(pasos)
+		if len(noConfiables) == 0 {
+			fmt.Fprintf(s.salida, "Si, es posible ejecutar el programa '%s'\n", nombre)
+			return nil
+		}
+		fmt.Fprintf(s.salida, "Si, es posible ejecutar el programa '%s', pero solo pasando por componentes no confiables:\n", nombre)
+		for _, paso := range noConfiables {
+			fmt.Fprintf(s.salida, "  %s%s\n", paso, s.describirProcedencia(paso))
+		}
 		return nil
 	}
-	
-	fmt.Printf("No es posible ejecutar el programa '%s'\n", nombre)
+
+	fmt.Fprintf(s.salida, "No es posible ejecutar el programa '%s'\n", nombre)
 	return nil
 }
 
+// analizarOpciones interpreta las opciones que pueden seguir a la definición
+// de un intérprete o traductor:
+//
+//	DESDE <fecha>              primer día en que el componente está disponible
+//	HASTA <fecha>              día en que el componente deja de estar disponible
+//	CONFIABLE | NOCONFIABLE    nivel de confianza (por omisión, confiable)
+//	PROCEDENCIA <etiqueta>     origen del componente
+//	VERSION <version>          versión del componente
+//	COSTO <numero>             costo de instalación, usado al planificar
+//	CARACTERISTICAS <a,b,...>  características separadas por comas
+func analizarOpciones(opciones []string) (Metadatos, error) {
+	var metadatos Metadatos
+	for i := 0; i < len(opciones); i++ {
+		opcion := strings.ToUpper(opciones[i])
+		switch opcion {
+		case "DESDE", "HASTA":
+			if i+1 >= len(opciones) {
+				return metadatos, fmt.Errorf("ERROR: La opción %s requiere <fecha>", opcion)
+			}
+			fecha, err := analizarFecha(opciones[i+1])
+			if err != nil {
+				return metadatos, err
+			}
+			if opcion == "DESDE" {
+				metadatos.desde = fecha
+			} else {
+				metadatos.hasta = fecha
+			}
+			i++
+		case "CONFIABLE":
+			metadatos.noConfiable = false
+		case "NOCONFIABLE":
+			metadatos.noConfiable = true
+		case "PROCEDENCIA":
+			if i+1 >= len(opciones) {
+				return metadatos, fmt.Errorf("ERROR: La opción PROCEDENCIA requiere <etiqueta>")
+			}
+			metadatos.procedencia = opciones[i+1]
+			i++
+		case "VERSION":
+			if i+1 >= len(opciones) {
+				return metadatos, fmt.Errorf("ERROR: La opción VERSION requiere <version>")
+			}
+			metadatos.version = opciones[i+1]
+			i++
+		case "COSTO":
+			if i+1 >= len(opciones) {
+				return metadatos, fmt.Errorf("ERROR: La opción COSTO requiere <numero>")
+			}
+			costo, err := strconv.ParseFloat(opciones[i+1], 64)
+			if err != nil || costo < 0 {
+				return metadatos, fmt.Errorf("ERROR: Costo inválido '%s' (se espera un número no negativo)", opciones[i+1])
+			}
+			metadatos.costo = costo
+			metadatos.costoDeclarado = true
+			i++
+		case "CARACTERISTICAS":
+			if i+1 >= len(opciones) {
+				return metadatos, fmt.Errorf("ERROR: La opción CARACTERISTICAS requiere <a,b,...>")
+			}
+			metadatos.caracteristicas = strings.Split(opciones[i+1], ",")
+			i++
+		default:
+			return metadatos, fmt.Errorf("ERROR: Opción desconocida '%s'", opciones[i])
+		}
+	}
+
+	return metadatos, metadatos.validar()
+}
+
+// analizarFecha interpreta una fecha con el formato AAAA-MM-DD
+func analizarFecha(texto string) (time.Time, error) {
+	fecha, err := time.Parse(formatoFecha, texto)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("ERROR: Fecha inválida '%s' (se espera AAAA-MM-DD)", texto)
+	}
+	return fecha, nil
+}
+
 // ProcesarComando procesa un comando del usuario
-func (s *Sistema) ProcesarComando(comando string) bool {
+func (s *System) ProcesarComando(comando string) bool {
 	partes := strings.Fields(comando)
 	if len(partes) == 0 {
 		return true
 	}
-	
+
 	accion := strings.ToUpper(partes[0])
-	
+
 	switch accion {
 	case "SALIR":
 		return false
-		
+
 	case "DEFINIR":
 		if len(partes) < 3 {
-			fmt.Println("ERROR: Comando DEFINIR incompleto")
+			fmt.Fprintln(s.salida, "ERROR: Comando DEFINIR incompleto")
 			return true
 		}
-		
+
 		tipo := strings.ToUpper(partes[1])
 		switch tipo {
 		case "PROGRAMA":
 			if len(partes) != 4 {
-				fmt.Println("ERROR: DEFINIR PROGRAMA requiere <nombre> <lenguaje>")
+				fmt.Fprintln(s.salida, "ERROR: DEFINIR PROGRAMA requiere <nombre> <lenguaje>")
 				return true
 			}
 			if err := s.DefinirPrograma(partes[2], partes[3]); err != nil {
-				fmt.Println(err)
+				fmt.Fprintln(s.salida, err)
 			}
-			
+
 		case "INTERPRETE":
-			if len(partes) != 4 {
-				fmt.Println("ERROR: DEFINIR INTERPRETE requiere <lenguaje_base> <lenguaje>")
+			if len(partes) < 4 {
+				fmt.Fprintln(s.salida, "ERROR: DEFINIR INTERPRETE requiere <lenguaje_base> <lenguaje> [opciones]")
+				return true
+			}
+			metadatos, err := analizarOpciones(partes[4:])
+			if err != nil {
+				fmt.Fprintln(s.salida, err)
 				return true
 			}
-			s.DefinirInterprete(partes[2], partes[3])
-			
+			s.agregarInterprete(Interpreter{
+				Base:      partes[2],
+				Language:  partes[3],
+				metadatos: metadatos,
+			})
+
 		case "TRADUCTOR":
-			if len(partes) != 5 {
-				fmt.Println("ERROR: DEFINIR TRADUCTOR requiere <lenguaje_base> <lenguaje_origen> <lenguaje_destino>")
+			if len(partes) < 5 {
+				fmt.Fprintln(s.salida, "ERROR: DEFINIR TRADUCTOR requiere <lenguaje_base> <lenguaje_origen> <lenguaje_destino> [opciones]")
 				return true
 			}
-			s.DefinirTraductor(partes[2], partes[3], partes[4])
-			
+			metadatos, err := analizarOpciones(partes[5:])
+			if err != nil {
+				fmt.Fprintln(s.salida, err)
+				return true
+			}
+			s.agregarTraductor(Translator{
+				Base:      partes[2],
+				Source:    partes[3],
+				Target:    partes[4],
+				metadatos: metadatos,
+			})
+
 		default:
-			fmt.Printf("ERROR: Tipo desconocido '%s'\n", tipo)
+			fmt.Fprintf(s.salida, "ERROR: Tipo desconocido '%s'\n", tipo)
 		}
-		
+
 	case "EJECUTABLE":
+		switch {
+		case len(partes) == 2:
+			if err := s.PuedeEjecutar(partes[1]); err != nil {
+				fmt.Fprintln(s.salida, err)
+			}
+		case len(partes) == 3 && strings.ToUpper(partes[2]) == "CONFIABLE":
+			if err := s.PuedeEjecutarConfiable(partes[1]); err != nil {
+				fmt.Fprintln(s.salida, err)
+			}
+		case len(partes) == 4 && strings.ToUpper(partes[2]) == "EN":
+			fecha, err := analizarFecha(partes[3])
+			if err == nil {
+				err = s.PuedeEjecutarEn(partes[1], fecha)
+			}
+			if err != nil {
+				fmt.Fprintln(s.salida, err)
+			}
+		default:
+			fmt.Fprintln(s.salida, "ERROR: EJECUTABLE requiere <nombre> [EN <fecha> | CONFIABLE]")
+		}
+
+	case "CRONOLOGIA":
+		if len(partes) != 1 {
+			fmt.Fprintln(s.salida, "ERROR: CRONOLOGIA no recibe argumentos")
+			return true
+		}
+		s.mostrarCronologia()
+
+	case "REPORTE":
+		if len(partes) != 2 {
+			fmt.Fprintln(s.salida, "ERROR: REPORTE requiere <JSON|JUNIT>")
+			return true
+		}
+		if err := s.EvaluarTodos().EscribirReporte(s.salida, partes[1]); err != nil {
+			fmt.Fprintln(s.salida, err)
+		}
+
+	case "IMPORTAR":
 		if len(partes) != 2 {
-			fmt.Println("ERROR: EJECUTABLE requiere <nombre>")
+			fmt.Fprintln(s.salida, "ERROR: IMPORTAR requiere <archivo>")
 			return true
 		}
-		if err := s.PuedeEjecutar(partes[1]); err != nil {
-			fmt.Println(err)
+		if err := s.ImportarArchivo(partes[1]); err != nil {
+			fmt.Fprintln(s.salida, err)
+		}
+
+	case "DEPENDE":
+		if err := s.procesarDepende(partes[1:]); err != nil {
+			fmt.Fprintln(s.salida, err)
+		}
+
+	case "PLANIFICAR":
+		if err := s.procesarPlanificar(partes[1:]); err != nil {
+			fmt.Fprintln(s.salida, err)
+		}
+
+	case "HISTORIAL":
+		if err := s.procesarHistorial(partes[1:]); err != nil {
+			fmt.Fprintln(s.salida, err)
 		}
-		
+
 	default:
-		fmt.Printf("ERROR: Comando desconocido '%s'\n", accion)
+		fmt.Fprintf(s.salida, "ERROR: Comando desconocido '%s'\n", accion)
 	}
-	
+
 	return true
 }
 
-func main() {
-	sistema := NuevoSistema()
-	scanner := bufio.NewScanner(os.Stdin)
-	
-	fmt.Println("Simulador de Diagramas T")
-	fmt.Println("Comandos disponibles:")
-	fmt.Println("  DEFINIR PROGRAMA <nombre> <lenguaje>")
-	fmt.Println("  DEFINIR INTERPRETE <lenguaje_base> <lenguaje>")
-	fmt.Println("  DEFINIR TRADUCTOR <lenguaje_base> <lenguaje_origen> <lenguaje_destino>")
-	fmt.Println("  EJECUTABLE <nombre>")
-	fmt.Println("  SALIR")
-	fmt.Println()
-	
-	for {
-		fmt.Print("$> ")
-		if !scanner.Scan() {
-			break
-		}
-		
-		comando := scanner.Text()
-		if !sistema.ProcesarComando(comando) {
+// procesarLineas aplica procesar a cada línea del lector hasta que se
+// agote la entrada o procesar devuelva false
+func procesarLineas(r io.Reader, procesar func(string) bool) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if !procesar(scanner.Text()) {
 			break
 		}
 	}
-	
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error leyendo entrada: %v\n", err)
-	}
+	return scanner.Err()
+}
+
+// ProcesarEntrada procesa todos los comandos de un lector, uno por línea
+func (s *System) ProcesarEntrada(r io.Reader) error {
+	return procesarLineas(r, s.ProcesarComando)
 }
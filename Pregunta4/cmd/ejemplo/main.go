@@ -2,11 +2,12 @@ package main
 
 import (
 	"fmt"
+	"math"
 	"quaternion"
 )
 
 func main() {
-	fmt.Println("=== Ejemplos de Operaciones con Cuaterniones ===\n")
+	fmt.Print("=== Ejemplos de Operaciones con Cuaterniones ===\n\n")
 	
 	// Crear cuaterniones
 	q1 := quaternion.New(1, 2, 3, 4)
@@ -39,7 +40,7 @@ func main() {
 	fmt.Printf("   %.4f\n\n", medida)
 	
 	// Expresiones compuestas
-	fmt.Println("=== Expresiones Compuestas ===\n")
+	fmt.Print("=== Expresiones Compuestas ===\n\n")
 	
 	// (q1 + q2) * q3
 	fmt.Println("5. (q1 + q2) * q3:")
@@ -57,7 +58,7 @@ func main() {
 	fmt.Printf("   %.4f\n\n", expr3)
 	
 	// Operaciones con números reales
-	fmt.Println("=== Operaciones con Números Reales ===\n")
+	fmt.Print("=== Operaciones con Números Reales ===\n\n")
 	
 	// q1 + 3
 	fmt.Println("8. q1 + 3:")
@@ -82,7 +83,7 @@ func main() {
 	fmt.Printf("    (donde &q3 = %.4f)\n\n", abs_q3)
 	
 	// Verificación de propiedades
-	fmt.Println("=== Verificación de Propiedades ===\n")
+	fmt.Print("=== Verificación de Propiedades ===\n\n")
 	
 	// Verificar i² = j² = k² = ijk = -1
 	i := quaternion.New(0, 1, 0, 0)
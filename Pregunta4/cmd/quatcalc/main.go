@@ -0,0 +1,60 @@
+// Comando quatcalc: calculadora interactiva de cuaterniones con la notación
+// del curso (~ conjugada, & medida). Cada línea es una expresión o una
+// asignación; el último resultado queda en la variable ans.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"quaternion"
+)
+
+// formatear muestra los reales como número y el resto como cuaternión
+func formatear(q quaternion.Quaternion) string {
+	if q.IsReal() {
+		return strconv.FormatFloat(q.A, 'g', -1, 64)
+	}
+	return q.String()
+}
+
+func main() {
+	calculadora := quaternion.NewCalculator()
+	scanner := bufio.NewScanner(os.Stdin)
+
+	fmt.Println("Calculadora de Cuaterniones")
+	fmt.Println("Operadores: + - * / (división por la derecha), ~q (conjugada), &q (medida)")
+	fmt.Println("Literales:  2.5, 1+2i+3j+4k, i, j, k")
+	fmt.Println("Variables:  q1 = 1+2i+3j+4k   (ans guarda el último resultado)")
+	fmt.Println("SALIR para terminar")
+	fmt.Println()
+
+	for {
+		fmt.Print("q> ")
+		if !scanner.Scan() {
+			break
+		}
+
+		linea := strings.TrimSpace(scanner.Text())
+		if linea == "" {
+			continue
+		}
+		if strings.EqualFold(linea, "SALIR") {
+			break
+		}
+
+		resultado, err := calculadora.Execute(linea)
+		if err != nil {
+			fmt.Printf("ERROR: %v\n", err)
+			continue
+		}
+		fmt.Println(formatear(resultado))
+	}
+
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error leyendo entrada: %v\n", err)
+	}
+}
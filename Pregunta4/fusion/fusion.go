@@ -0,0 +1,123 @@
+// Package fusion estima la orientación de un cuerpo a partir de una IMU:
+// giróscopo, acelerómetro y, opcionalmente, magnetómetro. Los filtros
+// integran la velocidad angular del giróscopo y corrigen la deriva con las
+// direcciones de referencia que miden los otros sensores.
+//
+// Convenciones: la orientación es el cuaternión unitario que lleva vectores
+// del sistema del cuerpo al del mundo (q.RotateVector(v) expresa en el mundo
+// un vector medido en el cuerpo), como en quaternion.Derivative. El mundo
+// tiene x hacia el norte magnético, y hacia el oeste y z hacia arriba. En
+// reposo el acelerómetro mide la reacción a la gravedad, que apunta hacia
+// arriba; el campo magnético apunta al norte y, en el hemisferio norte,
+// hacia abajo.
+package fusion
+
+import (
+	"errors"
+	"math"
+
+	"quaternion"
+)
+
+// up es la dirección vertical del mundo
+var up = [3]float64{0, 0, 1}
+
+// ErrNoGravity indica que la muestra no tiene medición del acelerómetro
+var ErrNoGravity = errors.New("fusion: la muestra no mide la gravedad")
+
+// Sample es una muestra de la IMU expresada en el sistema del cuerpo. Del
+// acelerómetro y del magnetómetro solo importa la dirección, así que las
+// unidades son libres; el giróscopo va en rad/s. Un vector nulo indica que
+// el sensor no midió: sin acelerómetro (por ejemplo en caída libre) el filtro
+// solo integra el giróscopo y sin magnetómetro el rumbo queda sin corregir.
+type Sample struct {
+	Gyro  [3]float64
+	Accel [3]float64
+	Mag   [3]float64
+}
+
+// Filter es un estimador de orientación que se actualiza con cada muestra
+type Filter interface {
+	// Update incorpora una muestra que cubre los dt segundos desde la
+	// anterior y devuelve la orientación estimada
+	Update(s Sample, dt float64) quaternion.Quaternion
+	// Orientation devuelve la orientación estimada actual
+	Orientation() quaternion.Quaternion
+	// SetOrientation reinicia la estimación en q, que se normaliza
+	SetOrientation(q quaternion.Quaternion)
+}
+
+// unitOrIdentity normaliza q, o devuelve la identidad si es cero
+func unitOrIdentity(q quaternion.Quaternion) quaternion.Quaternion {
+	n, err := q.Normalize()
+	if err != nil {
+		return quaternion.Identity()
+	}
+	return n
+}
+
+// dot calcula el producto escalar de dos vectores
+func dot(u, v [3]float64) float64 {
+	return u[0]*v[0] + u[1]*v[1] + u[2]*v[2]
+}
+
+// cross calcula el producto vectorial u × v
+func cross(u, v [3]float64) [3]float64 {
+	return [3]float64{
+		u[1]*v[2] - u[2]*v[1],
+		u[2]*v[0] - u[0]*v[2],
+		u[0]*v[1] - u[1]*v[0],
+	}
+}
+
+// normalize devuelve v con norma 1, o false si v es nulo
+func normalize(v [3]float64) ([3]float64, bool) {
+	n := math.Sqrt(dot(v, v))
+	if n == 0 {
+		return v, false
+	}
+	return [3]float64{v[0] / n, v[1] / n, v[2] / n}, true
+}
+
+// toBody expresa en el sistema del cuerpo un vector v del mundo
+func toBody(q quaternion.Quaternion, v [3]float64) [3]float64 {
+	return q.Conjugate().RotateVector(v)
+}
+
+// earthField calcula la referencia del campo magnético a partir de la
+// medición m y la orientación estimada: lleva m al mundo y concentra su
+// componente horizontal en el eje x, de modo que la referencia conserva la
+// inclinación medida y solo aporta información de rumbo
+func earthField(q quaternion.Quaternion, m [3]float64) [3]float64 {
+	h := q.RotateVector(m)
+	return [3]float64{math.Hypot(h[0], h[1]), 0, h[2]}
+}
+
+// InitialOrientation calcula la orientación que indica una sola muestra en
+// reposo, para iniciar un filtro sin esperar a que converja: la vertical
+// sale del acelerómetro y el norte de la componente horizontal del campo
+// magnético. Si no hay magnetómetro, o si el campo es vertical, devuelve la
+// inclinación con el menor giro posible y el rumbo queda arbitrario.
+// Devuelve ErrNoGravity si el acelerómetro es nulo.
+func InitialOrientation(s Sample) (quaternion.Quaternion, error) {
+	z, ok := normalize(s.Accel)
+	if !ok {
+		return quaternion.Quaternion{}, ErrNoGravity
+	}
+
+	// Los ejes del mundo expresados en el cuerpo son las filas de la matriz
+	// que lleva del cuerpo al mundo
+	m := s.Mag
+	h := dot(m, z)
+	if x, ok := normalize([3]float64{m[0] - h*z[0], m[1] - h*z[1], m[2] - h*z[2]}); ok {
+		return quaternion.FromRotationMatrix([3][3]float64{x, cross(z, x), z})
+	}
+
+	axis := cross(z, up)
+	angle := math.Atan2(math.Sqrt(dot(axis, axis)), dot(z, up))
+	if axis == ([3]float64{}) && dot(z, up) < 0 {
+		// Cuerpo boca abajo: cualquier eje horizontal da el giro de 180°
+		axis = [3]float64{1, 0, 0}
+	}
+	return quaternion.FromAxisAngle(axis, angle), nil
+}
@@ -0,0 +1,63 @@
+package fusion
+
+import (
+	"math"
+
+	"quaternion"
+)
+
+// Complementary es un filtro complementario: integra el giróscopo, que es
+// preciso a corto plazo, y en cada paso acerca la orientación una fracción
+// dt / (TimeConstant + dt) hacia la que indican el acelerómetro y el
+// magnetómetro, que son ruidosos pero no derivan. Equivale a filtrar el
+// giróscopo con un pasa altos y las referencias con un pasa bajos de la
+// misma constante de tiempo.
+type Complementary struct {
+	// TimeConstant es la constante de tiempo en segundos: con valores altos
+	// se confía más en el giróscopo y con valores bajos en las referencias
+	TimeConstant float64
+
+	q quaternion.Quaternion
+}
+
+// NewComplementary crea un filtro complementario que parte de la identidad
+func NewComplementary(timeConstant float64) *Complementary {
+	return &Complementary{TimeConstant: timeConstant, q: quaternion.Identity()}
+}
+
+// Orientation devuelve la orientación estimada
+func (f *Complementary) Orientation() quaternion.Quaternion {
+	return f.q
+}
+
+// SetOrientation reinicia la estimación en q
+func (f *Complementary) SetOrientation(q quaternion.Quaternion) {
+	f.q = unitOrIdentity(q)
+}
+
+// Update incorpora una muestra. La corrección de inclinación gira la
+// vertical medida hacia la del mundo y la de rumbo gira alrededor de la
+// vertical hasta alinear el campo magnético horizontal con el norte, así que
+// el magnetómetro nunca altera la inclinación.
+func (f *Complementary) Update(s Sample, dt float64) quaternion.Quaternion {
+	q := quaternion.IntegrateExp(f.q, s.Gyro, dt)
+	alpha := dt / (f.TimeConstant + dt)
+
+	if a, ok := normalize(s.Accel); ok {
+		g := q.RotateVector(a)
+		axis := cross(g, up)
+		angle := math.Atan2(math.Sqrt(dot(axis, axis)), dot(g, up))
+		q = quaternion.FromAxisAngle(axis, alpha*angle).Multiply(q)
+
+		if m, ok := normalize(s.Mag); ok {
+			h := q.RotateVector(m)
+			if h[0] != 0 || h[1] != 0 {
+				heading := math.Atan2(h[1], h[0])
+				q = quaternion.FromAxisAngle(up, -alpha*heading).Multiply(q)
+			}
+		}
+	}
+
+	f.q = unitOrIdentity(q)
+	return f.q
+}
@@ -0,0 +1,59 @@
+package fusion
+
+import "quaternion"
+
+// Madgwick es el filtro de Madgwick (2010): a la derivada que da el
+// giróscopo le resta un paso de descenso por gradiente que minimiza la
+// diferencia entre las direcciones de referencia, llevadas al cuerpo con la
+// orientación estimada, y las medidas.
+type Madgwick struct {
+	// Beta es la velocidad de la corrección en rad/s. Madgwick propone
+	// √(3/4) veces el error esperado del giróscopo; 0.1 es un valor habitual.
+	Beta float64
+
+	q quaternion.Quaternion
+}
+
+// NewMadgwick crea un filtro de Madgwick que parte de la identidad
+func NewMadgwick(beta float64) *Madgwick {
+	return &Madgwick{Beta: beta, q: quaternion.Identity()}
+}
+
+// Orientation devuelve la orientación estimada
+func (f *Madgwick) Orientation() quaternion.Quaternion {
+	return f.q
+}
+
+// SetOrientation reinicia la estimación en q
+func (f *Madgwick) SetOrientation(q quaternion.Quaternion) {
+	f.q = unitOrIdentity(q)
+}
+
+// Update incorpora una muestra
+func (f *Madgwick) Update(s Sample, dt float64) quaternion.Quaternion {
+	q := f.q
+	rate := quaternion.Derivative(q, s.Gyro)
+
+	if a, ok := normalize(s.Accel); ok {
+		grad := gradient(q, up, a)
+		if m, ok := normalize(s.Mag); ok {
+			grad = grad.Add(gradient(q, earthField(q, m), m))
+		}
+		if step, err := grad.Normalize(); err == nil {
+			rate = rate.Subtract(step.MultiplyReal(f.Beta))
+		}
+	}
+
+	f.q = unitOrIdentity(q.Add(rate.MultiplyReal(dt)))
+	return f.q
+}
+
+// gradient calcula el gradiente respecto de q de ½|q*·d·q − s|², el error
+// entre la referencia d del mundo llevada al cuerpo y la medición s. Con d
+// y e = q*·d·q − s puros el gradiente se reduce a −2·d·q·e, en lugar del
+// producto por el jacobiano que se desarrolla en el artículo original.
+func gradient(q quaternion.Quaternion, d, s [3]float64) quaternion.Quaternion {
+	v := toBody(q, d)
+	e := quaternion.New(0, v[0]-s[0], v[1]-s[1], v[2]-s[2])
+	return quaternion.New(0, d[0], d[1], d[2]).Multiply(q).Multiply(e).MultiplyReal(-2)
+}
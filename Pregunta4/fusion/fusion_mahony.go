@@ -0,0 +1,66 @@
+package fusion
+
+import "quaternion"
+
+// Mahony es el filtro complementario no lineal de Mahony (2008): el error
+// entre cada dirección medida y la estimada es el producto vectorial de
+// ambas, y se realimenta a la velocidad angular con una parte proporcional
+// y otra integral. La parte integral estima el sesgo del giróscopo; como
+// acumula el error, si el filtro parte lejos de la orientación real junta un
+// sesgo falso que tarda en descargar, así que conviene iniciarlo con
+// SetOrientation e InitialOrientation.
+type Mahony struct {
+	// Kp es la ganancia proporcional en rad/s y Ki la integral en rad/s²;
+	// con Ki = 0 no se estima el sesgo
+	Kp, Ki float64
+
+	q        quaternion.Quaternion
+	integral [3]float64
+}
+
+// NewMahony crea un filtro de Mahony que parte de la identidad
+func NewMahony(kp, ki float64) *Mahony {
+	return &Mahony{Kp: kp, Ki: ki, q: quaternion.Identity()}
+}
+
+// Orientation devuelve la orientación estimada
+func (f *Mahony) Orientation() quaternion.Quaternion {
+	return f.q
+}
+
+// SetOrientation reinicia la estimación en q y descarta el sesgo estimado
+func (f *Mahony) SetOrientation(q quaternion.Quaternion) {
+	f.q = unitOrIdentity(q)
+	f.integral = [3]float64{}
+}
+
+// Bias devuelve el sesgo del giróscopo estimado por la parte integral, en
+// rad/s: el valor que hay que restar a las mediciones
+func (f *Mahony) Bias() [3]float64 {
+	return [3]float64{-f.integral[0], -f.integral[1], -f.integral[2]}
+}
+
+// Update incorpora una muestra
+func (f *Mahony) Update(s Sample, dt float64) quaternion.Quaternion {
+	q := f.q
+	omega := s.Gyro
+
+	if a, ok := normalize(s.Accel); ok {
+		e := cross(a, toBody(q, up))
+		if m, ok := normalize(s.Mag); ok {
+			me := cross(m, toBody(q, earthField(q, m)))
+			e = [3]float64{e[0] + me[0], e[1] + me[1], e[2] + me[2]}
+		}
+		for i := range omega {
+			f.integral[i] += f.Ki * e[i] * dt
+			omega[i] += f.Kp*e[i] + f.integral[i]
+		}
+	} else {
+		for i := range omega {
+			omega[i] += f.integral[i]
+		}
+	}
+
+	f.q = unitOrIdentity(quaternion.IntegrateExp(q, omega, dt))
+	return f.q
+}
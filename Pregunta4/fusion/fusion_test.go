@@ -0,0 +1,262 @@
+package fusion
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"quaternion"
+)
+
+// Trayectoria sintética: el cuerpo gira con una velocidad que varía en el
+// tiempo, la verdad se integra con el mapa exponencial y las mediciones se
+// generan a partir de ella con ruido gaussiano reproducible.
+
+const (
+	sampleRate = 100.0
+	gravity    = 9.81
+)
+
+// magneticField es el campo del mundo: hacia el norte y hacia abajo
+var magneticField = [3]float64{0.22, 0, -0.42}
+
+// trueRate es la velocidad angular de la trayectoria en el cuerpo
+func trueRate(t float64) [3]float64 {
+	return [3]float64{0.6 * math.Sin(0.7*t), 0.4 * math.Cos(0.5*t), 0.3 + 0.5*math.Sin(0.3*t)}
+}
+
+// noiseLevels son las desviaciones típicas del ruido y el sesgo del giróscopo
+type noiseLevels struct {
+	gyro, accel, mag float64
+	bias             [3]float64
+}
+
+var realistic = noiseLevels{gyro: 0.01, accel: 0.15, mag: 0.01}
+
+// stream genera seconds segundos de muestras y la orientación verdadera
+// después de cada una. Sin magnetómetro el campo Mag queda nulo.
+func stream(start quaternion.Quaternion, seconds float64, noise noiseLevels, withMag bool) ([]Sample, []quaternion.Quaternion) {
+	r := rand.New(rand.NewSource(3))
+	jitter := func(v [3]float64, sigma float64) [3]float64 {
+		return [3]float64{v[0] + sigma*r.NormFloat64(), v[1] + sigma*r.NormFloat64(), v[2] + sigma*r.NormFloat64()}
+	}
+
+	dt := 1 / sampleRate
+	n := int(seconds * sampleRate)
+	samples := make([]Sample, n)
+	truth := make([]quaternion.Quaternion, n)
+	q := start
+	for i := range samples {
+		omega := trueRate((float64(i) + 0.5) * dt)
+		q = quaternion.IntegrateExp(q, omega, dt)
+		truth[i] = q
+
+		gyro := jitter(omega, noise.gyro)
+		for k := range gyro {
+			gyro[k] += noise.bias[k]
+		}
+		g := toBody(q, up)
+		samples[i] = Sample{
+			Gyro:  gyro,
+			Accel: jitter([3]float64{gravity * g[0], gravity * g[1], gravity * g[2]}, noise.accel),
+		}
+		if withMag {
+			samples[i].Mag = jitter(toBody(q, magneticField), noise.mag)
+		}
+	}
+	return samples, truth
+}
+
+// angleError es el ángulo de la rotación que lleva de una orientación a otra
+func angleError(q1, q2 quaternion.Quaternion) float64 {
+	return 2 * math.Acos(math.Min(1, math.Abs(q1.Dot(q2))))
+}
+
+// tiltError es el ángulo entre las verticales de dos orientaciones, que no
+// depende del rumbo
+func tiltError(q1, q2 quaternion.Quaternion) float64 {
+	u, v := toBody(q1, up), toBody(q2, up)
+	return math.Atan2(math.Sqrt(dot(cross(u, v), cross(u, v))), dot(u, v))
+}
+
+// run pasa las muestras por el filtro y devuelve el error máximo de la
+// segunda mitad, cuando ya debe haber convergido
+func run(f Filter, samples []Sample, truth []quaternion.Quaternion, metric func(q1, q2 quaternion.Quaternion) float64) float64 {
+	worst := 0.0
+	for i, s := range samples {
+		q := f.Update(s, 1/sampleRate)
+		if i >= len(samples)/2 {
+			worst = math.Max(worst, metric(q, truth[i]))
+		}
+	}
+	return worst
+}
+
+// filters devuelve los tres filtros con ganancias razonables para el ruido
+// de prueba
+func filters() map[string]Filter {
+	return map[string]Filter{
+		"Complementary": NewComplementary(1),
+		"Madgwick":      NewMadgwick(0.1),
+		"Mahony":        NewMahony(1, 0.05),
+	}
+}
+
+var trajectoryStart = quaternion.FromEuler([3]float64{0.5, -0.4, 2.0}, quaternion.EulerZYX)
+
+const degree = math.Pi / 180
+
+// start inicia el filtro con la primera muestra y lo devuelve
+func start(f Filter, s Sample) Filter {
+	q, err := InitialOrientation(s)
+	if err == nil {
+		f.SetOrientation(q)
+	}
+	return f
+}
+
+// TestFiltersWithMagnetometer prueba que los filtros siguen la orientación
+// completa con las tres mediciones
+func TestFiltersWithMagnetometer(t *testing.T) {
+	samples, truth := stream(trajectoryStart, 40, realistic, true)
+	for name, f := range filters() {
+		if e := run(start(f, samples[0]), samples, truth, angleError); e > 3*degree {
+			t.Errorf("%s: expected an error below 3°, got %.2f°", name, e/degree)
+		}
+	}
+}
+
+// TestFiltersTiltWithoutMagnetometer prueba que sin magnetómetro se estima
+// la inclinación, aunque el rumbo quede libre
+func TestFiltersTiltWithoutMagnetometer(t *testing.T) {
+	samples, truth := stream(trajectoryStart, 40, realistic, false)
+	for name, f := range filters() {
+		if e := run(start(f, samples[0]), samples, truth, tiltError); e > 3*degree {
+			t.Errorf("%s: expected a tilt error below 3°, got %.2f°", name, e/degree)
+		}
+	}
+}
+
+// TestFiltersConvergeFromIdentity prueba que los filtros alcanzan la
+// orientación real partiendo de la identidad. Mahony va sin parte integral,
+// que acumularía un sesgo falso durante la convergencia.
+func TestFiltersConvergeFromIdentity(t *testing.T) {
+	samples, truth := stream(trajectoryStart, 40, realistic, true)
+	fs := filters()
+	fs["Mahony"] = NewMahony(2, 0)
+	for name, f := range fs {
+		if e := run(f, samples, truth, angleError); e > 3*degree {
+			t.Errorf("%s: expected an error below 3°, got %.2f°", name, e/degree)
+		}
+	}
+}
+
+// TestInitialOrientation prueba la orientación calculada con una muestra
+func TestInitialOrientation(t *testing.T) {
+	g := toBody(trajectoryStart, up)
+	s := Sample{Accel: [3]float64{gravity * g[0], gravity * g[1], gravity * g[2]}, Mag: toBody(trajectoryStart, magneticField)}
+	if q, err := InitialOrientation(s); err != nil || angleError(q, trajectoryStart) > 1e-12 {
+		t.Errorf("Expected %s, got %s (%v)", trajectoryStart, q, err)
+	}
+
+	s.Mag = [3]float64{}
+	if q, err := InitialOrientation(s); err != nil || tiltError(q, trajectoryStart) > 1e-12 {
+		t.Errorf("Expected the tilt of %s, got %s (%v)", trajectoryStart, q, err)
+	}
+
+	// Boca abajo y sin magnetómetro hace falta un giro de 180°
+	q, err := InitialOrientation(Sample{Accel: [3]float64{0, 0, -gravity}})
+	if v := q.RotateVector([3]float64{0, 0, -1}); err != nil || math.Abs(v[2]-1) > 1e-12 {
+		t.Errorf("Expected the body -z axis to point up, got %v (%v)", v, err)
+	}
+
+	if _, err := InitialOrientation(Sample{Mag: [3]float64{1, 0, 0}}); !errors.Is(err, ErrNoGravity) {
+		t.Errorf("Expected ErrNoGravity, got %v", err)
+	}
+}
+
+// TestMahonyEstimatesGyroBias prueba que la parte integral de Mahony
+// recupera el sesgo del giróscopo y que así reduce el error
+func TestMahonyEstimatesGyroBias(t *testing.T) {
+	noise := realistic
+	noise.bias = [3]float64{0.02, -0.03, 0.015}
+	samples, truth := stream(trajectoryStart, 120, noise, true)
+
+	integral := NewMahony(1, 0.05)
+	integral.SetOrientation(trajectoryStart)
+	withIntegral := run(integral, samples, truth, angleError)
+	proportional := NewMahony(1, 0)
+	proportional.SetOrientation(trajectoryStart)
+	withoutIntegral := run(proportional, samples, truth, angleError)
+
+	bias := integral.Bias()
+	for k := range bias {
+		if math.Abs(bias[k]-noise.bias[k]) > 0.005 {
+			t.Errorf("Expected bias %v, got %v", noise.bias, bias)
+			break
+		}
+	}
+	if withIntegral >= withoutIntegral {
+		t.Errorf("Expected the integral term to reduce the error, got %.2f° and %.2f°", withIntegral/degree, withoutIntegral/degree)
+	}
+}
+
+// TestFiltersWithoutAccelerometerIntegrateGyro prueba que sin acelerómetro
+// los filtros solo integran el giróscopo
+func TestFiltersWithoutAccelerometerIntegrateGyro(t *testing.T) {
+	s := Sample{Gyro: [3]float64{0.3, -0.2, 0.5}, Mag: [3]float64{1, 0, 0}}
+	expected := quaternion.IntegrateExp(trajectoryStart, s.Gyro, 0.01)
+	for name, f := range filters() {
+		f.SetOrientation(trajectoryStart)
+		if q := f.Update(s, 0.01); angleError(q, expected) > 1e-6 {
+			t.Errorf("%s: expected %s, got %s", name, expected, q)
+		}
+	}
+}
+
+// TestFiltersStayUnit prueba que la estimación se mantiene unitaria
+func TestFiltersStayUnit(t *testing.T) {
+	samples, _ := stream(trajectoryStart, 10, realistic, true)
+	for name, f := range filters() {
+		for _, s := range samples {
+			f.Update(s, 1/sampleRate)
+		}
+		if n := f.Orientation().Abs(); math.Abs(n-1) > 1e-12 {
+			t.Errorf("%s: expected a unit quaternion, got |q| = %.17f", name, n)
+		}
+	}
+}
+
+// TestGradientMatchesFiniteDifference prueba la forma cerrada del gradiente
+// de Madgwick contra diferencias finitas de la función objetivo
+func TestGradientMatchesFiniteDifference(t *testing.T) {
+	q := quaternion.New(0.8, -0.3, 0.4, 0.2)
+	d := [3]float64{0.3, -0.1, 0.9}
+	s := [3]float64{0.1, 0.5, 0.7}
+	objective := func(q quaternion.Quaternion) float64 {
+		v := q.Conjugate().Multiply(quaternion.New(0, d[0], d[1], d[2])).Multiply(q)
+		e := [3]float64{v.B - s[0], v.C - s[1], v.D - s[2]}
+		return dot(e, e) / 2
+	}
+
+	// gradient usa RotateVector, que normaliza; en un q unitario coincide
+	// con q*·d·q
+	q, _ = q.Normalize()
+	const h = 1e-6
+	numeric := [4]float64{}
+	for k := range numeric {
+		var delta [4]float64
+		delta[k] = h
+		plus := q.Add(quaternion.New(delta[0], delta[1], delta[2], delta[3]))
+		minus := q.Subtract(quaternion.New(delta[0], delta[1], delta[2], delta[3]))
+		numeric[k] = (objective(plus) - objective(minus)) / (2 * h)
+	}
+	g := gradient(q, d, s)
+	for k, got := range [4]float64{g.A, g.B, g.C, g.D} {
+		if math.Abs(got-numeric[k]) > 1e-6 {
+			t.Errorf("Expected %v, got %s", numeric, g)
+			break
+		}
+	}
+}
@@ -0,0 +1,3 @@
+module quaternion
+
+go 1.21
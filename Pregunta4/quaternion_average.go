@@ -0,0 +1,288 @@
+package quaternion
+
+import (
+	"errors"
+	"math"
+)
+
+// Promedios y estadísticas de orientaciones. Promediar los coeficientes no
+// sirve: q y -q son la misma rotación, y la media de ambos es cero. Las
+// funciones de este archivo tratan a q y -q como iguales y aceptan pesos
+// opcionales: con weights nil todas las orientaciones pesan lo mismo. Los
+// cuaterniones se normalizan antes de usarlos y los resultados tienen
+// parte real no negativa, como FromRotationMatrix.
+
+const (
+	// karcherTolerance es el tamaño del paso, en radianes, por debajo del
+	// cual KarcherMean considera que la media ya no cambia
+	karcherTolerance = 1e-13
+	// karcherMaxIterations limita las iteraciones de KarcherMean; partiendo
+	// del promedio de Markley basta con unas pocas
+	karcherMaxIterations = 100
+	// jacobiSweeps limita los barridos del método de Jacobi; una matriz de
+	// 4×4 converge en menos de diez
+	jacobiSweeps = 50
+)
+
+var (
+	// ErrNoOrientations indica que no hay orientaciones que promediar
+	ErrNoOrientations = errors.New("quaternion: no hay orientaciones que promediar")
+	// ErrInvalidWeights indica que hay un peso negativo, que todos son cero
+	// o que no hay uno por orientación
+	ErrInvalidWeights = errors.New("quaternion: los pesos no son válidos")
+	// ErrNoConvergence indica que la media de Karcher no convergió, lo que
+	// pasa cuando las orientaciones están repartidas por toda la esfera y la
+	// media no es única
+	ErrNoConvergence = errors.New("quaternion: la media de Karcher no convergió")
+)
+
+// AngularDistance calcula el ángulo, en [0, π], de la rotación que lleva de
+// q1 a q2. Trata a q y -q como iguales y es preciso también para ángulos
+// muy chicos, donde la fórmula con el arcocoseno pierde la mitad de los
+// dígitos.
+func AngularDistance(q1, q2 Quaternion) float64 {
+	p1, err1 := q1.Normalize()
+	p2, err2 := q2.Normalize()
+	if err1 != nil || err2 != nil {
+		return math.NaN()
+	}
+	// El ángulo entre los cuaterniones es la mitad del de la rotación, y la
+	// cuerda y la suma dan la tangente de su mitad
+	p2 = shortestPath(p1, p2)
+	return 4 * math.Atan2(p1.Subtract(p2).Abs(), p1.Add(p2).Abs())
+}
+
+// RotationVector devuelve el vector de rotación (eje por ángulo, con el
+// ángulo en [0, π]) de la rotación que lleva de q1 a q2, expresado en el
+// sistema de q1. Es la diferencia entre orientaciones en el espacio
+// tangente, la que usan KarcherMean y Statistics.
+func RotationVector(q1, q2 Quaternion) [3]float64 {
+	delta := q1.Conjugate().Multiply(q2)
+	if delta.A < 0 {
+		delta = delta.Negate()
+	}
+	if n, err := delta.Normalize(); err == nil {
+		delta = n
+	}
+	log := delta.Log()
+	return [3]float64{2 * log.B, 2 * log.C, 2 * log.D}
+}
+
+// normalizedWeights normaliza las orientaciones y los pesos para que sumen 1
+func normalizedWeights(qs []Quaternion, weights []float64) ([]Quaternion, []float64, error) {
+	if len(qs) == 0 {
+		return nil, nil, ErrNoOrientations
+	}
+	if weights != nil && len(weights) != len(qs) {
+		return nil, nil, ErrInvalidWeights
+	}
+
+	units := make([]Quaternion, len(qs))
+	ws := make([]float64, len(qs))
+	total := 0.0
+	for i, q := range qs {
+		u, err := q.Normalize()
+		if err != nil {
+			return nil, nil, err
+		}
+		units[i] = u
+		ws[i] = 1
+		if weights != nil {
+			ws[i] = weights[i]
+		}
+		if ws[i] < 0 || math.IsNaN(ws[i]) || math.IsInf(ws[i], 0) {
+			return nil, nil, ErrInvalidWeights
+		}
+		total += ws[i]
+	}
+	if total == 0 {
+		return nil, nil, ErrInvalidWeights
+	}
+	for i := range ws {
+		ws[i] /= total
+	}
+	return units, ws, nil
+}
+
+// nonNegative devuelve q o -q, el que tenga parte real no negativa
+func nonNegative(q Quaternion) Quaternion {
+	if q.A < 0 {
+		return q.Negate()
+	}
+	return q
+}
+
+// Average calcula el promedio de Markley (2007): el cuaternión unitario que
+// maximiza Σ wᵢ (q·qᵢ)², es decir el autovector del mayor autovalor de la
+// matriz M = Σ wᵢ qᵢ qᵢᵀ. Como cada término es cuadrático el signo de qᵢ no
+// importa. Minimiza la distancia cordal entre matrices de rotación, que para
+// orientaciones cercanas casi coincide con la media geodésica de
+// KarcherMean, y no necesita iterar.
+func Average(qs []Quaternion, weights []float64) (Quaternion, error) {
+	units, ws, err := normalizedWeights(qs, weights)
+	if err != nil {
+		return Quaternion{}, err
+	}
+	return markley(units, ws), nil
+}
+
+// markley calcula el promedio de Markley de cuaterniones ya normalizados
+func markley(qs []Quaternion, ws []float64) Quaternion {
+	var m [4][4]float64
+	for i, q := range qs {
+		v := [4]float64{q.A, q.B, q.C, q.D}
+		for r := 0; r < 4; r++ {
+			for c := r; c < 4; c++ {
+				m[r][c] += ws[i] * v[r] * v[c]
+			}
+		}
+	}
+	for r := 0; r < 4; r++ {
+		for c := 0; c < r; c++ {
+			m[r][c] = m[c][r]
+		}
+	}
+
+	values, vectors := symmetricEigen(m)
+	best := 0
+	for k := 1; k < 4; k++ {
+		if values[k] > values[best] {
+			best = k
+		}
+	}
+	q := New(vectors[0][best], vectors[1][best], vectors[2][best], vectors[3][best])
+	if n, err := q.Normalize(); err == nil {
+		q = n
+	}
+	return nonNegative(q)
+}
+
+// symmetricEigen calcula los autovalores y autovectores (por columnas) de
+// una matriz simétrica de 4×4 con el método cíclico de Jacobi, que es
+// estable aunque haya autovalores repetidos
+func symmetricEigen(m [4][4]float64) ([4]float64, [4][4]float64) {
+	v := [4][4]float64{{1, 0, 0, 0}, {0, 1, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}}
+	for sweep := 0; sweep < jacobiSweeps; sweep++ {
+		off := 0.0
+		for p := 0; p < 4; p++ {
+			for q := p + 1; q < 4; q++ {
+				off += m[p][q] * m[p][q]
+			}
+		}
+		if off == 0 {
+			break
+		}
+
+		for p := 0; p < 4; p++ {
+			for q := p + 1; q < 4; q++ {
+				if m[p][q] == 0 {
+					continue
+				}
+				// Rotación de Givens que anula m[p][q]
+				theta := (m[q][q] - m[p][p]) / (2 * m[p][q])
+				t := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < 4; k++ {
+					mkp, mkq := m[k][p], m[k][q]
+					m[k][p], m[k][q] = c*mkp-s*mkq, s*mkp+c*mkq
+				}
+				for k := 0; k < 4; k++ {
+					mpk, mqk := m[p][k], m[q][k]
+					m[p][k], m[q][k] = c*mpk-s*mqk, s*mpk+c*mqk
+				}
+				for k := 0; k < 4; k++ {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p], v[k][q] = c*vkp-s*vkq, s*vkp+c*vkq
+				}
+			}
+		}
+	}
+	return [4]float64{m[0][0], m[1][1], m[2][2], m[3][3]}, v
+}
+
+// KarcherMean calcula la media geodésica (de Karcher): la orientación que
+// minimiza la suma ponderada de los cuadrados de AngularDistance. Parte del
+// promedio de Markley y lo corrige en el espacio tangente con el promedio de
+// los vectores de rotación hasta que el paso es despreciable. Devuelve
+// ErrNoConvergence si las orientaciones están tan dispersas que la media no
+// es única.
+func KarcherMean(qs []Quaternion, weights []float64) (Quaternion, error) {
+	units, ws, err := normalizedWeights(qs, weights)
+	if err != nil {
+		return Quaternion{}, err
+	}
+	return karcher(units, ws)
+}
+
+// karcher itera la media geodésica de cuaterniones ya normalizados
+func karcher(qs []Quaternion, ws []float64) (Quaternion, error) {
+	mean := markley(qs, ws)
+	for iteration := 0; iteration < karcherMaxIterations; iteration++ {
+		var step [3]float64
+		for i, q := range qs {
+			r := RotationVector(mean, q)
+			for k := range step {
+				step[k] += ws[i] * r[k]
+			}
+		}
+		mean = mean.Multiply(pure(step).MultiplyReal(0.5).Exp())
+		if n, err := mean.Normalize(); err == nil {
+			mean = n
+		}
+		if math.Sqrt(step[0]*step[0]+step[1]*step[1]+step[2]*step[2]) < karcherTolerance {
+			return nonNegative(mean), nil
+		}
+	}
+	return Quaternion{}, ErrNoConvergence
+}
+
+// RotationStats resume la dispersión de un conjunto de orientaciones
+// alrededor de su media de Karcher. Los ángulos van en radianes.
+type RotationStats struct {
+	// Mean es la media de Karcher
+	Mean Quaternion
+	// RMS es la raíz de la media ponderada de los cuadrados de las
+	// distancias angulares a la media
+	RMS float64
+	// MeanDistance es la media ponderada de las distancias angulares
+	MeanDistance float64
+	// MaxDistance es la mayor distancia angular a la media entre las
+	// orientaciones con peso positivo
+	MaxDistance float64
+	// Covariance es la covarianza ponderada de los vectores de rotación
+	// desde la media, expresados en el sistema de la media. Su traza es RMS².
+	Covariance [3][3]float64
+}
+
+// Statistics calcula la media de Karcher y las medidas de dispersión de
+// las orientaciones
+func Statistics(qs []Quaternion, weights []float64) (RotationStats, error) {
+	units, ws, err := normalizedWeights(qs, weights)
+	if err != nil {
+		return RotationStats{}, err
+	}
+	mean, err := karcher(units, ws)
+	if err != nil {
+		return RotationStats{}, err
+	}
+
+	stats := RotationStats{Mean: mean}
+	for i, q := range units {
+		r := RotationVector(mean, q)
+		d2 := r[0]*r[0] + r[1]*r[1] + r[2]*r[2]
+		d := math.Sqrt(d2)
+		stats.RMS += ws[i] * d2
+		stats.MeanDistance += ws[i] * d
+		if ws[i] > 0 {
+			stats.MaxDistance = math.Max(stats.MaxDistance, d)
+		}
+		for a := 0; a < 3; a++ {
+			for b := 0; b < 3; b++ {
+				stats.Covariance[a][b] += ws[i] * r[a] * r[b]
+			}
+		}
+	}
+	stats.RMS = math.Sqrt(stats.RMS)
+	return stats, nil
+}
@@ -0,0 +1,226 @@
+package quaternion
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// cluster genera n orientaciones reproducibles alrededor de center, girando
+// ángulos de hasta spread radianes sobre ejes al azar, con signos mezclados
+func cluster(center Quaternion, n int, spread float64) []Quaternion {
+	r := rand.New(rand.NewSource(4))
+	qs := make([]Quaternion, n)
+	for i := range qs {
+		axis := [3]float64{r.NormFloat64(), r.NormFloat64(), r.NormFloat64()}
+		qs[i] = center.Multiply(FromAxisAngle(axis, spread*r.Float64()))
+		if i%2 == 1 {
+			qs[i] = qs[i].Negate()
+		}
+	}
+	return qs
+}
+
+var averageCenter = FromEuler([3]float64{0.9, 0.2, -1.4}, EulerZYX)
+
+// TestAngularDistance prueba la distancia con ambos signos y con ángulos
+// muy chicos
+func TestAngularDistance(t *testing.T) {
+	q := FromAxisAngle([3]float64{1, -2, 2}, 0.3)
+	p := q.Multiply(FromAxisAngle([3]float64{0, 1, 0}, 1.2))
+	for _, other := range []Quaternion{p, p.Negate(), p.MultiplyReal(3)} {
+		if d := AngularDistance(q, other); math.Abs(d-1.2) > 1e-14 {
+			t.Errorf("Expected 1.2, got %.17g", d)
+		}
+	}
+
+	tiny := q.Multiply(FromAxisAngle([3]float64{1, 1, 0}, 1e-9))
+	if d := AngularDistance(q, tiny); math.Abs(d-1e-9) > 1e-17 {
+		t.Errorf("Expected 1e-9, got %g", d)
+	}
+	if d := AngularDistance(q, Quaternion{}); !math.IsNaN(d) {
+		t.Errorf("Expected NaN with a zero quaternion, got %g", d)
+	}
+}
+
+// TestAverageIgnoresSign prueba que q y -q se promedian como la misma
+// orientación, cuando la media de los coeficientes daría cero
+func TestAverageIgnoresSign(t *testing.T) {
+	qs := []Quaternion{averageCenter, averageCenter.Negate(), averageCenter.MultiplyReal(2)}
+	for name, mean := range map[string]func([]Quaternion, []float64) (Quaternion, error){
+		"Average": Average, "KarcherMean": KarcherMean,
+	} {
+		q, err := mean(qs, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if d := AngularDistance(q, averageCenter); d > 1e-14 || q.A < 0 {
+			t.Errorf("%s: expected %s, got %s", name, nonNegative(averageCenter), q)
+		}
+	}
+}
+
+// TestAverageSymmetric prueba que giros opuestos alrededor del centro se
+// compensan
+func TestAverageSymmetric(t *testing.T) {
+	var qs []Quaternion
+	for _, axis := range [][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}} {
+		qs = append(qs, averageCenter.Multiply(FromAxisAngle(axis, 0.8)))
+		qs = append(qs, averageCenter.Multiply(FromAxisAngle(axis, -0.8)).Negate())
+	}
+	markley, _ := Average(qs, nil)
+	karcher, err := KarcherMean(qs, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, q := range []Quaternion{markley, karcher} {
+		if d := AngularDistance(q, averageCenter); d > 1e-12 {
+			t.Errorf("Expected %s, got %s", averageCenter, q)
+		}
+	}
+}
+
+// TestKarcherMeanWeighted prueba que con dos giros sobre el mismo eje la
+// media geodésica divide el ángulo según los pesos, como Slerp
+func TestKarcherMeanWeighted(t *testing.T) {
+	axis := [3]float64{2, -1, 2}
+	qs := []Quaternion{averageCenter, averageCenter.Multiply(FromAxisAngle(axis, 1.6))}
+	q, err := KarcherMean(qs, []float64{1, 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := averageCenter.Multiply(FromAxisAngle(axis, 1.2))
+	if d := AngularDistance(q, expected); d > 1e-12 {
+		t.Errorf("Expected %s, got %s", expected, q)
+	}
+
+	// Markley minimiza otra distancia: queda cerca pero no en el mismo punto
+	markley, _ := Average(qs, []float64{1, 3})
+	if d := AngularDistance(markley, expected); d < 1e-6 || d > 0.2 {
+		t.Errorf("Expected the Markley average near %s, got %s", expected, markley)
+	}
+}
+
+// TestKarcherMeanStationary prueba la condición de mínimo: los vectores de
+// rotación desde la media suman cero, y que Markley queda muy cerca cuando
+// la dispersión es chica
+func TestKarcherMeanStationary(t *testing.T) {
+	qs := cluster(averageCenter, 200, 0.5)
+	weights := make([]float64, len(qs))
+	for i := range weights {
+		weights[i] = 1 + float64(i%7)
+	}
+
+	mean, err := KarcherMean(qs, weights)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var sum [3]float64
+	for i, q := range qs {
+		r := RotationVector(mean, q)
+		for k := range sum {
+			sum[k] += weights[i] * r[k]
+		}
+	}
+	if !vectorsClose(sum, [3]float64{}, 1e-10) {
+		t.Errorf("Expected the weighted rotation vectors to add up to zero, got %v", sum)
+	}
+
+	small := cluster(averageCenter, 200, 0.05)
+	markley, _ := Average(small, nil)
+	karcher, _ := KarcherMean(small, nil)
+	if d := AngularDistance(markley, karcher); d > 1e-5 {
+		t.Errorf("Expected Markley and Karcher to agree within 1e-5, got %g", d)
+	}
+}
+
+// TestStatistics prueba las medidas de dispersión con giros conocidos sobre
+// el eje x de la media
+func TestStatistics(t *testing.T) {
+	qs := []Quaternion{
+		averageCenter.Multiply(FromAxisAngle([3]float64{1, 0, 0}, 0.3)),
+		averageCenter.Multiply(FromAxisAngle([3]float64{1, 0, 0}, -0.3)),
+		averageCenter.Multiply(FromAxisAngle([3]float64{1, 0, 0}, 0.1)),
+		averageCenter.Multiply(FromAxisAngle([3]float64{1, 0, 0}, -0.1)),
+	}
+	stats, err := Statistics(qs, []float64{1, 1, 3, 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d := AngularDistance(stats.Mean, averageCenter); d > 1e-12 {
+		t.Errorf("Expected mean %s, got %s", averageCenter, stats.Mean)
+	}
+	// Pesos normalizados 1/8 y 3/8
+	variance := (2*0.09 + 6*0.01) / 8
+	if math.Abs(stats.RMS-math.Sqrt(variance)) > 1e-12 {
+		t.Errorf("Expected RMS %g, got %g", math.Sqrt(variance), stats.RMS)
+	}
+	if expected := (2*0.3 + 6*0.1) / 8; math.Abs(stats.MeanDistance-expected) > 1e-12 {
+		t.Errorf("Expected mean distance %g, got %g", expected, stats.MeanDistance)
+	}
+	if math.Abs(stats.MaxDistance-0.3) > 1e-12 {
+		t.Errorf("Expected max distance 0.3, got %g", stats.MaxDistance)
+	}
+	for a := 0; a < 3; a++ {
+		for b := 0; b < 3; b++ {
+			expected := 0.0
+			if a == 0 && b == 0 {
+				expected = variance
+			}
+			if math.Abs(stats.Covariance[a][b]-expected) > 1e-12 {
+				t.Errorf("Expected covariance %v, got %v", expected, stats.Covariance)
+			}
+		}
+	}
+}
+
+// TestAverageErrors prueba los errores de entrada
+func TestAverageErrors(t *testing.T) {
+	qs := []Quaternion{Identity(), averageCenter}
+	cases := []struct {
+		qs      []Quaternion
+		weights []float64
+		err     error
+	}{
+		{nil, nil, ErrNoOrientations},
+		{qs, []float64{1}, ErrInvalidWeights},
+		{qs, []float64{1, -1}, ErrInvalidWeights},
+		{qs, []float64{0, 0}, ErrInvalidWeights},
+		{qs, []float64{1, math.NaN()}, ErrInvalidWeights},
+		{[]Quaternion{Identity(), {}}, nil, ErrZeroNorm},
+	}
+	for _, c := range cases {
+		if _, err := Average(c.qs, c.weights); !errors.Is(err, c.err) {
+			t.Errorf("Average: expected %v, got %v", c.err, err)
+		}
+		if _, err := KarcherMean(c.qs, c.weights); !errors.Is(err, c.err) {
+			t.Errorf("KarcherMean: expected %v, got %v", c.err, err)
+		}
+		if _, err := Statistics(c.qs, c.weights); !errors.Is(err, c.err) {
+			t.Errorf("Statistics: expected %v, got %v", c.err, err)
+		}
+	}
+}
+
+// TestSymmetricEigen prueba la descomposición con M v = λ v
+func TestSymmetricEigen(t *testing.T) {
+	m := [4][4]float64{
+		{4, 1, -2, 0.5},
+		{1, 3, 0, 1},
+		{-2, 0, 5, -1},
+		{0.5, 1, -1, 2},
+	}
+	values, vectors := symmetricEigen(m)
+	for k := 0; k < 4; k++ {
+		for r := 0; r < 4; r++ {
+			mv := 0.0
+			for c := 0; c < 4; c++ {
+				mv += m[r][c] * vectors[c][k]
+			}
+			if math.Abs(mv-values[k]*vectors[r][k]) > 1e-12 {
+				t.Fatalf("Eigenpair %d: M v = %g, λ v = %g", k, mv, values[k]*vectors[r][k])
+			}
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package quaternion
+
+import (
+	"math"
+	"runtime"
+	"sync"
+)
+
+// Operaciones por lotes sobre estructuras de arreglos: en lugar de un slice
+// de Quaternion se guarda un slice por componente. Cada bucle recorre
+// arreglos contiguos del mismo largo sin dependencias entre iteraciones, y
+// al reslicear todo al largo común el compilador elimina las comprobaciones
+// de límites. Es la disposición que necesitan las instrucciones SIMD; el
+// compilador de Go todavía no las emite solo, pero el bucle ya es el más
+// rápido que permite el lenguaje y puede repartirse entre goroutines.
+//
+// Todas las funciones aceptan que el destino sea uno de los operandos y
+// entran en pánico si los largos no coinciden, como la indexación fuera de
+// rango.
+
+// minParallelChunk es la menor cantidad de elementos por goroutine en las
+// variantes paralelas; con menos, el costo de coordinarlas supera la ganancia
+const minParallelChunk = 1 << 14
+
+// Batch es un lote de cuaterniones guardado por componentes: el cuaternión
+// i es A[i] + B[i]i + C[i]j + D[i]k. Los cuatro slices deben tener el mismo
+// largo.
+type Batch struct {
+	A, B, C, D []float64
+}
+
+// Vectors es un lote de vectores 3D guardado por componentes
+type Vectors struct {
+	X, Y, Z []float64
+}
+
+// MakeBatch crea un lote de n cuaterniones cero
+func MakeBatch(n int) Batch {
+	return Batch{A: make([]float64, n), B: make([]float64, n), C: make([]float64, n), D: make([]float64, n)}
+}
+
+// BatchFrom copia un slice de cuaterniones a un lote
+func BatchFrom(qs []Quaternion) Batch {
+	b := MakeBatch(len(qs))
+	for i, q := range qs {
+		b.Set(i, q)
+	}
+	return b
+}
+
+// Len devuelve la cantidad de cuaterniones del lote
+func (b Batch) Len() int {
+	return len(b.A)
+}
+
+// At devuelve el cuaternión i del lote
+func (b Batch) At(i int) Quaternion {
+	return Quaternion{A: b.A[i], B: b.B[i], C: b.C[i], D: b.D[i]}
+}
+
+// Set guarda q como cuaternión i del lote
+func (b Batch) Set(i int, q Quaternion) {
+	b.A[i], b.B[i], b.C[i], b.D[i] = q.A, q.B, q.C, q.D
+}
+
+// slice devuelve el sublote [lo, hi)
+func (b Batch) slice(lo, hi int) Batch {
+	return Batch{A: b.A[lo:hi], B: b.B[lo:hi], C: b.C[lo:hi], D: b.D[lo:hi]}
+}
+
+// checkLen verifica que los cuatro componentes tengan largo n
+func (b Batch) checkLen(n int) {
+	if len(b.A) != n || len(b.B) != n || len(b.C) != n || len(b.D) != n {
+		panic("quaternion: los lotes tienen largos distintos")
+	}
+}
+
+// MakeVectors crea un lote de n vectores cero
+func MakeVectors(n int) Vectors {
+	return Vectors{X: make([]float64, n), Y: make([]float64, n), Z: make([]float64, n)}
+}
+
+// VectorsFrom copia un slice de vectores a un lote
+func VectorsFrom(vs [][3]float64) Vectors {
+	v := MakeVectors(len(vs))
+	for i, x := range vs {
+		v.Set(i, x)
+	}
+	return v
+}
+
+// Len devuelve la cantidad de vectores del lote
+func (v Vectors) Len() int {
+	return len(v.X)
+}
+
+// At devuelve el vector i del lote
+func (v Vectors) At(i int) [3]float64 {
+	return [3]float64{v.X[i], v.Y[i], v.Z[i]}
+}
+
+// Set guarda x como vector i del lote
+func (v Vectors) Set(i int, x [3]float64) {
+	v.X[i], v.Y[i], v.Z[i] = x[0], x[1], x[2]
+}
+
+func (v Vectors) slice(lo, hi int) Vectors {
+	return Vectors{X: v.X[lo:hi], Y: v.Y[lo:hi], Z: v.Z[lo:hi]}
+}
+
+func (v Vectors) checkLen(n int) {
+	if len(v.X) != n || len(v.Y) != n || len(v.Z) != n {
+		panic("quaternion: los lotes tienen largos distintos")
+	}
+}
+
+// MultiplyBatch calcula dst[i] = p[i] * q[i] para todo i
+func MultiplyBatch(dst, p, q Batch) {
+	n := p.Len()
+	p.checkLen(n)
+	q.checkLen(n)
+	dst.checkLen(n)
+
+	a1, b1, c1, d1 := p.A[:n], p.B[:n], p.C[:n], p.D[:n]
+	a2, b2, c2, d2 := q.A[:n], q.B[:n], q.C[:n], q.D[:n]
+	ra, rb, rc, rd := dst.A[:n], dst.B[:n], dst.C[:n], dst.D[:n]
+	for i := range ra {
+		// Se leen todos los operandos antes de escribir para admitir que dst
+		// sea p o q
+		w1, x1, y1, z1 := a1[i], b1[i], c1[i], d1[i]
+		w2, x2, y2, z2 := a2[i], b2[i], c2[i], d2[i]
+		ra[i] = w1*w2 - x1*x2 - y1*y2 - z1*z2
+		rb[i] = w1*x2 + x1*w2 + y1*z2 - z1*y2
+		rc[i] = w1*y2 - x1*z2 + y1*w2 + z1*x2
+		rd[i] = w1*z2 + x1*y2 - y1*x2 + z1*w2
+	}
+}
+
+// RotateVectors rota todos los vectores de v con la misma rotación q y
+// guarda el resultado en dst. La matriz de q se calcula una sola vez, así
+// que cada vector cuesta nueve productos. Si q es cero los vectores se
+// copian sin cambios, como en RotateVector.
+func RotateVectors(dst Vectors, q Quaternion, v Vectors) {
+	n := v.Len()
+	v.checkLen(n)
+	dst.checkLen(n)
+
+	m := q.ToRotationMatrix()
+	xs, ys, zs := v.X[:n], v.Y[:n], v.Z[:n]
+	rx, ry, rz := dst.X[:n], dst.Y[:n], dst.Z[:n]
+	for i := range rx {
+		x, y, z := xs[i], ys[i], zs[i]
+		rx[i] = m[0][0]*x + m[0][1]*y + m[0][2]*z
+		ry[i] = m[1][0]*x + m[1][1]*y + m[1][2]*z
+		rz[i] = m[2][0]*x + m[2][1]*y + m[2][2]*z
+	}
+}
+
+// RotateVectorsBatch rota cada vector v[i] con su propia rotación qs[i],
+// que debe ser unitaria (ver NormalizeBatch)
+func RotateVectorsBatch(dst Vectors, qs Batch, v Vectors) {
+	n := v.Len()
+	v.checkLen(n)
+	qs.checkLen(n)
+	dst.checkLen(n)
+
+	ws, us, vs, ts := qs.A[:n], qs.B[:n], qs.C[:n], qs.D[:n]
+	xs, ys, zs := v.X[:n], v.Y[:n], v.Z[:n]
+	rx, ry, rz := dst.X[:n], dst.Y[:n], dst.Z[:n]
+	for i := range rx {
+		w, qx, qy, qz := ws[i], us[i], vs[i], ts[i]
+		x, y, z := xs[i], ys[i], zs[i]
+		// v' = v + 2w(u × v) + 2u × (u × v), como en RotateVector
+		tx := 2 * (qy*z - qz*y)
+		ty := 2 * (qz*x - qx*z)
+		tz := 2 * (qx*y - qy*x)
+		rx[i] = x + w*tx + (qy*tz - qz*ty)
+		ry[i] = y + w*ty + (qz*tx - qx*tz)
+		rz[i] = z + w*tz + (qx*ty - qy*tx)
+	}
+}
+
+// NormalizeBatch guarda en dst la normalización de cada cuaternión de src.
+// Los cuaterniones cero quedan en cero y en ese caso devuelve ErrZeroNorm
+// después de procesar todo el lote. A diferencia de Normalize no escala los
+// componentes antes de elevarlos al cuadrado, así que con componentes
+// mayores que 1e150 o menores que 1e-150 el resultado puede perder precisión.
+func NormalizeBatch(dst, src Batch) error {
+	n := src.Len()
+	src.checkLen(n)
+	dst.checkLen(n)
+
+	as, bs, cs, ds := src.A[:n], src.B[:n], src.C[:n], src.D[:n]
+	ra, rb, rc, rd := dst.A[:n], dst.B[:n], dst.C[:n], dst.D[:n]
+	zero := false
+	for i := range ra {
+		a, b, c, d := as[i], bs[i], cs[i], ds[i]
+		n2 := a*a + b*b + c*c + d*d
+		inv := 0.0
+		if n2 != 0 {
+			inv = 1 / math.Sqrt(n2)
+		} else {
+			zero = true
+		}
+		ra[i], rb[i], rc[i], rd[i] = a*inv, b*inv, c*inv, d*inv
+	}
+	if zero {
+		return ErrZeroNorm
+	}
+	return nil
+}
+
+// parallelFor reparte [0, n) en tramos contiguos entre hasta workers
+// goroutines (GOMAXPROCS si workers ≤ 0) y espera a que terminen. Con pocos
+// elementos llama a f una sola vez en la goroutine actual.
+func parallelFor(n, workers int, f func(lo, hi int)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if limit := n / minParallelChunk; workers > limit {
+		workers = limit
+	}
+	if workers <= 1 {
+		f(0, n)
+		return
+	}
+
+	var wg sync.WaitGroup
+	chunk := (n + workers - 1) / workers
+	for lo := 0; lo < n; lo += chunk {
+		hi := lo + chunk
+		if hi > n {
+			hi = n
+		}
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			f(lo, hi)
+		}(lo, hi)
+	}
+	wg.Wait()
+}
+
+// ParallelMultiplyBatch es MultiplyBatch repartido entre goroutines
+// (GOMAXPROCS si workers ≤ 0). Conviene a partir de cientos de miles de
+// elementos.
+func ParallelMultiplyBatch(dst, p, q Batch, workers int) {
+	n := p.Len()
+	p.checkLen(n)
+	q.checkLen(n)
+	dst.checkLen(n)
+	parallelFor(n, workers, func(lo, hi int) {
+		MultiplyBatch(dst.slice(lo, hi), p.slice(lo, hi), q.slice(lo, hi))
+	})
+}
+
+// ParallelRotateVectors es RotateVectors repartido entre goroutines
+func ParallelRotateVectors(dst Vectors, q Quaternion, v Vectors, workers int) {
+	n := v.Len()
+	v.checkLen(n)
+	dst.checkLen(n)
+	parallelFor(n, workers, func(lo, hi int) {
+		RotateVectors(dst.slice(lo, hi), q, v.slice(lo, hi))
+	})
+}
+
+// ParallelNormalizeBatch es NormalizeBatch repartido entre goroutines
+func ParallelNormalizeBatch(dst, src Batch, workers int) error {
+	n := src.Len()
+	src.checkLen(n)
+	dst.checkLen(n)
+	var mu sync.Mutex
+	var result error
+	parallelFor(n, workers, func(lo, hi int) {
+		if err := NormalizeBatch(dst.slice(lo, hi), src.slice(lo, hi)); err != nil {
+			mu.Lock()
+			result = err
+			mu.Unlock()
+		}
+	})
+	return result
+}
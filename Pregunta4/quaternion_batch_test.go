@@ -0,0 +1,215 @@
+package quaternion
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// randomVectors genera vectores de prueba reproducibles
+func randomVectors(n int) [][3]float64 {
+	r := rand.New(rand.NewSource(2))
+	vs := make([][3]float64, n)
+	for i := range vs {
+		vs[i] = [3]float64{2*r.Float64() - 1, 2*r.Float64() - 1, 2*r.Float64() - 1}
+	}
+	return vs
+}
+
+// unitQuaternions normaliza cuaterniones de prueba
+func unitQuaternions(n int) []Quaternion {
+	qs := randomQuaternions(n, 1)
+	for i := range qs {
+		qs[i], _ = qs[i].Normalize()
+	}
+	return qs
+}
+
+// TestMultiplyBatchMatchesScalar prueba que el lote coincide con Multiply
+// y que el destino puede ser un operando
+func TestMultiplyBatchMatchesScalar(t *testing.T) {
+	ps := randomQuaternions(100, 3)
+	qs := randomQuaternions(100, 2)[50:]
+	ps = ps[:50]
+	p, q := BatchFrom(ps), BatchFrom(qs)
+
+	dst := MakeBatch(50)
+	MultiplyBatch(dst, p, q)
+	MultiplyBatch(p, p, q)
+	for i := range ps {
+		expected := ps[i].Multiply(qs[i])
+		if !dst.At(i).Equals(expected) || !p.At(i).Equals(expected) {
+			t.Fatalf("Element %d: expected %s, got %s and %s", i, expected, dst.At(i), p.At(i))
+		}
+	}
+}
+
+// TestRotateVectorsMatchesScalar prueba ambas rotaciones por lotes
+func TestRotateVectorsMatchesScalar(t *testing.T) {
+	vs := randomVectors(64)
+	qs := unitQuaternions(64)
+	q := FromEuler([3]float64{0.3, -0.7, 1.9}, EulerZYX)
+
+	v := VectorsFrom(vs)
+	same, each := MakeVectors(64), MakeVectors(64)
+	RotateVectors(same, q, v)
+	RotateVectorsBatch(each, BatchFrom(qs), v)
+	for i := range vs {
+		if expected := q.RotateVector(vs[i]); !vectorsClose(same.At(i), expected, 1e-14) {
+			t.Fatalf("RotateVectors %d: expected %v, got %v", i, expected, same.At(i))
+		}
+		if expected := qs[i].RotateVector(vs[i]); !vectorsClose(each.At(i), expected, 1e-14) {
+			t.Fatalf("RotateVectorsBatch %d: expected %v, got %v", i, expected, each.At(i))
+		}
+	}
+}
+
+// TestNormalizeBatch prueba la normalización y el error con ceros
+func TestNormalizeBatch(t *testing.T) {
+	qs := []Quaternion{New(0, 3, 0, 4), New(1, 2, 3, 4), {}, New(-2, 0, 0, 0)}
+	b := BatchFrom(qs)
+	if err := NormalizeBatch(b, b); !errors.Is(err, ErrZeroNorm) {
+		t.Errorf("Expected ErrZeroNorm, got %v", err)
+	}
+	for i, q := range qs {
+		expected, err := q.Normalize()
+		if err != nil {
+			expected = Quaternion{}
+		}
+		if !b.At(i).Equals(expected) {
+			t.Errorf("Element %d: expected %s, got %s", i, expected, b.At(i))
+		}
+	}
+	if err := NormalizeBatch(MakeBatch(1), BatchFrom(qs[:1])); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+// TestParallelBatchMatchesSerial prueba que las variantes paralelas dan el
+// mismo resultado que las seriales con lotes que se reparten en varios tramos
+func TestParallelBatchMatchesSerial(t *testing.T) {
+	const n = 5*minParallelChunk + 123
+	p, q := BatchFrom(randomQuaternions(n, 2)), BatchFrom(randomQuaternions(n, 1))
+
+	serial, parallel := MakeBatch(n), MakeBatch(n)
+	MultiplyBatch(serial, p, q)
+	ParallelMultiplyBatch(parallel, p, q, 4)
+	if err := ParallelNormalizeBatch(parallel, parallel, 4); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	NormalizeBatch(serial, serial)
+	for i := 0; i < n; i++ {
+		if serial.At(i) != parallel.At(i) {
+			t.Fatalf("Element %d: expected %s, got %s", i, serial.At(i), parallel.At(i))
+		}
+	}
+
+	v := VectorsFrom(randomVectors(n))
+	rotation := FromAxisAngle([3]float64{1, 2, 3}, 0.5)
+	vs, vp := MakeVectors(n), MakeVectors(n)
+	RotateVectors(vs, rotation, v)
+	ParallelRotateVectors(vp, rotation, v, 0)
+	for i := 0; i < n; i++ {
+		if vs.At(i) != vp.At(i) {
+			t.Fatalf("Vector %d: expected %v, got %v", i, vs.At(i), vp.At(i))
+		}
+	}
+}
+
+// TestBatchLengthMismatchPanics prueba que los largos distintos se detectan
+func TestBatchLengthMismatchPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic with mismatched lengths")
+		}
+	}()
+	MultiplyBatch(MakeBatch(3), MakeBatch(3), MakeBatch(2))
+}
+
+// Benchmarks: el bucle escalar sobre []Quaternion contra el lote por
+// componentes y su variante paralela
+
+const benchmarkBatchSize = 1 << 20
+
+func BenchmarkMultiplyScalarLoop(b *testing.B) {
+	ps, qs := randomQuaternions(benchmarkBatchSize, 1), randomQuaternions(benchmarkBatchSize, 2)
+	dst := make([]Quaternion, benchmarkBatchSize)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range dst {
+			dst[i] = ps[i].Multiply(qs[i])
+		}
+	}
+}
+
+func BenchmarkMultiplyBatch(b *testing.B) {
+	p := BatchFrom(randomQuaternions(benchmarkBatchSize, 1))
+	q := BatchFrom(randomQuaternions(benchmarkBatchSize, 2))
+	dst := MakeBatch(benchmarkBatchSize)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		MultiplyBatch(dst, p, q)
+	}
+}
+
+func BenchmarkParallelMultiplyBatch(b *testing.B) {
+	p := BatchFrom(randomQuaternions(benchmarkBatchSize, 1))
+	q := BatchFrom(randomQuaternions(benchmarkBatchSize, 2))
+	dst := MakeBatch(benchmarkBatchSize)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ParallelMultiplyBatch(dst, p, q, 0)
+	}
+}
+
+func BenchmarkRotateVectorScalarLoop(b *testing.B) {
+	vs := randomVectors(benchmarkBatchSize)
+	dst := make([][3]float64, benchmarkBatchSize)
+	q := FromAxisAngle([3]float64{1, 2, 3}, 0.5)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range dst {
+			dst[i] = q.RotateVector(vs[i])
+		}
+	}
+}
+
+func BenchmarkRotateVectors(b *testing.B) {
+	v := VectorsFrom(randomVectors(benchmarkBatchSize))
+	dst := MakeVectors(benchmarkBatchSize)
+	q := FromAxisAngle([3]float64{1, 2, 3}, 0.5)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		RotateVectors(dst, q, v)
+	}
+}
+
+func BenchmarkParallelRotateVectors(b *testing.B) {
+	v := VectorsFrom(randomVectors(benchmarkBatchSize))
+	dst := MakeVectors(benchmarkBatchSize)
+	q := FromAxisAngle([3]float64{1, 2, 3}, 0.5)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ParallelRotateVectors(dst, q, v, 0)
+	}
+}
+
+func BenchmarkNormalizeScalarLoop(b *testing.B) {
+	qs := randomQuaternions(benchmarkBatchSize, 2)
+	dst := make([]Quaternion, benchmarkBatchSize)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range dst {
+			dst[i], _ = qs[i].Normalize()
+		}
+	}
+}
+
+func BenchmarkNormalizeBatch(b *testing.B) {
+	src := BatchFrom(randomQuaternions(benchmarkBatchSize, 2))
+	dst := MakeBatch(benchmarkBatchSize)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		NormalizeBatch(dst, src)
+	}
+}
@@ -0,0 +1,72 @@
+package quaternion
+
+import (
+	"errors"
+	"math"
+)
+
+// Política de errores: las operaciones que dividen por la norma devuelven
+// ErrZeroNorm si el cuaternión es cero, porque no tiene inverso ni dirección.
+// Los componentes infinitos o NaN no se tratan como error: se propagan y el
+// resultado tiene componentes NaN, igual que en la aritmética de float64.
+
+// ErrZeroNorm indica que se intentó invertir, normalizar o dividir por el
+// cuaternión cero
+var ErrZeroNorm = errors.New("quaternion: el cuaternión tiene norma cero")
+
+// Norm2 calcula el cuadrado de la norma del cuaternión
+// &(a + bi + cj + dk)² = a² + b² + c² + d²
+func (q Quaternion) Norm2() float64 {
+	return q.A*q.A + q.B*q.B + q.C*q.C + q.D*q.D
+}
+
+// scaled divide el cuaternión por su mayor componente en valor absoluto y
+// devuelve también ese factor. Trabajar con la versión escalada evita que
+// a² + b² + c² + d² desborde o se anule con componentes muy grandes o muy
+// pequeños.
+func (q Quaternion) scaled() (Quaternion, float64) {
+	scale := math.Max(math.Max(math.Abs(q.A), math.Abs(q.B)), math.Max(math.Abs(q.C), math.Abs(q.D)))
+	if scale == 0 {
+		return q, 0
+	}
+	return q.MultiplyReal(1 / scale), scale
+}
+
+// Inverse calcula el inverso multiplicativo del cuaternión
+// q⁻¹ = ~q / &q², de modo que q * q⁻¹ = q⁻¹ * q = 1
+func (q Quaternion) Inverse() (Quaternion, error) {
+	p, scale := q.scaled()
+	if scale == 0 {
+		return Quaternion{}, ErrZeroNorm
+	}
+	return p.Conjugate().MultiplyReal(1 / (p.Norm2() * scale)), nil
+}
+
+// Normalize devuelve el cuaternión unitario con la misma dirección
+// q / &q
+func (q Quaternion) Normalize() (Quaternion, error) {
+	p, scale := q.scaled()
+	if scale == 0 {
+		return Quaternion{}, ErrZeroNorm
+	}
+	return p.MultiplyReal(1 / math.Sqrt(p.Norm2())), nil
+}
+
+// RightDivide divide por la derecha: q * other⁻¹.
+// Como el producto no es conmutativo, en general difiere de LeftDivide.
+func (q Quaternion) RightDivide(other Quaternion) (Quaternion, error) {
+	inverse, err := other.Inverse()
+	if err != nil {
+		return Quaternion{}, err
+	}
+	return q.Multiply(inverse), nil
+}
+
+// LeftDivide divide por la izquierda: other⁻¹ * q
+func (q Quaternion) LeftDivide(other Quaternion) (Quaternion, error) {
+	inverse, err := other.Inverse()
+	if err != nil {
+		return Quaternion{}, err
+	}
+	return inverse.Multiply(q), nil
+}
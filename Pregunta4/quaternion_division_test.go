@@ -0,0 +1,121 @@
+package quaternion
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// TestNorm2 prueba el cuadrado de la norma
+func TestNorm2(t *testing.T) {
+	if n := New(1, 2, 3, 4).Norm2(); n != 30 {
+		t.Errorf("Expected 30, got %f", n)
+	}
+}
+
+// TestInverseBasic prueba que q * q⁻¹ = q⁻¹ * q = 1
+func TestInverseBasic(t *testing.T) {
+	q := New(1, 2, 3, 4)
+	inverse, err := q.Inverse()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := New(1.0/30, -2.0/30, -3.0/30, -4.0/30)
+	if !inverse.Equals(expected) {
+		t.Errorf("Expected %s, got %s", expected, inverse)
+	}
+	if !q.Multiply(inverse).Equals(FromReal(1)) || !inverse.Multiply(q).Equals(FromReal(1)) {
+		t.Errorf("q * q⁻¹ should be 1, got %s and %s", q.Multiply(inverse), inverse.Multiply(q))
+	}
+}
+
+// TestInverseExtremeMagnitudes prueba que no hay desbordes con componentes
+// muy grandes o muy pequeños
+func TestInverseExtremeMagnitudes(t *testing.T) {
+	for _, scale := range []float64{1e-170, 1e170} {
+		q := New(1, 2, 3, 4).MultiplyReal(scale)
+		inverse, err := q.Inverse()
+		if err != nil {
+			t.Fatalf("Scale %g: unexpected error: %v", scale, err)
+		}
+		if product := q.Multiply(inverse); !product.Equals(FromReal(1)) {
+			t.Errorf("Scale %g: q * q⁻¹ should be 1, got %s", scale, product)
+		}
+	}
+}
+
+// TestNormalizeBasic prueba la normalización
+func TestNormalizeBasic(t *testing.T) {
+	n, err := New(0, 3, 0, 4).Normalize()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !n.Equals(New(0, 0.6, 0, 0.8)) {
+		t.Errorf("Expected (0 + 0.6i + 0j + 0.8k), got %s", n)
+	}
+
+	n, _ = New(1e-200, 0, 0, 0).Normalize()
+	if !n.Equals(FromReal(1)) {
+		t.Errorf("Tiny quaternions should normalize to unit length, got %s", n)
+	}
+}
+
+// TestZeroNormErrors prueba el error con el cuaternión cero
+func TestZeroNormErrors(t *testing.T) {
+	zero := New(0, 0, 0, 0)
+	q := New(1, 2, 3, 4)
+
+	if _, err := zero.Inverse(); !errors.Is(err, ErrZeroNorm) {
+		t.Errorf("Inverse of zero: expected ErrZeroNorm, got %v", err)
+	}
+	if _, err := zero.Normalize(); !errors.Is(err, ErrZeroNorm) {
+		t.Errorf("Normalize zero: expected ErrZeroNorm, got %v", err)
+	}
+	if _, err := q.RightDivide(zero); !errors.Is(err, ErrZeroNorm) {
+		t.Errorf("RightDivide by zero: expected ErrZeroNorm, got %v", err)
+	}
+	if _, err := q.LeftDivide(zero); !errors.Is(err, ErrZeroNorm) {
+		t.Errorf("LeftDivide by zero: expected ErrZeroNorm, got %v", err)
+	}
+}
+
+// TestNaNPropagates prueba que los NaN se propagan sin error
+func TestNaNPropagates(t *testing.T) {
+	n, err := New(math.NaN(), 1, 0, 0).Normalize()
+	if err != nil || !math.IsNaN(n.A) {
+		t.Errorf("Expected NaN without error, got %s, %v", n, err)
+	}
+}
+
+// TestDivisionNonCommutative prueba que la división izquierda y derecha difieren
+func TestDivisionNonCommutative(t *testing.T) {
+	i := New(0, 1, 0, 0)
+	j := New(0, 0, 1, 0)
+	k := New(0, 0, 0, 1)
+
+	// k * j⁻¹ = k * (-j) = i   y   j⁻¹ * k = -j * k = -i
+	right, _ := k.RightDivide(j)
+	left, _ := k.LeftDivide(j)
+	if !right.Equals(i) {
+		t.Errorf("Expected i, got %s", right)
+	}
+	if !left.Equals(i.Negate()) {
+		t.Errorf("Expected -i, got %s", left)
+	}
+}
+
+// TestDivisionUndoesMultiplication prueba que dividir deshace el producto
+func TestDivisionUndoesMultiplication(t *testing.T) {
+	p := New(1, 2, 3, 4)
+	q := New(-2, 0.5, 1, 3)
+
+	right, _ := p.Multiply(q).RightDivide(q)
+	left, _ := p.Multiply(q).LeftDivide(p)
+	if !right.Equals(p) {
+		t.Errorf("(p * q) / q should be p, got %s", right)
+	}
+	if !left.Equals(q) {
+		t.Errorf("p \\ (p * q) should be q, got %s", left)
+	}
+}
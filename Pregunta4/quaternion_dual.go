@@ -0,0 +1,232 @@
+package quaternion
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Cuaterniones duales r + εd con ε² = 0. Un cuaternión dual unitario (r
+// unitario y r·d = 0) representa un movimiento rígido: la rotación r seguida
+// de la traslación t, con d = t·r/2 tomando t como cuaternión puro. Como con
+// las matrices, a.Multiply(b) aplica primero b y luego a.
+
+// ErrNotRigidTransform indica que una matriz 4×4 no es un movimiento rígido
+// porque su última fila no es (0, 0, 0, 1) o su bloque 3×3 no es una rotación
+var ErrNotRigidTransform = errors.New("quaternion: la matriz no es un movimiento rígido")
+
+// bottomRowTolerance es la diferencia admitida entre la última fila de una
+// matriz homogénea y (0, 0, 0, 1)
+const bottomRowTolerance = 1e-9
+
+// screwThreshold es el seno del medio giro por debajo del cual ScLERP no usa
+// los parámetros de tornillo: su error de redondeo crece como ε/sen y el de
+// interpolar giro y traslación por separado como sen, y ambos se igualan
+// cerca de √ε
+const screwThreshold = 1e-8
+
+// DualQuaternion es un cuaternión dual Real + εDual
+type DualQuaternion struct {
+	Real Quaternion
+	Dual Quaternion
+}
+
+// pure construye el cuaternión puro del vector v
+func pure(v [3]float64) Quaternion {
+	return Quaternion{B: v[0], C: v[1], D: v[2]}
+}
+
+// NewRigidTransform crea el movimiento rígido que rota con rotation y luego
+// traslada por translation. La rotación se normaliza; si es el cuaternión
+// cero se usa la identidad.
+func NewRigidTransform(rotation Quaternion, translation [3]float64) DualQuaternion {
+	r, err := rotation.Normalize()
+	if err != nil {
+		r = Identity()
+	}
+	return DualQuaternion{Real: r, Dual: pure(translation).Multiply(r).MultiplyReal(0.5)}
+}
+
+// DualIdentity devuelve el movimiento rígido que no mueve nada
+func DualIdentity() DualQuaternion {
+	return DualQuaternion{Real: Identity()}
+}
+
+// DualFromTranslation crea una traslación pura
+func DualFromTranslation(translation [3]float64) DualQuaternion {
+	return NewRigidTransform(Identity(), translation)
+}
+
+// String devuelve una representación en string del cuaternión dual
+func (dq DualQuaternion) String() string {
+	return fmt.Sprintf("%s + ε%s", dq.Real, dq.Dual)
+}
+
+// Add suma dos cuaterniones duales componente a componente
+func (dq DualQuaternion) Add(other DualQuaternion) DualQuaternion {
+	return DualQuaternion{Real: dq.Real.Add(other.Real), Dual: dq.Dual.Add(other.Dual)}
+}
+
+// Multiply calcula el producto de cuaterniones duales
+// (r₁ + εd₁)(r₂ + εd₂) = r₁r₂ + ε(r₁d₂ + d₁r₂)
+// Con movimientos rígidos es la composición: primero other y luego dq.
+func (dq DualQuaternion) Multiply(other DualQuaternion) DualQuaternion {
+	return DualQuaternion{
+		Real: dq.Real.Multiply(other.Real),
+		Dual: dq.Real.Multiply(other.Dual).Add(dq.Dual.Multiply(other.Real)),
+	}
+}
+
+// Conjugate calcula la conjugada cuaterniónica de ambas partes, ~r + ε~d.
+// Para un movimiento rígido es su inverso.
+func (dq DualQuaternion) Conjugate() DualQuaternion {
+	return DualQuaternion{Real: dq.Real.Conjugate(), Dual: dq.Dual.Conjugate()}
+}
+
+// Inverse calcula el inverso multiplicativo
+// (r + εd)⁻¹ = r⁻¹ - εr⁻¹dr⁻¹
+// Devuelve ErrZeroNorm si la parte real es cero, porque entonces no existe.
+func (dq DualQuaternion) Inverse() (DualQuaternion, error) {
+	r, err := dq.Real.Inverse()
+	if err != nil {
+		return DualQuaternion{}, err
+	}
+	return DualQuaternion{Real: r, Dual: r.Multiply(dq.Dual).Multiply(r).Negate()}, nil
+}
+
+// Normalize devuelve el cuaternión dual unitario más cercano: divide por la
+// norma de la parte real y quita de la dual la componente paralela a la real,
+// de modo que el resultado vuelva a ser un movimiento rígido. Devuelve
+// ErrZeroNorm si la parte real es cero.
+func (dq DualQuaternion) Normalize() (DualQuaternion, error) {
+	norm := dq.Real.norm()
+	if norm == 0 {
+		return DualQuaternion{}, ErrZeroNorm
+	}
+	r := dq.Real.MultiplyReal(1 / norm)
+	d := dq.Dual.MultiplyReal(1 / norm)
+	return DualQuaternion{Real: r, Dual: d.Subtract(r.MultiplyReal(r.Dot(d)))}, nil
+}
+
+// Rotation devuelve la parte de rotación del movimiento rígido
+func (dq DualQuaternion) Rotation() Quaternion {
+	return dq.Real
+}
+
+// Translation devuelve la traslación del movimiento rígido
+// t = 2d~r
+func (dq DualQuaternion) Translation() [3]float64 {
+	t := dq.Dual.Multiply(dq.Real.Conjugate()).MultiplyReal(2)
+	return [3]float64{t.B, t.C, t.D}
+}
+
+// TransformPoint aplica el movimiento rígido al punto p: lo rota y luego lo
+// traslada
+func (dq DualQuaternion) TransformPoint(p [3]float64) [3]float64 {
+	v := dq.Real.RotateVector(p)
+	t := dq.Translation()
+	return [3]float64{v[0] + t[0], v[1] + t[1], v[2] + t[2]}
+}
+
+// TransformVector aplica solo la rotación, como corresponde a direcciones
+func (dq DualQuaternion) TransformVector(v [3]float64) [3]float64 {
+	return dq.Real.RotateVector(v)
+}
+
+// Equals compara dos cuaterniones duales con una tolerancia pequeña.
+// dq y -dq representan el mismo movimiento pero no se consideran iguales.
+func (dq DualQuaternion) Equals(other DualQuaternion) bool {
+	return dq.Real.Equals(other.Real) && dq.Dual.Equals(other.Dual)
+}
+
+// ToMatrix devuelve la matriz homogénea 4×4 del movimiento rígido, con la
+// rotación en el bloque 3×3 y la traslación en la última columna
+func (dq DualQuaternion) ToMatrix() [4][4]float64 {
+	m := dq.Real.ToHomogeneousMatrix()
+	t := dq.Translation()
+	m[0][3], m[1][3], m[2][3] = t[0], t[1], t[2]
+	return m
+}
+
+// DualFromMatrix crea el movimiento rígido de una matriz homogénea 4×4. El
+// bloque de rotación se ortonormaliza como en FromRotationMatrix. Devuelve
+// ErrNotRigidTransform si la matriz no es un movimiento rígido.
+func DualFromMatrix(m [4][4]float64) (DualQuaternion, error) {
+	bottom := [4]float64{0, 0, 0, 1}
+	for j, expected := range bottom {
+		if !(math.Abs(m[3][j]-expected) <= bottomRowTolerance) {
+			return DualQuaternion{}, ErrNotRigidTransform
+		}
+	}
+
+	var rotation [3][3]float64
+	for i := 0; i < 3; i++ {
+		copy(rotation[i][:], m[i][:3])
+	}
+	r, err := FromRotationMatrix(rotation)
+	if err != nil {
+		return DualQuaternion{}, fmt.Errorf("%w: %v", ErrNotRigidTransform, err)
+	}
+	translation := [3]float64{m[0][3], m[1][3], m[2][3]}
+	for _, x := range translation {
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return DualQuaternion{}, ErrNotRigidTransform
+		}
+	}
+	return NewRigidTransform(r, translation), nil
+}
+
+// pow eleva un movimiento rígido unitario a un exponente real con sus
+// parámetros de tornillo: un giro θ alrededor de la recta de dirección n y
+// momento m, más un avance δ a lo largo de ella. Elevar a t multiplica θ y δ
+// por t sin cambiar la recta.
+func (dq DualQuaternion) pow(t float64) DualQuaternion {
+	r, e := dq.Real, dq.Dual
+	s := r.vectorNorm()
+	if s < screwThreshold {
+		// Con un giro tan pequeño la recta del tornillo queda mal definida:
+		// se interpolan por separado el giro y la traslación, lo que difiere
+		// del tornillo en un orden del ángulo
+		rotation := r.Pow(t)
+		translation := dq.Translation()
+		for i := range translation {
+			translation[i] *= t
+		}
+		return NewRigidTransform(rotation, translation)
+	}
+
+	halfTheta := math.Atan2(s, r.A)
+	n := [3]float64{r.B / s, r.C / s, r.D / s}
+	halfDelta := -e.A / s
+	cos := math.Cos(halfTheta)
+	m := [3]float64{
+		(e.B - n[0]*halfDelta*cos) / s,
+		(e.C - n[1]*halfDelta*cos) / s,
+		(e.D - n[2]*halfDelta*cos) / s,
+	}
+
+	halfTheta *= t
+	halfDelta *= t
+	sinT, cosT := math.Sin(halfTheta), math.Cos(halfTheta)
+	return DualQuaternion{
+		Real: Quaternion{A: cosT, B: n[0] * sinT, C: n[1] * sinT, D: n[2] * sinT},
+		Dual: Quaternion{
+			A: -halfDelta * sinT,
+			B: m[0]*sinT + n[0]*halfDelta*cosT,
+			C: m[1]*sinT + n[1]*halfDelta*cosT,
+			D: m[2]*sinT + n[2]*halfDelta*cosT,
+		},
+	}
+}
+
+// ScLERP interpola entre dos movimientos rígidos unitarios a lo largo del
+// tornillo que los une, a(a⁻¹b)^t: la rotación avanza como Slerp y la
+// traslación acompaña al giro en lugar de ir en línea recta. Sigue el camino
+// más corto entre a y b.
+func ScLERP(a, b DualQuaternion, t float64) DualQuaternion {
+	diff := a.Conjugate().Multiply(b)
+	if diff.Real.A < 0 {
+		diff = DualQuaternion{Real: diff.Real.Negate(), Dual: diff.Dual.Negate()}
+	}
+	return a.Multiply(diff.pow(t))
+}
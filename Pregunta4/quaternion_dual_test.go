@@ -0,0 +1,201 @@
+package quaternion
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// rigidTransformsForTest son movimientos rígidos de prueba
+func rigidTransformsForTest() []DualQuaternion {
+	return []DualQuaternion{
+		DualIdentity(),
+		DualFromTranslation([3]float64{1, -2, 3}),
+		NewRigidTransform(FromAxisAngle([3]float64{0, 0, 1}, math.Pi/2), [3]float64{0, 0, 0}),
+		NewRigidTransform(FromEuler([3]float64{0.3, -1.2, 2.5}, EulerZYX), [3]float64{4, 0.5, -7}),
+		NewRigidTransform(FromAxisAngle([3]float64{1, 1, 1}, 3), [3]float64{-1, 10, 2}),
+	}
+}
+
+// TestDualComposeWithInverse prueba que componer con el inverso da la identidad
+func TestDualComposeWithInverse(t *testing.T) {
+	for _, dq := range rigidTransformsForTest() {
+		inverse, err := dq.Inverse()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if r := dq.Multiply(inverse); !r.Equals(DualIdentity()) {
+			t.Errorf("dq * dq⁻¹ should be the identity, got %s", r)
+		}
+		if r := inverse.Multiply(dq); !r.Equals(DualIdentity()) {
+			t.Errorf("dq⁻¹ * dq should be the identity, got %s", r)
+		}
+		// Para movimientos rígidos el inverso es la conjugada
+		if !inverse.Equals(dq.Conjugate()) {
+			t.Errorf("Expected the inverse %s to match the conjugate %s", inverse, dq.Conjugate())
+		}
+	}
+}
+
+// TestDualInverseZero prueba el error con parte real nula
+func TestDualInverseZero(t *testing.T) {
+	if _, err := (DualQuaternion{Dual: New(1, 0, 0, 0)}).Inverse(); !errors.Is(err, ErrZeroNorm) {
+		t.Errorf("Expected ErrZeroNorm, got %v", err)
+	}
+}
+
+// TestDualTransformPoint prueba que un punto se rota y luego se traslada
+func TestDualTransformPoint(t *testing.T) {
+	dq := NewRigidTransform(FromAxisAngle([3]float64{0, 0, 1}, math.Pi/2), [3]float64{1, 2, 3})
+	p := dq.TransformPoint([3]float64{1, 0, 0})
+	if expected := [3]float64{1, 3, 3}; !vectorsClose(p, expected, 1e-12) {
+		t.Errorf("Expected %v, got %v", expected, p)
+	}
+	// Las direcciones no se trasladan
+	v := dq.TransformVector([3]float64{1, 0, 0})
+	if expected := [3]float64{0, 1, 0}; !vectorsClose(v, expected, 1e-12) {
+		t.Errorf("Expected %v, got %v", expected, v)
+	}
+}
+
+// TestDualComposition prueba que a * b aplica primero b y luego a
+func TestDualComposition(t *testing.T) {
+	transforms := rigidTransformsForTest()
+	p := [3]float64{0.5, -1, 2}
+	for _, a := range transforms {
+		for _, b := range transforms {
+			got := a.Multiply(b).TransformPoint(p)
+			expected := a.TransformPoint(b.TransformPoint(p))
+			if !vectorsClose(got, expected, 1e-12) {
+				t.Errorf("Expected %v, got %v", expected, got)
+			}
+		}
+	}
+}
+
+// TestDualRotationAndTranslation prueba la extracción de ambas partes
+func TestDualRotationAndTranslation(t *testing.T) {
+	rotation := FromEuler([3]float64{0.3, -1.2, 2.5}, EulerZYX)
+	translation := [3]float64{4, 0.5, -7}
+	dq := NewRigidTransform(rotation.MultiplyReal(2), translation)
+
+	if !dq.Rotation().Equals(rotation) {
+		t.Errorf("Expected rotation %s, got %s", rotation, dq.Rotation())
+	}
+	if tr := dq.Translation(); !vectorsClose(tr, translation, 1e-12) {
+		t.Errorf("Expected translation %v, got %v", translation, tr)
+	}
+}
+
+// TestDualNormalize prueba que Normalize devuelve un movimiento rígido
+func TestDualNormalize(t *testing.T) {
+	dq := NewRigidTransform(FromAxisAngle([3]float64{0, 1, 0}, 1), [3]float64{1, 2, 3})
+	drifted := DualQuaternion{
+		Real: dq.Real.MultiplyReal(1.01),
+		Dual: dq.Dual.Add(dq.Real.MultiplyReal(0.02)),
+	}
+	n, err := drifted.Normalize()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if math.Abs(n.Real.Abs()-1) > 1e-15 || math.Abs(n.Real.Dot(n.Dual)) > 1e-15 {
+		t.Errorf("Expected a unit dual quaternion, got %s", n)
+	}
+	if _, err := (DualQuaternion{}).Normalize(); !errors.Is(err, ErrZeroNorm) {
+		t.Errorf("Expected ErrZeroNorm, got %v", err)
+	}
+}
+
+// TestDualMatrixRoundTrip prueba la ida y vuelta con matrices 4×4
+func TestDualMatrixRoundTrip(t *testing.T) {
+	for _, dq := range rigidTransformsForTest() {
+		m := dq.ToMatrix()
+		back, err := DualFromMatrix(m)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		p := [3]float64{1, 2, 3}
+		if a, b := back.TransformPoint(p), dq.TransformPoint(p); !vectorsClose(a, b, 1e-12) {
+			t.Errorf("Expected %v, got %v", b, a)
+		}
+
+		// La matriz aplicada a un punto en coordenadas homogéneas coincide
+		var mp [3]float64
+		for i := 0; i < 3; i++ {
+			mp[i] = m[i][0]*p[0] + m[i][1]*p[1] + m[i][2]*p[2] + m[i][3]
+		}
+		if expected := dq.TransformPoint(p); !vectorsClose(mp, expected, 1e-12) {
+			t.Errorf("Expected %v, got %v", expected, mp)
+		}
+	}
+}
+
+// TestDualFromMatrixErrors prueba las matrices que no son movimientos rígidos
+func TestDualFromMatrixErrors(t *testing.T) {
+	projective := DualIdentity().ToMatrix()
+	projective[3][2] = 1
+	reflection := DualIdentity().ToMatrix()
+	reflection[2][2] = -1
+
+	for name, m := range map[string][4][4]float64{"projective": projective, "reflection": reflection} {
+		if _, err := DualFromMatrix(m); !errors.Is(err, ErrNotRigidTransform) {
+			t.Errorf("%s: expected ErrNotRigidTransform, got %v", name, err)
+		}
+	}
+}
+
+// TestScLERPEndpoints prueba que t = 0 y t = 1 dan los extremos
+func TestScLERPEndpoints(t *testing.T) {
+	transforms := rigidTransformsForTest()
+	for _, a := range transforms {
+		for _, b := range transforms {
+			p := [3]float64{1, -1, 0.5}
+			if r := ScLERP(a, b, 0).TransformPoint(p); !vectorsClose(r, a.TransformPoint(p), 1e-12) {
+				t.Errorf("t = 0: expected %v, got %v", a.TransformPoint(p), r)
+			}
+			if r := ScLERP(a, b, 1).TransformPoint(p); !vectorsClose(r, b.TransformPoint(p), 1e-12) {
+				t.Errorf("t = 1: expected %v, got %v", b.TransformPoint(p), r)
+			}
+		}
+	}
+}
+
+// TestScLERPScrewMotion prueba que un giro con avance sobre el mismo eje se
+// interpola como una hélice
+func TestScLERPScrewMotion(t *testing.T) {
+	z := [3]float64{0, 0, 1}
+	end := NewRigidTransform(FromAxisAngle(z, math.Pi), [3]float64{0, 0, 4})
+	for _, tt := range []float64{0.25, 0.5, 0.75} {
+		r := ScLERP(DualIdentity(), end, tt)
+		expected := NewRigidTransform(FromAxisAngle(z, tt*math.Pi), [3]float64{0, 0, 4 * tt})
+		if !r.Equals(expected) {
+			t.Errorf("t = %g: expected %s, got %s", tt, expected, r)
+		}
+	}
+
+	// Un giro de media vuelta alrededor de una recta que no pasa por el
+	// origen lleva el punto sobre un arco, no en línea recta
+	end = NewRigidTransform(FromAxisAngle(z, math.Pi), [3]float64{2, 0, 0})
+	mid := ScLERP(DualIdentity(), end, 0.5).TransformPoint([3]float64{0, 0, 0})
+	if expected := [3]float64{1, -1, 0}; !vectorsClose(mid, expected, 1e-12) {
+		t.Errorf("Expected %v, got %v", expected, mid)
+	}
+}
+
+// TestScLERPPureTranslation prueba la rama sin rotación y su continuidad con
+// giros muy pequeños
+func TestScLERPPureTranslation(t *testing.T) {
+	a := DualFromTranslation([3]float64{1, 2, 3})
+	b := DualFromTranslation([3]float64{5, -2, 3})
+	if r := ScLERP(a, b, 0.25).Translation(); !vectorsClose(r, [3]float64{2, 1, 3}, 1e-12) {
+		t.Errorf("Expected [2 1 3], got %v", r)
+	}
+
+	for _, angle := range []float64{1e-12, 1e-9, 1e-7, 1e-5} {
+		c := NewRigidTransform(FromAxisAngle([3]float64{0, 1, 0}, angle), [3]float64{5, -2, 3})
+		r := ScLERP(a, c, 0.25).Translation()
+		if !vectorsClose(r, [3]float64{2, 1, 3}, 1e-4) {
+			t.Errorf("Angle %g: expected about [2 1 3], got %v", angle, r)
+		}
+	}
+}
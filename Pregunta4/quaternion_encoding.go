@@ -0,0 +1,311 @@
+package quaternion
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Formato, lectura y codificación de cuaterniones. La forma de texto es la
+// notación usual a + bi + cj + dk, con el signo de cada parte imaginaria
+// como operador: 1 - 2.5i + 3j + 0k. El texto que escriben MarshalText y
+// %g usa la menor cantidad de dígitos que recupera exactamente cada
+// componente, así que ParseQuaternion lo lee sin pérdidas.
+
+// binarySize es el largo de la codificación binaria: cuatro float64
+const binarySize = 32
+
+// ErrInvalidEncoding indica que los datos a decodificar no representan un
+// cuaternión
+var ErrInvalidEncoding = errors.New("quaternion: codificación inválida")
+
+// format escribe el cuaternión con el formato y la precisión de
+// strconv.FormatFloat. Con plus la parte real lleva siempre signo.
+func (q Quaternion) format(verb byte, prec int, plus bool) string {
+	buf := make([]byte, 0, 64)
+	if plus && !math.Signbit(q.A) && !math.IsNaN(q.A) {
+		buf = append(buf, '+')
+	}
+	buf = strconv.AppendFloat(buf, q.A, verb, prec, 64)
+	for i, v := range [3]float64{q.B, q.C, q.D} {
+		if math.Signbit(v) {
+			buf = append(buf, " - "...)
+		} else {
+			buf = append(buf, " + "...)
+		}
+		if math.IsInf(v, 0) {
+			// El signo ya está escrito; strconv escribiría +Inf
+			buf = append(buf, "Inf"...)
+		} else {
+			buf = strconv.AppendFloat(buf, math.Abs(v), verb, prec, 64)
+		}
+		buf = append(buf, "ijk"[i])
+	}
+	return string(buf)
+}
+
+// Format implementa fmt.Formatter:
+//
+//	%v, %s          como String, con dos decimales
+//	%+v             como %+.2f
+//	%.3v            tres cifras significativas, como %.3g
+//	%e %f %g %E %G  cada componente con ese verbo y su precisión; %g sin
+//	                precisión no pierde dígitos
+//	%#v             la sintaxis de Go del valor
+//
+// La bandera + fuerza el signo de la parte real y el ancho se aplica al
+// texto completo, alineado a la derecha o a la izquierda con la bandera -.
+func (q Quaternion) Format(f fmt.State, verb rune) {
+	var text string
+	switch verb {
+	case 'v', 's':
+		if verb == 'v' && f.Flag('#') {
+			text = fmt.Sprintf("quaternion.Quaternion{A:%#v, B:%#v, C:%#v, D:%#v}", q.A, q.B, q.C, q.D)
+		} else if prec, ok := f.Precision(); ok {
+			text = q.format('g', prec, f.Flag('+'))
+		} else if f.Flag('+') {
+			text = q.format('f', 2, true)
+		} else {
+			text = q.String()
+		}
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		prec, ok := f.Precision()
+		if !ok {
+			prec = 6
+			if verb == 'g' || verb == 'G' {
+				prec = -1
+			}
+		}
+		if verb == 'F' {
+			verb = 'f'
+		}
+		text = q.format(byte(verb), prec, f.Flag('+'))
+	default:
+		fmt.Fprintf(f, "%%!%c(quaternion.Quaternion=%s)", verb, q.String())
+		return
+	}
+
+	padding := ""
+	if width, ok := f.Width(); ok {
+		if n := width - utf8.RuneCountInString(text); n > 0 {
+			padding = strings.Repeat(" ", n)
+		}
+	}
+	if f.Flag('-') {
+		fmt.Fprint(f, text, padding)
+	} else {
+		fmt.Fprint(f, padding, text)
+	}
+}
+
+// ParseQuaternion lee un cuaternión escrito como suma de términos, por
+// ejemplo "1 - 2.5i + 3j + 0k", "-k", "2i + 1" o el formato de String
+// "1.00 + -2.00i + 3.00j + 4.00k". Cada parte puede aparecer una sola vez y
+// en cualquier orden; las que faltan valen cero. Acepta Inf y NaN como
+// strconv.ParseFloat. Los errores son de tipo *SyntaxError.
+func ParseQuaternion(s string) (Quaternion, error) {
+	var parts [4]float64
+	var seen [4]bool
+
+	pos := skipSpaces(s, 0)
+	if pos == len(s) {
+		return Quaternion{}, &SyntaxError{Pos: pos, Msg: "cuaternión vacío"}
+	}
+	for first := true; pos < len(s); first = false {
+		start := pos
+
+		// Signos: entre términos hace falta uno y se admite un segundo, como
+		// en "+ -2.00i"
+		negative, signs := false, 0
+		for pos < len(s) && (s[pos] == '+' || s[pos] == '-') {
+			if signs == 2 {
+				return Quaternion{}, &SyntaxError{Pos: pos, Msg: "demasiados signos seguidos"}
+			}
+			negative = negative != (s[pos] == '-')
+			signs++
+			pos = skipSpaces(s, pos+1)
+		}
+		if !first && signs == 0 {
+			return Quaternion{}, &SyntaxError{Pos: pos, Msg: "se esperaba + o -"}
+		}
+
+		value, hasNumber := 1.0, false
+		if end := specialEnd(s, pos); end > pos {
+			value, _ = strconv.ParseFloat(s[pos:end], 64)
+			hasNumber, pos = true, end
+		} else if end := numberEnd(s, pos); end > pos {
+			v, err := strconv.ParseFloat(s[pos:end], 64)
+			if err != nil && !errors.Is(err, strconv.ErrRange) {
+				return Quaternion{}, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("número inválido '%s'", s[pos:end])}
+			}
+			value, hasNumber, pos = v, true, end
+		}
+
+		part := 0
+		if pos < len(s) && strings.IndexByte("ijk", s[pos]) >= 0 {
+			part = 1 + strings.IndexByte("ijk", s[pos])
+			pos++
+		} else if !hasNumber {
+			return Quaternion{}, &SyntaxError{Pos: pos, Msg: "se esperaba un número o i, j, k"}
+		}
+		if pos < len(s) && !isSpace(s[pos]) && s[pos] != '+' && s[pos] != '-' {
+			return Quaternion{}, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("carácter inesperado %q", s[pos])}
+		}
+		if seen[part] {
+			return Quaternion{}, &SyntaxError{Pos: start, Msg: fmt.Sprintf("la parte %s aparece dos veces", [4]string{"real", "i", "j", "k"}[part])}
+		}
+
+		if negative {
+			value = -value
+		}
+		parts[part], seen[part] = value, true
+		pos = skipSpaces(s, pos)
+	}
+	return Quaternion{A: parts[0], B: parts[1], C: parts[2], D: parts[3]}, nil
+}
+
+// specialEnd devuelve el final de Inf, Infinity o NaN si empiezan en pos
+func specialEnd(s string, pos int) int {
+	rest := strings.ToLower(s[pos:])
+	for _, word := range []string{"infinity", "inf", "nan"} {
+		if strings.HasPrefix(rest, word) {
+			return pos + len(word)
+		}
+	}
+	return pos
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// skipSpaces avanza pos sobre los espacios
+func skipSpaces(s string, pos int) int {
+	for pos < len(s) && isSpace(s[pos]) {
+		pos++
+	}
+	return pos
+}
+
+// MarshalText implementa encoding.TextMarshaler con el formato de %g, que
+// no pierde dígitos
+func (q Quaternion) MarshalText() ([]byte, error) {
+	return []byte(q.format('g', -1, false)), nil
+}
+
+// UnmarshalText implementa encoding.TextUnmarshaler con ParseQuaternion
+func (q *Quaternion) UnmarshalText(text []byte) error {
+	p, err := ParseQuaternion(string(text))
+	if err != nil {
+		return err
+	}
+	*q = p
+	return nil
+}
+
+// quaternionObject es la forma de objeto JSON; al convertir desde y hacia
+// Quaternion solo cambian las etiquetas
+type quaternionObject struct {
+	A float64 `json:"a"`
+	B float64 `json:"b"`
+	C float64 `json:"c"`
+	D float64 `json:"d"`
+}
+
+// MarshalJSON implementa json.Marshaler con la forma de arreglo
+// [a, b, c, d]. Como JSON no admite Inf ni NaN, con esos valores devuelve
+// un error.
+func (q Quaternion) MarshalJSON() ([]byte, error) {
+	return json.Marshal([4]float64{q.A, q.B, q.C, q.D})
+}
+
+// UnmarshalJSON implementa json.Unmarshaler. Acepta el arreglo
+// [a, b, c, d], el objeto {"a": …, "b": …, "c": …, "d": …}, en el que las
+// partes que faltan valen cero, y el texto de MarshalText entre comillas.
+// null deja el cuaternión sin cambios.
+func (q *Quaternion) UnmarshalJSON(data []byte) error {
+	text := strings.TrimSpace(string(data))
+	if text == "" {
+		return ErrInvalidEncoding
+	}
+	switch text[0] {
+	case 'n':
+		if text != "null" {
+			return ErrInvalidEncoding
+		}
+		return nil
+	case '[':
+		var parts []float64
+		if err := json.Unmarshal(data, &parts); err != nil {
+			return err
+		}
+		if len(parts) != 4 {
+			return fmt.Errorf("%w: el arreglo tiene %d elementos en lugar de 4", ErrInvalidEncoding, len(parts))
+		}
+		*q = Quaternion{A: parts[0], B: parts[1], C: parts[2], D: parts[3]}
+		return nil
+	case '{':
+		var object quaternionObject
+		if err := json.Unmarshal(data, &object); err != nil {
+			return err
+		}
+		*q = Quaternion(object)
+		return nil
+	case '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return q.UnmarshalText([]byte(s))
+	}
+	return ErrInvalidEncoding
+}
+
+// JSONObject es un Quaternion que se codifica en JSON como objeto
+// {"a": …, "b": …, "c": …, "d": …}. Sirve como tipo de un campo, o con la
+// conversión JSONObject(q), cuando el formato de destino pide objetos.
+type JSONObject Quaternion
+
+// MarshalJSON implementa json.Marshaler con la forma de objeto
+func (o JSONObject) MarshalJSON() ([]byte, error) {
+	return json.Marshal(quaternionObject(o))
+}
+
+// UnmarshalJSON acepta las mismas formas que Quaternion.UnmarshalJSON
+func (o *JSONObject) UnmarshalJSON(data []byte) error {
+	return (*Quaternion)(o).UnmarshalJSON(data)
+}
+
+// AppendBinary agrega a b la codificación binaria del cuaternión: los
+// cuatro componentes como float64 IEEE 754 en little endian, 32 bytes en
+// total. Conserva exactamente todos los valores, incluidos -0, Inf y NaN.
+func (q Quaternion) AppendBinary(b []byte) ([]byte, error) {
+	for _, v := range [4]float64{q.A, q.B, q.C, q.D} {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+	}
+	return b, nil
+}
+
+// MarshalBinary implementa encoding.BinaryMarshaler (ver AppendBinary)
+func (q Quaternion) MarshalBinary() ([]byte, error) {
+	return q.AppendBinary(make([]byte, 0, binarySize))
+}
+
+// UnmarshalBinary implementa encoding.BinaryUnmarshaler. Los datos deben
+// tener exactamente 32 bytes.
+func (q *Quaternion) UnmarshalBinary(data []byte) error {
+	if len(data) != binarySize {
+		return fmt.Errorf("%w: se esperaban %d bytes, hay %d", ErrInvalidEncoding, binarySize, len(data))
+	}
+	var parts [4]float64
+	for i := range parts {
+		parts[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8*i:]))
+	}
+	*q = Quaternion{A: parts[0], B: parts[1], C: parts[2], D: parts[3]}
+	return nil
+}
@@ -0,0 +1,185 @@
+package quaternion
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// TestFormatVerbs prueba los verbos, la precisión, las banderas y el ancho
+func TestFormatVerbs(t *testing.T) {
+	q := New(1, -2.5, 1.0/3, 0)
+	cases := []struct {
+		format, expected string
+	}{
+		{"%v", "1.00 + -2.50i + 0.33j + 0.00k"},
+		{"%s", "1.00 + -2.50i + 0.33j + 0.00k"},
+		{"%.3v", "1 - 2.5i + 0.333j + 0k"},
+		{"%g", "1 - 2.5i + 0.3333333333333333j + 0k"},
+		{"%.1f", "1.0 - 2.5i + 0.3j + 0.0k"},
+		{"%f", "1.000000 - 2.500000i + 0.333333j + 0.000000k"},
+		{"%.2e", "1.00e+00 - 2.50e+00i + 3.33e-01j + 0.00e+00k"},
+		{"%+v", "+1.00 - 2.50i + 0.33j + 0.00k"},
+		{"%+.1f", "+1.0 - 2.5i + 0.3j + 0.0k"},
+		{"%30.1f", "      1.0 - 2.5i + 0.3j + 0.0k"},
+		{"%-30.1f|", "1.0 - 2.5i + 0.3j + 0.0k      |"},
+		{"%#v", "quaternion.Quaternion{A:1, B:-2.5, C:0.3333333333333333, D:0}"},
+		{"%d", "%!d(quaternion.Quaternion=1.00 + -2.50i + 0.33j + 0.00k)"},
+	}
+	for _, c := range cases {
+		if s := fmt.Sprintf(c.format, q); s != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.format, c.expected, s)
+		}
+	}
+	if s := fmt.Sprintf("%g", New(0, math.Copysign(0, -1), math.Inf(-1), math.NaN())); s != "0 - 0i - Infj + NaNk" {
+		t.Errorf("Expected 0 - 0i - Infj + NaNk, got %q", s)
+	}
+}
+
+// TestParseQuaternion prueba las formas aceptadas
+func TestParseQuaternion(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected Quaternion
+	}{
+		{"1 - 2.5i + 3j + 0k", New(1, -2.5, 3, 0)},
+		{"1.00 + -2.00i + 3.00j + 4.00k", New(1, -2, 3, 4)},
+		{"-k", New(0, 0, 0, -1)},
+		{"  2i+1  ", New(1, 2, 0, 0)},
+		{"i - j", New(0, 1, -1, 0)},
+		{"-3.5e-3", New(-3.5e-3, 0, 0, 0)},
+		{"1e+21i - 4E2k", New(0, 1e21, 0, -400)},
+		{"-Inf + infj", New(math.Inf(-1), 0, math.Inf(1), 0)},
+	}
+	for _, c := range cases {
+		q, err := ParseQuaternion(c.text)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", c.text, err)
+		} else if q != c.expected {
+			t.Errorf("%q: expected %s, got %s", c.text, c.expected, q)
+		}
+	}
+
+	if q, err := ParseQuaternion("NaN - 0i"); err != nil || !math.IsNaN(q.A) || !math.Signbit(q.B) {
+		t.Errorf("Expected NaN - 0i, got %v (%v)", q, err)
+	}
+}
+
+// TestParseQuaternionErrors prueba que los textos mal formados dan un
+// *SyntaxError con la posición del problema
+func TestParseQuaternionErrors(t *testing.T) {
+	cases := []struct {
+		text string
+		pos  int
+	}{
+		{"", 0},
+		{"   ", 3},
+		{"1 2i", 2},
+		{"1 + 2i + 3i", 7},
+		{"1 + - - 2", 6},
+		{"1 + ", 4},
+		{"2x", 1},
+		{"1 * i", 2},
+		{".i", 0},
+	}
+	for _, c := range cases {
+		_, err := ParseQuaternion(c.text)
+		var syntax *SyntaxError
+		if !errors.As(err, &syntax) {
+			t.Errorf("%q: expected a *SyntaxError, got %v", c.text, err)
+		} else if syntax.Pos != c.pos {
+			t.Errorf("%q: expected the error at %d, got %v", c.text, c.pos, err)
+		}
+	}
+}
+
+// TestTextRoundTrip prueba que MarshalText no pierde dígitos
+func TestTextRoundTrip(t *testing.T) {
+	qs := append(randomQuaternions(50, 1e3), New(math.Copysign(0, -1), 1e-310, -math.MaxFloat64, 0.1))
+	for _, q := range qs {
+		text, _ := q.MarshalText()
+		var p Quaternion
+		if err := p.UnmarshalText(text); err != nil {
+			t.Fatalf("%s: unexpected error: %v", text, err)
+		}
+		if p != q || math.Signbit(p.A) != math.Signbit(q.A) {
+			t.Fatalf("Expected %#v, got %#v from %s", q, p, text)
+		}
+	}
+}
+
+// TestJSON prueba las formas de arreglo, objeto y texto
+func TestJSON(t *testing.T) {
+	q := New(1, -2.5, 3, 0.125)
+	type frame struct {
+		Pose  Quaternion  `json:"pose"`
+		Other JSONObject  `json:"other"`
+		Opt   *Quaternion `json:"opt,omitempty"`
+	}
+	data, err := json.Marshal(frame{Pose: q, Other: JSONObject(q)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := `{"pose":[1,-2.5,3,0.125],"other":{"a":1,"b":-2.5,"c":3,"d":0.125}}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+
+	var back frame
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if back.Pose != q || Quaternion(back.Other) != q || back.Opt != nil {
+		t.Errorf("Expected %s twice, got %+v", q, back)
+	}
+
+	inputs := []string{`[1, -2.5, 3, 0.125]`, `{"a": 1, "b": -2.5, "c": 3, "d": 0.125}`, `{"A": 1, "B": -2.5, "C": 3, "D": 0.125}`, `"1 - 2.5i + 3j + 0.125k"`}
+	for _, in := range inputs {
+		var p Quaternion
+		if err := json.Unmarshal([]byte(in), &p); err != nil || p != q {
+			t.Errorf("%s: expected %s, got %s (%v)", in, q, p, err)
+		}
+	}
+
+	var p Quaternion
+	if err := json.Unmarshal([]byte(`{"b": 2}`), &p); err != nil || p != New(0, 2, 0, 0) {
+		t.Errorf("Expected missing parts to be zero, got %s (%v)", p, err)
+	}
+	for _, in := range []string{`[1, 2, 3]`, `true`, `"1 + x"`} {
+		if err := json.Unmarshal([]byte(in), &p); err == nil {
+			t.Errorf("%s: expected an error", in)
+		}
+	}
+	if _, err := json.Marshal(New(math.NaN(), 0, 0, 0)); err == nil {
+		t.Errorf("Expected an error encoding NaN")
+	}
+}
+
+// TestBinaryRoundTrip prueba la codificación binaria, exacta bit a bit
+func TestBinaryRoundTrip(t *testing.T) {
+	q := New(math.Copysign(0, -1), math.Inf(1), math.NaN(), 1.0/3)
+	data, err := q.MarshalBinary()
+	if err != nil || len(data) != 32 {
+		t.Fatalf("Expected 32 bytes, got %d (%v)", len(data), err)
+	}
+	var p Quaternion
+	if err := p.UnmarshalBinary(data); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i, pair := range [][2]float64{{q.A, p.A}, {q.B, p.B}, {q.C, p.C}, {q.D, p.D}} {
+		if math.Float64bits(pair[0]) != math.Float64bits(pair[1]) {
+			t.Errorf("Component %d: expected bits %x, got %x", i, math.Float64bits(pair[0]), math.Float64bits(pair[1]))
+		}
+	}
+
+	// Varios cuaterniones seguidos en un mismo buffer
+	buf, _ := New(1, 2, 3, 4).AppendBinary(data)
+	if err := p.UnmarshalBinary(buf[32:]); err != nil || p != New(1, 2, 3, 4) {
+		t.Errorf("Expected 1 + 2i + 3j + 4k, got %s (%v)", p, err)
+	}
+	if err := p.UnmarshalBinary(buf); !errors.Is(err, ErrInvalidEncoding) {
+		t.Errorf("Expected ErrInvalidEncoding, got %v", err)
+	}
+}
@@ -0,0 +1,409 @@
+package quaternion
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"unicode"
+)
+
+// Lenguaje de expresiones con la notación del curso:
+//
+//	q1 + q2, q1 - q2     suma y resta
+//	q1 * q2, q1 / q2     producto y división por la derecha (q1 * q2⁻¹)
+//	~q                   conjugada
+//	&q                   medida o valor absoluto (un real)
+//	-q                   opuesto
+//	1+2i+3j+4k           literales; i, j y k son las unidades imaginarias
+//	q1 = expresión       asignación (solo en Calculator)
+//
+// Los reales son cuaterniones con parte imaginaria cero, así que se mezclan
+// libremente con los cuaterniones: (q1 + q1) * &q3 o q1 * 3.0 + 7.0. Los
+// operadores prefijos ligan más que * y /, que ligan más que + y -.
+
+// ErrUndefinedVariable indica que una expresión usa una variable sin valor
+var ErrUndefinedVariable = errors.New("quaternion: variable no definida")
+
+// SyntaxError describe un error de sintaxis en una expresión
+type SyntaxError struct {
+	Pos int    // posición en bytes dentro de la expresión
+	Msg string // descripción del error
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("quaternion: error de sintaxis en la posición %d: %s", e.Pos, e.Msg)
+}
+
+// Expression es una expresión ya analizada que puede evaluarse muchas veces
+// con distintos valores de las variables
+type Expression interface {
+	Eval(vars map[string]Quaternion) (Quaternion, error)
+}
+
+// units son las unidades imaginarias, nombres reservados del lenguaje
+var units = map[string]Quaternion{
+	"i": {B: 1},
+	"j": {C: 1},
+	"k": {D: 1},
+}
+
+type literalNode struct {
+	value Quaternion
+}
+
+func (n literalNode) Eval(map[string]Quaternion) (Quaternion, error) {
+	return n.value, nil
+}
+
+type variableNode struct {
+	name string
+}
+
+func (n variableNode) Eval(vars map[string]Quaternion) (Quaternion, error) {
+	value, ok := vars[n.name]
+	if !ok {
+		return Quaternion{}, fmt.Errorf("%w: '%s'", ErrUndefinedVariable, n.name)
+	}
+	return value, nil
+}
+
+type unaryNode struct {
+	op      byte
+	operand Expression
+}
+
+func (n unaryNode) Eval(vars map[string]Quaternion) (Quaternion, error) {
+	q, err := n.operand.Eval(vars)
+	if err != nil {
+		return Quaternion{}, err
+	}
+	switch n.op {
+	case '~':
+		return q.Conjugate(), nil
+	case '&':
+		return FromReal(q.Abs()), nil
+	default:
+		return q.Negate(), nil
+	}
+}
+
+type binaryNode struct {
+	op          byte
+	left, right Expression
+}
+
+func (n binaryNode) Eval(vars map[string]Quaternion) (Quaternion, error) {
+	left, err := n.left.Eval(vars)
+	if err != nil {
+		return Quaternion{}, err
+	}
+	right, err := n.right.Eval(vars)
+	if err != nil {
+		return Quaternion{}, err
+	}
+	switch n.op {
+	case '+':
+		return left.Add(right), nil
+	case '-':
+		return left.Subtract(right), nil
+	case '*':
+		return left.Multiply(right), nil
+	default:
+		return left.RightDivide(right)
+	}
+}
+
+// token es una pieza léxica: un operador (kind es el carácter), un número
+// ('0'), un identificador ('a') o el fin de la entrada (0)
+type token struct {
+	kind  byte
+	pos   int
+	text  string
+	value Quaternion
+}
+
+// tokenize separa la expresión en tokens
+func tokenize(src string) ([]token, error) {
+	var tokens []token
+	for pos := 0; pos < len(src); {
+		c := src[pos]
+		switch {
+		case c == ' ' || c == '\t':
+			pos++
+		case isDigit(c) || c == '.':
+			tok, end, err := scanNumber(src, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			pos = end
+		case isIdentStart(c):
+			end := pos + 1
+			for end < len(src) && isIdentPart(src[end]) {
+				end++
+			}
+			tokens = append(tokens, token{kind: 'a', pos: pos, text: src[pos:end]})
+			pos = end
+		case c == '+' || c == '-' || c == '*' || c == '/' || c == '~' || c == '&' ||
+			c == '(' || c == ')' || c == '=':
+			tokens = append(tokens, token{kind: c, pos: pos, text: string(c)})
+			pos++
+		default:
+			return nil, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("carácter inesperado %q", rune(c))}
+		}
+	}
+	return append(tokens, token{pos: len(src)}), nil
+}
+
+// numberEnd devuelve el final del número sin signo que empieza en pos
+func numberEnd(src string, pos int) int {
+	end := pos
+	for end < len(src) && (isDigit(src[end]) || src[end] == '.') {
+		end++
+	}
+	// Exponente, solo si la e va seguida de dígitos
+	if end < len(src) && (src[end] == 'e' || src[end] == 'E') {
+		exp := end + 1
+		if exp < len(src) && (src[exp] == '+' || src[exp] == '-') {
+			exp++
+		}
+		if exp < len(src) && isDigit(src[exp]) {
+			for exp < len(src) && isDigit(src[exp]) {
+				exp++
+			}
+			end = exp
+		}
+	}
+	return end
+}
+
+// scanNumber lee un número desde pos. Si va seguido de i, j o k es la
+// parte imaginaria correspondiente: 2.5i es el cuaternión 0 + 2.5i.
+func scanNumber(src string, pos int) (token, int, error) {
+	end := numberEnd(src, pos)
+	value, err := strconv.ParseFloat(src[pos:end], 64)
+	if err != nil {
+		return token{}, 0, &SyntaxError{Pos: pos, Msg: fmt.Sprintf("número inválido '%s'", src[pos:end])}
+	}
+	tok := token{kind: '0', pos: pos, text: src[pos:end], value: FromReal(value)}
+
+	if end < len(src) && isIdentStart(src[end]) {
+		suffix := end + 1
+		for suffix < len(src) && isIdentPart(src[suffix]) {
+			suffix++
+		}
+		unit, ok := units[src[end:suffix]]
+		if !ok {
+			return token{}, 0, &SyntaxError{Pos: end, Msg: fmt.Sprintf("sufijo inválido '%s' (se esperaba i, j o k)", src[end:suffix])}
+		}
+		tok.value = unit.MultiplyReal(value)
+		tok.text = src[pos:suffix]
+		end = suffix
+	}
+	return tok, end, nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c < 0x80 && unicode.IsLetter(rune(c))
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
+
+// parser es un analizador descendente recursivo con un token de anticipación
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != 0 {
+		p.pos++
+	}
+	return tok
+}
+
+// parseExpression: term (('+' | '-') term)*
+func (p *parser) parseExpression() (Expression, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == '+' || p.peek().kind == '-' {
+		op := p.next().kind
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseTerm: unary (('*' | '/') unary)*
+func (p *parser) parseTerm() (Expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == '*' || p.peek().kind == '/' {
+		op := p.next().kind
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+// parseUnary: ('-' | '~' | '&') unary | primary
+func (p *parser) parseUnary() (Expression, error) {
+	switch p.peek().kind {
+	case '-', '~', '&':
+		op := p.next().kind
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: op, operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary: número | unidad | variable | '(' expression ')'
+func (p *parser) parsePrimary() (Expression, error) {
+	tok := p.next()
+	switch tok.kind {
+	case '0':
+		return literalNode{value: tok.value}, nil
+	case 'a':
+		if unit, ok := units[tok.text]; ok {
+			return literalNode{value: unit}, nil
+		}
+		return variableNode{name: tok.text}, nil
+	case '(':
+		inner, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != ')' {
+			return nil, &SyntaxError{Pos: closing.pos, Msg: "se esperaba ')'"}
+		}
+		return inner, nil
+	case 0:
+		return nil, &SyntaxError{Pos: tok.pos, Msg: "expresión incompleta"}
+	default:
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("token inesperado '%s'", tok.text)}
+	}
+}
+
+// expectEnd verifica que no queden tokens sin consumir
+func (p *parser) expectEnd() error {
+	if tok := p.peek(); tok.kind != 0 {
+		return &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("token inesperado '%s'", tok.text)}
+	}
+	return nil
+}
+
+// ParseExpression analiza una expresión. Devuelve *SyntaxError si está mal
+// formada; las variables no definidas recién se detectan al evaluar.
+func ParseExpression(src string) (Expression, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	expr, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectEnd(); err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+// Evaluate analiza y evalúa una expresión con las variables dadas
+func Evaluate(src string, vars map[string]Quaternion) (Quaternion, error) {
+	expr, err := ParseExpression(src)
+	if err != nil {
+		return Quaternion{}, err
+	}
+	return expr.Eval(vars)
+}
+
+// Calculator evalúa una sucesión de líneas que pueden asignar variables.
+// El resultado de la última expresión queda en la variable ans.
+type Calculator struct {
+	vars map[string]Quaternion
+}
+
+// NewCalculator crea una calculadora sin variables definidas
+func NewCalculator() *Calculator {
+	return &Calculator{vars: make(map[string]Quaternion)}
+}
+
+// Set asigna el valor de una variable
+func (c *Calculator) Set(name string, value Quaternion) {
+	c.vars[name] = value
+}
+
+// Get devuelve el valor de una variable y si está definida
+func (c *Calculator) Get(name string) (Quaternion, bool) {
+	value, ok := c.vars[name]
+	return value, ok
+}
+
+// Execute evalúa una línea, que puede ser una expresión o una asignación
+// nombre = expresión, y devuelve el valor resultante
+func (c *Calculator) Execute(line string) (Quaternion, error) {
+	tokens, err := tokenize(line)
+	if err != nil {
+		return Quaternion{}, err
+	}
+
+	name := ""
+	if len(tokens) > 2 && tokens[0].kind == 'a' && tokens[1].kind == '=' {
+		name = tokens[0].text
+		if _, reserved := units[name]; reserved {
+			return Quaternion{}, &SyntaxError{Pos: tokens[0].pos, Msg: fmt.Sprintf("'%s' es una unidad imaginaria y no se puede asignar", name)}
+		}
+		tokens = tokens[2:]
+	}
+
+	p := &parser{tokens: tokens}
+	expr, err := p.parseExpression()
+	if err != nil {
+		return Quaternion{}, err
+	}
+	if err := p.expectEnd(); err != nil {
+		return Quaternion{}, err
+	}
+	value, err := expr.Eval(c.vars)
+	if err != nil {
+		return Quaternion{}, err
+	}
+
+	if name != "" {
+		c.vars[name] = value
+	}
+	c.vars["ans"] = value
+	return value, nil
+}
+
+// IsReal indica si el cuaternión no tiene parte imaginaria, por ejemplo el
+// resultado de &q
+func (q Quaternion) IsReal() bool {
+	return q.B == 0 && q.C == 0 && q.D == 0
+}
@@ -0,0 +1,164 @@
+package quaternion
+
+import (
+	"errors"
+	"testing"
+)
+
+// exampleVars son los cuaterniones de quaternion_example.go
+func exampleVars() map[string]Quaternion {
+	return map[string]Quaternion{
+		"q1": New(1, 2, 3, 4),
+		"q2": New(2, 3, 4, 5),
+		"q3": New(1, 0, 1, 0),
+	}
+}
+
+// TestEvaluateExampleExpressions prueba las expresiones del programa de ejemplo
+func TestEvaluateExampleExpressions(t *testing.T) {
+	vars := exampleVars()
+	q1, q2, q3 := vars["q1"], vars["q2"], vars["q3"]
+
+	tests := []struct {
+		src      string
+		expected Quaternion
+	}{
+		{"q1 + q2", q1.Add(q2)},
+		{"~q1", q1.Conjugate()},
+		{"q1 * q2", q1.Multiply(q2)},
+		{"&q1", FromReal(q1.Abs())},
+		{"(q1 + q2) * q3", q1.Add(q2).Multiply(q3)},
+		{"(q1 + q1) * (q3 + ~q2)", q1.Add(q1).Multiply(q3.Add(q2.Conjugate()))},
+		{"&(q3 * q1)", FromReal(q3.Multiply(q1).Abs())},
+		{"q1 + 3", q1.AddReal(3)},
+		{"q2 * 2.5", q2.MultiplyReal(2.5)},
+		{"q1 * 3.0 + 7.0", q1.MultiplyReal(3).AddReal(7)},
+		{"(q1 + q1) * &q3", q1.Add(q1).MultiplyReal(q3.Abs())},
+	}
+	for _, tt := range tests {
+		result, err := Evaluate(tt.src, vars)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.src, err)
+			continue
+		}
+		if !result.Equals(tt.expected) {
+			t.Errorf("%s: expected %s, got %s", tt.src, tt.expected, result)
+		}
+	}
+}
+
+// TestEvaluateLiteralsAndPrecedence prueba los literales y la precedencia
+func TestEvaluateLiteralsAndPrecedence(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected Quaternion
+	}{
+		{"1+2i+3j+4k", New(1, 2, 3, 4)},
+		{"1 - 2.5i + 3j + 0k", New(1, -2.5, 3, 0)},
+		{"1e2 + 1.5e-1k", New(100, 0, 0, 0.15)},
+		{"i * j", New(0, 0, 0, 1)},
+		{"j * i", New(0, 0, 0, -1)},
+		{"i*i", FromReal(-1)},
+		{"1 + 2 * 3", FromReal(7)},
+		{"(1 + 2) * 3", FromReal(9)},
+		{"2 - 3 - 4", FromReal(-5)},
+		{"-i * j", New(0, 0, 0, -1)},
+		{"~~(1+2i)", New(1, 2, 0, 0)},
+		{"&3j + 1", FromReal(4)},
+		{"k / j", New(0, 1, 0, 0)},
+		{"6 / 4", FromReal(1.5)},
+	}
+	for _, tt := range tests {
+		result, err := Evaluate(tt.src, nil)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.src, err)
+			continue
+		}
+		if !result.Equals(tt.expected) {
+			t.Errorf("%s: expected %s, got %s", tt.src, tt.expected, result)
+		}
+	}
+}
+
+// TestParseExpressionSyntaxErrors prueba los errores de sintaxis y su posición
+func TestParseExpressionSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		pos int
+	}{
+		{"", 0},
+		{"1 +", 3},
+		{"(1 + 2", 6},
+		{"1 2", 2},
+		{"q1 # q2", 3},
+		{"2x", 1},
+		{"1.2.3", 0},
+		{"* 2", 0},
+	}
+	for _, tt := range tests {
+		_, err := ParseExpression(tt.src)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("%q: expected a SyntaxError, got %v", tt.src, err)
+			continue
+		}
+		if syntaxErr.Pos != tt.pos {
+			t.Errorf("%q: expected position %d, got %d (%v)", tt.src, tt.pos, syntaxErr.Pos, err)
+		}
+	}
+}
+
+// TestEvaluateErrors prueba las variables no definidas y la división por cero
+func TestEvaluateErrors(t *testing.T) {
+	if _, err := Evaluate("q1 + q9", exampleVars()); !errors.Is(err, ErrUndefinedVariable) {
+		t.Errorf("Expected ErrUndefinedVariable, got %v", err)
+	}
+	if _, err := Evaluate("q1 / (q3 - q3)", exampleVars()); !errors.Is(err, ErrZeroNorm) {
+		t.Errorf("Expected ErrZeroNorm, got %v", err)
+	}
+}
+
+// TestCalculatorAssignments prueba las asignaciones y la variable ans
+func TestCalculatorAssignments(t *testing.T) {
+	c := NewCalculator()
+	if _, err := c.Execute("q1 = 1+2i+3j+4k"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := c.Execute("q2 = ~q1 * 2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if q2, _ := c.Get("q2"); !q2.Equals(New(2, -4, -6, -8)) {
+		t.Errorf("Expected (2 - 4i - 6j - 8k), got %s", q2)
+	}
+
+	result, err := c.Execute("&(q1 + q2)")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ans, _ := c.Get("ans"); !result.IsReal() || !ans.Equals(result) {
+		t.Errorf("Expected a real result stored in ans, got %s and %s", result, ans)
+	}
+
+	c.Set("x", FromReal(2))
+	if result, _ := c.Execute("ans * x"); !result.Equals(FromReal(2 * New(3, -2, -3, -4).Abs())) {
+		t.Errorf("Expected ans * x, got %s", result)
+	}
+}
+
+// TestCalculatorErrors prueba que los errores no modifican las variables
+func TestCalculatorErrors(t *testing.T) {
+	c := NewCalculator()
+	var syntaxErr *SyntaxError
+	if _, err := c.Execute("i = 2"); !errors.As(err, &syntaxErr) {
+		t.Errorf("Assigning to i: expected a SyntaxError, got %v", err)
+	}
+	if _, err := c.Execute("x ="); !errors.As(err, &syntaxErr) {
+		t.Errorf("Empty assignment: expected a SyntaxError, got %v", err)
+	}
+	if _, err := c.Execute("x = y"); !errors.Is(err, ErrUndefinedVariable) {
+		t.Errorf("Expected ErrUndefinedVariable, got %v", err)
+	}
+	if _, ok := c.Get("x"); ok {
+		t.Errorf("A failed assignment should not define the variable")
+	}
+}
@@ -0,0 +1,176 @@
+package quaternion
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Q es un cuaternión a + bi + cj + dk con coeficientes de cualquier tipo que
+// cumpla Scalar: F32 para la GPU, F64 para simulación, Rat para álgebra
+// exacta o BigFloat para precisión arbitraria. Solo ofrece las operaciones
+// que se pueden hacer con las cuatro operaciones básicas; la norma, las
+// rotaciones y las funciones trascendentes quedan en Quaternion.
+//
+// Se llama Q y no Quaternion[T] porque Go no admite un tipo genérico y otro
+// que no lo es con el mismo nombre en un paquete, y Quaternion ya es el
+// tipo float64 que usa el resto de la biblioteca. Quaternion tampoco puede
+// ser un alias de Q[F64]: no se pueden declarar métodos sobre una instancia
+// de un tipo genérico, y sus campos dejarían de ser float64. Por eso los dos
+// tipos son independientes y Quaternion conserva su aritmética float64
+// directa, que el compilador puede expandir en línea; una llamada genérica
+// pasa por un diccionario de métodos y es varias veces más lenta. Las
+// conversiones entre ambos (ToF64, FromF64) solo copian los cuatro campos.
+type Q[T Scalar[T]] struct {
+	A, B, C, D T
+}
+
+// NewQ crea un cuaternión genérico con los coeficientes dados
+func NewQ[T Scalar[T]](a, b, c, d T) Q[T] {
+	return Q[T]{A: a, B: b, C: c, D: d}
+}
+
+// QFromReal crea un cuaternión genérico a partir de un escalar
+func QFromReal[T Scalar[T]](a T) Q[T] {
+	return Q[T]{A: a}
+}
+
+// String devuelve una representación en string del cuaternión con la
+// representación exacta de cada coeficiente
+func (q Q[T]) String() string {
+	return fmt.Sprintf("%s + %si + %sj + %sk", q.A, q.B, q.C, q.D)
+}
+
+// Add suma dos cuaterniones
+func (q Q[T]) Add(other Q[T]) Q[T] {
+	return Q[T]{A: q.A.Add(other.A), B: q.B.Add(other.B), C: q.C.Add(other.C), D: q.D.Add(other.D)}
+}
+
+// Subtract resta dos cuaterniones
+func (q Q[T]) Subtract(other Q[T]) Q[T] {
+	return Q[T]{A: q.A.Sub(other.A), B: q.B.Sub(other.B), C: q.C.Sub(other.C), D: q.D.Sub(other.D)}
+}
+
+// AddReal suma un escalar a la parte real
+func (q Q[T]) AddReal(n T) Q[T] {
+	return Q[T]{A: q.A.Add(n), B: q.B, C: q.C, D: q.D}
+}
+
+// MultiplyReal multiplica cada coeficiente por un escalar
+func (q Q[T]) MultiplyReal(n T) Q[T] {
+	return Q[T]{A: q.A.Mul(n), B: q.B.Mul(n), C: q.C.Mul(n), D: q.D.Mul(n)}
+}
+
+// Conjugate calcula la conjugada a - bi - cj - dk
+func (q Q[T]) Conjugate() Q[T] {
+	return Q[T]{A: q.A, B: q.B.Neg(), C: q.C.Neg(), D: q.D.Neg()}
+}
+
+// Negate calcula el opuesto
+func (q Q[T]) Negate() Q[T] {
+	return Q[T]{A: q.A.Neg(), B: q.B.Neg(), C: q.C.Neg(), D: q.D.Neg()}
+}
+
+// Multiply calcula el producto de Hamilton, con la misma fórmula que
+// Quaternion.Multiply
+func (q Q[T]) Multiply(other Q[T]) Q[T] {
+	a1, b1, c1, d1 := q.A, q.B, q.C, q.D
+	a2, b2, c2, d2 := other.A, other.B, other.C, other.D
+	return Q[T]{
+		A: a1.Mul(a2).Sub(b1.Mul(b2)).Sub(c1.Mul(c2)).Sub(d1.Mul(d2)),
+		B: a1.Mul(b2).Add(b1.Mul(a2)).Add(c1.Mul(d2)).Sub(d1.Mul(c2)),
+		C: a1.Mul(c2).Sub(b1.Mul(d2)).Add(c1.Mul(a2)).Add(d1.Mul(b2)),
+		D: a1.Mul(d2).Add(b1.Mul(c2)).Sub(c1.Mul(b2)).Add(d1.Mul(a2)),
+	}
+}
+
+// Norm2 calcula el cuadrado de la norma a² + b² + c² + d², que a diferencia
+// de la norma es exacto con coeficientes racionales
+func (q Q[T]) Norm2() T {
+	return q.A.Mul(q.A).Add(q.B.Mul(q.B)).Add(q.C.Mul(q.C)).Add(q.D.Mul(q.D))
+}
+
+// Inverse calcula el inverso ~q / (a² + b² + c² + d²).
+// Devuelve ErrZeroNorm si el cuaternión es cero.
+func (q Q[T]) Inverse() (Q[T], error) {
+	n := q.Norm2()
+	if n.IsZero() {
+		return Q[T]{}, ErrZeroNorm
+	}
+	c := q.Conjugate()
+	return Q[T]{A: c.A.Quo(n), B: c.B.Quo(n), C: c.C.Quo(n), D: c.D.Quo(n)}, nil
+}
+
+// RightDivide divide por la derecha: q * other⁻¹
+func (q Q[T]) RightDivide(other Q[T]) (Q[T], error) {
+	inverse, err := other.Inverse()
+	if err != nil {
+		return Q[T]{}, err
+	}
+	return q.Multiply(inverse), nil
+}
+
+// LeftDivide divide por la izquierda: other⁻¹ * q
+func (q Q[T]) LeftDivide(other Q[T]) (Q[T], error) {
+	inverse, err := other.Inverse()
+	if err != nil {
+		return Q[T]{}, err
+	}
+	return inverse.Multiply(q), nil
+}
+
+// Equals compara los coeficientes exactamente. Para tipos de coma flotante
+// conviene convertir a Quaternion y usar Quaternion.Equals, que tiene
+// tolerancia.
+func (q Q[T]) Equals(other Q[T]) bool {
+	return q.A.Equal(other.A) && q.B.Equal(other.B) && q.C.Equal(other.C) && q.D.Equal(other.D)
+}
+
+// IsZero indica si todos los coeficientes son cero
+func (q Q[T]) IsZero() bool {
+	return q.A.IsZero() && q.B.IsZero() && q.C.IsZero() && q.D.IsZero()
+}
+
+// Map aplica f a cada coeficiente, por ejemplo para cambiar de tipo
+func Map[T Scalar[T], U Scalar[U]](q Q[T], f func(T) U) Q[U] {
+	return Q[U]{A: f(q.A), B: f(q.B), C: f(q.C), D: f(q.D)}
+}
+
+// ToF64 convierte un Quaternion en Q[F64]
+func ToF64(q Quaternion) Q[F64] {
+	return Q[F64]{A: F64(q.A), B: F64(q.B), C: F64(q.C), D: F64(q.D)}
+}
+
+// FromF64 convierte un Q[F64] en Quaternion
+func FromF64(q Q[F64]) Quaternion {
+	return Quaternion{A: float64(q.A), B: float64(q.B), C: float64(q.C), D: float64(q.D)}
+}
+
+// ToF32 convierte un Quaternion en Q[F32], redondeando cada coeficiente
+func ToF32(q Quaternion) Q[F32] {
+	return Q[F32]{A: F32(q.A), B: F32(q.B), C: F32(q.C), D: F32(q.D)}
+}
+
+// FromF32 convierte un Q[F32] en Quaternion
+func FromF32(q Q[F32]) Quaternion {
+	return Quaternion{A: float64(q.A), B: float64(q.B), C: float64(q.C), D: float64(q.D)}
+}
+
+// Float32s devuelve los coeficientes en el orden a, b, c, d como float32,
+// listos para copiar a un buffer de la GPU
+func (q Quaternion) Float32s() [4]float32 {
+	return [4]float32{float32(q.A), float32(q.B), float32(q.C), float32(q.D)}
+}
+
+// ToRat convierte un Quaternion en Q[Rat] con el valor exacto de cada
+// float64. Devuelve false si algún coeficiente es infinito o NaN.
+func ToRat(q Quaternion) (Q[Rat], bool) {
+	var result [4]Rat
+	for i, x := range [4]float64{q.A, q.B, q.C, q.D} {
+		r := new(big.Rat).SetFloat64(x)
+		if r == nil {
+			return Q[Rat]{}, false
+		}
+		result[i] = Rat{r}
+	}
+	return NewQ(result[0], result[1], result[2], result[3]), true
+}
@@ -0,0 +1,166 @@
+package quaternion
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// ratQ crea un Q[Rat] con coeficientes enteros
+func ratQ(a, b, c, d int64) Q[Rat] {
+	return NewQ(NewRat(a, 1), NewRat(b, 1), NewRat(c, 1), NewRat(d, 1))
+}
+
+// TestGenericMatchesQuaternion prueba que Q[F64] calcula lo mismo que Quaternion
+func TestGenericMatchesQuaternion(t *testing.T) {
+	p := New(1, 2, 3, 4)
+	q := New(-2, 0.5, 1, 3)
+
+	if r := FromF64(ToF64(p).Multiply(ToF64(q))); !r.Equals(p.Multiply(q)) {
+		t.Errorf("Multiply: expected %s, got %s", p.Multiply(q), r)
+	}
+	if r := FromF64(ToF64(p).Add(ToF64(q)).Conjugate()); !r.Equals(p.Add(q).Conjugate()) {
+		t.Errorf("Add/Conjugate: expected %s, got %s", p.Add(q).Conjugate(), r)
+	}
+	inverse, _ := p.Inverse()
+	if r, err := ToF64(p).Inverse(); err != nil || !FromF64(r).Equals(inverse) {
+		t.Errorf("Inverse: expected %s, got %s (%v)", inverse, FromF64(r), err)
+	}
+}
+
+// TestGenericFloat32 prueba la versión de precisión simple
+func TestGenericFloat32(t *testing.T) {
+	p := New(1, 2, 3, 4)
+	q := New(-2, 0.5, 1, 3)
+	r := FromF32(ToF32(p).Multiply(ToF32(q)))
+	if expected := p.Multiply(q); !FromF32(ToF32(r)).Equals(FromF32(ToF32(expected))) {
+		t.Errorf("Expected %s, got %s", expected, r)
+	}
+	if f := p.Float32s(); f != [4]float32{1, 2, 3, 4} {
+		t.Errorf("Expected [1 2 3 4], got %v", f)
+	}
+}
+
+// TestGenericRatHamilton prueba i² = j² = k² = ijk = -1 con aritmética exacta
+func TestGenericRatHamilton(t *testing.T) {
+	i, j, k := ratQ(0, 1, 0, 0), ratQ(0, 0, 1, 0), ratQ(0, 0, 0, 1)
+	minusOne := ratQ(-1, 0, 0, 0)
+	for name, r := range map[string]Q[Rat]{
+		"i²": i.Multiply(i), "j²": j.Multiply(j), "k²": k.Multiply(k), "ijk": i.Multiply(j).Multiply(k),
+	} {
+		if !r.Equals(minusOne) {
+			t.Errorf("%s: expected -1, got %s", name, r)
+		}
+	}
+}
+
+// TestGenericRatExactInverse prueba que q * q⁻¹ es exactamente 1 y que la
+// norma es multiplicativa sin error de redondeo
+func TestGenericRatExactInverse(t *testing.T) {
+	p := NewQ(NewRat(1, 3), NewRat(-2, 7), NewRat(5, 11), NewRat(13, 17))
+	q := ratQ(3, -1, 4, 1)
+
+	inverse, err := p.Inverse()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if r := p.Multiply(inverse); !r.Equals(ratQ(1, 0, 0, 0)) {
+		t.Errorf("Expected exactly 1, got %s", r)
+	}
+	if n, expected := p.Multiply(q).Norm2(), p.Norm2().Mul(q.Norm2()); !n.Equal(expected) {
+		t.Errorf("Expected N(pq) = N(p)N(q) = %s, got %s", expected, n)
+	}
+
+	back, _ := p.Multiply(q).RightDivide(q)
+	if !back.Equals(p) {
+		t.Errorf("(p * q) / q should be exactly p, got %s", back)
+	}
+}
+
+// TestGenericRatZeroValue prueba que el valor cero es el cuaternión 0
+func TestGenericRatZeroValue(t *testing.T) {
+	var zero Q[Rat]
+	if !zero.IsZero() || zero.String() != "0 + 0i + 0j + 0k" {
+		t.Errorf("Expected 0 + 0i + 0j + 0k, got %s", zero)
+	}
+	if r := zero.Add(ratQ(1, 2, 3, 4)); !r.Equals(ratQ(1, 2, 3, 4)) {
+		t.Errorf("Expected 1 + 2i + 3j + 4k, got %s", r)
+	}
+	if _, err := zero.Inverse(); !errors.Is(err, ErrZeroNorm) {
+		t.Errorf("Expected ErrZeroNorm, got %v", err)
+	}
+}
+
+// TestGenericRatValuesAreImmutable prueba que operar no modifica los operandos
+func TestGenericRatValuesAreImmutable(t *testing.T) {
+	x := NewRat(1, 2)
+	source := big.NewRat(3, 4)
+	y := RatFromBig(source)
+	source.SetInt64(100)
+
+	_ = x.Add(y).Mul(y)
+	if x.String() != "1/2" || y.String() != "3/4" {
+		t.Errorf("Expected 1/2 and 3/4, got %s and %s", x, y)
+	}
+	y.Big().SetInt64(7)
+	if y.String() != "3/4" {
+		t.Errorf("Big should return a copy, got %s", y)
+	}
+}
+
+// TestToRatIsExact prueba que la conversión a racionales conserva el valor
+// exacto del float64
+func TestToRatIsExact(t *testing.T) {
+	q, ok := ToRat(New(0.1, -2, 0, 1e-300))
+	if !ok {
+		t.Fatalf("Expected a finite quaternion to convert")
+	}
+	if expected := new(big.Rat).SetFloat64(0.1); q.A.Big().Cmp(expected) != 0 {
+		t.Errorf("Expected %s, got %s", expected, q.A)
+	}
+	if _, ok := ToRat(New(1, 0, 0, 0).MultiplyReal(1e308).MultiplyReal(10)); ok {
+		t.Errorf("Infinite components should not convert")
+	}
+}
+
+// TestGenericBigFloat prueba la precisión arbitraria: con 200 bits
+// q * q⁻¹ es 1 con un error muy por debajo del de float64
+func TestGenericBigFloat(t *testing.T) {
+	const prec = 200
+	third := NewBigFloat(1, prec).Quo(NewBigFloat(3, prec))
+	q := NewQ(third, NewBigFloat(2, prec), third.Neg(), NewBigFloat(5, prec))
+
+	inverse, err := q.Inverse()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	diff := q.Multiply(inverse).Subtract(QFromReal(NewBigFloat(1, prec)))
+	limit := new(big.Float).SetMantExp(big.NewFloat(1), -190)
+	for _, x := range []BigFloat{diff.A, diff.B, diff.C, diff.D} {
+		if new(big.Float).Abs(x.Big()).Cmp(limit) > 0 {
+			t.Errorf("Expected an error below 2⁻¹⁹⁰, got %s", x)
+		}
+	}
+}
+
+// TestMapChangesType prueba la conversión entre tipos de coeficientes
+func TestMapChangesType(t *testing.T) {
+	q := ratQ(1, -2, 3, 4)
+	f := Map(q, func(x Rat) F64 {
+		v, _ := x.Big().Float64()
+		return F64(v)
+	})
+	if r := FromF64(f); !r.Equals(New(1, -2, 3, 4)) {
+		t.Errorf("Expected 1 - 2i + 3j + 4k, got %s", r)
+	}
+}
+
+// BenchmarkGenericMultiplyF64 compara el costo del producto genérico con el
+// de Quaternion.Multiply
+func BenchmarkGenericMultiplyF64(b *testing.B) {
+	p := ToF64(New(1, 2, 3, 4))
+	q := ToF64(New(-2, 0.5, 1, 3))
+	for i := 0; i < b.N; i++ {
+		p.Multiply(q)
+	}
+}
@@ -0,0 +1,488 @@
+package quaternion
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// Cuaterniones enteros. Los de Lipschitz tienen los cuatro coeficientes
+// enteros; los de Hurwitz además admiten que los cuatro sean semienteros,
+// como (1 + i + j + k)/2. Con esa ampliación el anillo es euclídeo: para
+// todo a y b ≠ 0 existe q con N(a - qb) < N(b), lo que da división con
+// resto, máximo común divisor y factorización en primos.
+//
+// Los coeficientes son int64 sin control de desborde. Con coeficientes de
+// valor absoluto menor que 2²⁸ (normas menores que 2⁵⁶) los productos
+// intermedios de la división no desbordan.
+
+// maxFourSquares es el mayor n que acepta FourSquares: sus primos deben
+// cumplir 2p² < 2⁶³ en la división con resto que los encuentra
+const maxFourSquares = 1 << 30
+
+var (
+	// ErrNotHurwitz indica coeficientes que no son todos enteros ni todos
+	// semienteros
+	ErrNotHurwitz = errors.New("quaternion: los coeficientes deben ser todos enteros o todos semienteros")
+	// ErrOutOfRange indica un argumento fuera del rango admitido
+	ErrOutOfRange = errors.New("quaternion: argumento fuera de rango")
+)
+
+// Lipschitz es un cuaternión a + bi + cj + dk de coeficientes enteros
+type Lipschitz struct {
+	A, B, C, D int64
+}
+
+// NewLipschitz crea un cuaternión de Lipschitz con los coeficientes dados
+func NewLipschitz(a, b, c, d int64) Lipschitz {
+	return Lipschitz{A: a, B: b, C: c, D: d}
+}
+
+// String devuelve una representación en string del cuaternión
+func (l Lipschitz) String() string {
+	return fmt.Sprintf("%d + %di + %dj + %dk", l.A, l.B, l.C, l.D)
+}
+
+// Add suma dos cuaterniones de Lipschitz
+func (l Lipschitz) Add(other Lipschitz) Lipschitz {
+	return Lipschitz{A: l.A + other.A, B: l.B + other.B, C: l.C + other.C, D: l.D + other.D}
+}
+
+// Subtract resta dos cuaterniones de Lipschitz
+func (l Lipschitz) Subtract(other Lipschitz) Lipschitz {
+	return Lipschitz{A: l.A - other.A, B: l.B - other.B, C: l.C - other.C, D: l.D - other.D}
+}
+
+// Multiply calcula el producto de Hamilton
+func (l Lipschitz) Multiply(other Lipschitz) Lipschitz {
+	p := hamilton([4]int64{l.A, l.B, l.C, l.D}, [4]int64{other.A, other.B, other.C, other.D})
+	return Lipschitz{A: p[0], B: p[1], C: p[2], D: p[3]}
+}
+
+// Conjugate calcula la conjugada a - bi - cj - dk
+func (l Lipschitz) Conjugate() Lipschitz {
+	return Lipschitz{A: l.A, B: -l.B, C: -l.C, D: -l.D}
+}
+
+// Norm calcula la norma a² + b² + c² + d², que es multiplicativa:
+// N(pq) = N(p)N(q)
+func (l Lipschitz) Norm() int64 {
+	return l.A*l.A + l.B*l.B + l.C*l.C + l.D*l.D
+}
+
+// Hurwitz devuelve el mismo cuaternión como elemento del orden de Hurwitz
+func (l Lipschitz) Hurwitz() Hurwitz {
+	return NewHurwitz(l.A, l.B, l.C, l.D)
+}
+
+// Quaternion convierte a Quaternion
+func (l Lipschitz) Quaternion() Quaternion {
+	return New(float64(l.A), float64(l.B), float64(l.C), float64(l.D))
+}
+
+// Hurwitz es un cuaternión de Hurwitz: coeficientes todos enteros o todos
+// semienteros. El valor cero es el cuaternión 0.
+type Hurwitz struct {
+	// h guarda el doble de cada coeficiente, así todo es entero: los cuatro
+	// valores son pares (cuaternión de Lipschitz) o los cuatro impares
+	h [4]int64
+}
+
+// NewHurwitz crea el cuaternión de Hurwitz de coeficientes enteros
+// a + bi + cj + dk
+func NewHurwitz(a, b, c, d int64) Hurwitz {
+	return Hurwitz{[4]int64{2 * a, 2 * b, 2 * c, 2 * d}}
+}
+
+// NewHurwitzHalves crea el cuaternión (a + bi + cj + dk)/2. Devuelve
+// ErrNotHurwitz si a, b, c y d no tienen todos la misma paridad.
+func NewHurwitzHalves(a, b, c, d int64) (Hurwitz, error) {
+	parity := a & 1
+	if b&1 != parity || c&1 != parity || d&1 != parity {
+		return Hurwitz{}, ErrNotHurwitz
+	}
+	return Hurwitz{[4]int64{a, b, c, d}}, nil
+}
+
+// Halves devuelve el doble de cada coeficiente: el cuaternión es
+// (h[0] + h[1]i + h[2]j + h[3]k)/2
+func (h Hurwitz) Halves() [4]int64 {
+	return h.h
+}
+
+// String devuelve una representación en string del cuaternión, como
+// fracción sobre 2 si los coeficientes son semienteros
+func (h Hurwitz) String() string {
+	if l, ok := h.Lipschitz(); ok {
+		return l.String()
+	}
+	return fmt.Sprintf("(%d + %di + %dj + %dk)/2", h.h[0], h.h[1], h.h[2], h.h[3])
+}
+
+// Lipschitz devuelve el cuaternión de coeficientes enteros y true, o false
+// si los coeficientes son semienteros
+func (h Hurwitz) Lipschitz() (Lipschitz, bool) {
+	if h.h[0]&1 != 0 {
+		return Lipschitz{}, false
+	}
+	return Lipschitz{A: h.h[0] / 2, B: h.h[1] / 2, C: h.h[2] / 2, D: h.h[3] / 2}, true
+}
+
+// Quaternion convierte a Quaternion
+func (h Hurwitz) Quaternion() Quaternion {
+	return New(float64(h.h[0])/2, float64(h.h[1])/2, float64(h.h[2])/2, float64(h.h[3])/2)
+}
+
+// Add suma dos cuaterniones de Hurwitz
+func (h Hurwitz) Add(other Hurwitz) Hurwitz {
+	return Hurwitz{[4]int64{h.h[0] + other.h[0], h.h[1] + other.h[1], h.h[2] + other.h[2], h.h[3] + other.h[3]}}
+}
+
+// Subtract resta dos cuaterniones de Hurwitz
+func (h Hurwitz) Subtract(other Hurwitz) Hurwitz {
+	return Hurwitz{[4]int64{h.h[0] - other.h[0], h.h[1] - other.h[1], h.h[2] - other.h[2], h.h[3] - other.h[3]}}
+}
+
+// Multiply calcula el producto de Hamilton, que siempre es de Hurwitz
+func (h Hurwitz) Multiply(other Hurwitz) Hurwitz {
+	// (2x)(2y) = 4xy, y el doble del producto es la mitad de eso
+	p := hamilton(h.h, other.h)
+	return Hurwitz{[4]int64{p[0] / 2, p[1] / 2, p[2] / 2, p[3] / 2}}
+}
+
+// Conjugate calcula la conjugada
+func (h Hurwitz) Conjugate() Hurwitz {
+	return Hurwitz{[4]int64{h.h[0], -h.h[1], -h.h[2], -h.h[3]}}
+}
+
+// Negate calcula el opuesto
+func (h Hurwitz) Negate() Hurwitz {
+	return Hurwitz{[4]int64{-h.h[0], -h.h[1], -h.h[2], -h.h[3]}}
+}
+
+// Norm calcula la norma a² + b² + c² + d², que siempre es entera
+func (h Hurwitz) Norm() int64 {
+	return (h.h[0]*h.h[0] + h.h[1]*h.h[1] + h.h[2]*h.h[2] + h.h[3]*h.h[3]) / 4
+}
+
+// IsZero indica si es el cuaternión cero
+func (h Hurwitz) IsZero() bool {
+	return h.h == [4]int64{}
+}
+
+// IsUnit indica si es una de las 24 unidades: ±1, ±i, ±j, ±k y
+// (±1 ± i ± j ± k)/2
+func (h Hurwitz) IsUnit() bool {
+	return h.Norm() == 1
+}
+
+// hurwitzUnits son las 24 unidades del orden de Hurwitz
+var hurwitzUnits = func() []Hurwitz {
+	var units []Hurwitz
+	for i := 0; i < 4; i++ {
+		for _, s := range []int64{2, -2} {
+			var u Hurwitz
+			u.h[i] = s
+			units = append(units, u)
+		}
+	}
+	for mask := 0; mask < 16; mask++ {
+		var u Hurwitz
+		for i := range u.h {
+			u.h[i] = 1 - 2*int64(mask>>i&1)
+		}
+		units = append(units, u)
+	}
+	return units
+}()
+
+// hamilton calcula el producto de Hamilton de dos vectores de coeficientes
+func hamilton(x, y [4]int64) [4]int64 {
+	return [4]int64{
+		x[0]*y[0] - x[1]*y[1] - x[2]*y[2] - x[3]*y[3],
+		x[0]*y[1] + x[1]*y[0] + x[2]*y[3] - x[3]*y[2],
+		x[0]*y[2] - x[1]*y[3] + x[2]*y[0] + x[3]*y[1],
+		x[0]*y[3] + x[1]*y[2] - x[2]*y[1] + x[3]*y[0],
+	}
+}
+
+// floorDiv calcula ⌊p / q⌋ para q > 0
+func floorDiv(p, q int64) int64 {
+	f := p / q
+	if p%q != 0 && p < 0 {
+		f--
+	}
+	return f
+}
+
+// nearestHurwitz devuelve el cuaternión de Hurwitz más cercano a p/(4n),
+// donde p es el producto sin reducir de los dobles de dos cuaterniones.
+// Prueba el punto de coeficientes enteros más cercano y el de coeficientes
+// semienteros más cercano y se queda con el mejor; uno de los dos está
+// siempre a distancia al cuadrado ≤ 1/2.
+func nearestHurwitz(p [4]int64, n int64) Hurwitz {
+	// En dobles el objetivo es p/(2n). El candidato par redondea p/(4n) y el
+	// impar toma 2⌊p/(4n)⌋ + 1.
+	var even, odd Hurwitz
+	var distEven, distOdd float64
+	for i, x := range p {
+		f := floorDiv(x, 4*n)
+		rest := x - f*4*n // en [0, 4n)
+		e := 2 * f
+		if rest >= 4*n-rest {
+			e += 2
+		}
+		even.h[i] = e
+		odd.h[i] = 2*f + 1
+
+		de := float64(x - 2*n*e)
+		do := float64(x - 2*n*odd.h[i])
+		distEven += de * de
+		distOdd += do * do
+	}
+	if distOdd < distEven {
+		return odd
+	}
+	return even
+}
+
+// RightDivMod divide por la derecha con resto: devuelve q y r tales que
+// h = q * other + r con N(r) < N(other); de hecho N(r) ≤ N(other)/2.
+// Devuelve ErrZeroNorm si other es cero.
+func (h Hurwitz) RightDivMod(other Hurwitz) (Hurwitz, Hurwitz, error) {
+	n := other.Norm()
+	if n == 0 {
+		return Hurwitz{}, Hurwitz{}, ErrZeroNorm
+	}
+	// q ≈ h * ~other / N(other)
+	q := nearestHurwitz(hamilton(h.h, other.Conjugate().h), n)
+	return q, h.Subtract(q.Multiply(other)), nil
+}
+
+// LeftDivMod divide por la izquierda con resto: devuelve q y r tales que
+// h = other * q + r con N(r) < N(other). Devuelve ErrZeroNorm si other es
+// cero.
+func (h Hurwitz) LeftDivMod(other Hurwitz) (Hurwitz, Hurwitz, error) {
+	n := other.Norm()
+	if n == 0 {
+		return Hurwitz{}, Hurwitz{}, ErrZeroNorm
+	}
+	// q ≈ ~other * h / N(other)
+	q := nearestHurwitz(hamilton(other.Conjugate().h, h.h), n)
+	return q, h.Subtract(other.Multiply(q)), nil
+}
+
+// RightGCD calcula un máximo común divisor por la derecha de a y b: un g
+// tal que a = x * g y b = y * g, y que cualquier otro divisor común por la
+// derecha divide a g por la derecha. Es único salvo multiplicar por una
+// unidad a la izquierda. El de dos ceros es cero.
+func RightGCD(a, b Hurwitz) Hurwitz {
+	for !b.IsZero() {
+		_, r, _ := a.RightDivMod(b)
+		a, b = b, r
+	}
+	return a
+}
+
+// LeftGCD calcula un máximo común divisor por la izquierda: un g tal que
+// a = g * x y b = g * y
+func LeftGCD(a, b Hurwitz) Hurwitz {
+	for !b.IsZero() {
+		_, r, _ := a.LeftDivMod(b)
+		a, b = b, r
+	}
+	return a
+}
+
+// rightExactDiv calcula h * d⁻¹ sabiendo que d divide a h por la derecha
+func (h Hurwitz) rightExactDiv(d Hurwitz) Hurwitz {
+	// (2h)(2~d) = 4h~d y el doble del cociente es 4h~d / (2N(d))
+	p := hamilton(h.h, d.Conjugate().h)
+	n := 2 * d.Norm()
+	return Hurwitz{[4]int64{p[0] / n, p[1] / n, p[2] / n, p[3] / n}}
+}
+
+// Factor factoriza el cuaternión en cuaterniones de norma prima:
+// h = factors[0] * factors[1] * ... con N(factors[i]) primo y las normas en
+// orden creciente de izquierda a derecha, repetidas según su multiplicidad
+// en N(h). La unidad que sobra queda incluida en factors[0]. Una unidad se
+// devuelve sola. Devuelve ErrZeroNorm si h es cero.
+func (h Hurwitz) Factor() ([]Hurwitz, error) {
+	n := h.Norm()
+	if n == 0 {
+		return nil, ErrZeroNorm
+	}
+	primes := primeFactors(n)
+	if len(primes) == 0 {
+		return []Hurwitz{h}, nil
+	}
+
+	// Se extraen por la derecha empezando por el primo mayor
+	factors := make([]Hurwitz, len(primes))
+	rest := h
+	for i := len(primes) - 1; i >= 0; i-- {
+		p := primes[i]
+		g := RightGCD(rest, NewHurwitz(p, 0, 0, 0))
+		if g.Norm() != p {
+			// p divide a rest, que es q * p = (q * ~π) * π para cualquier π de
+			// norma p
+			g = primeOfNorm(p)
+		}
+		factors[i] = g
+		rest = rest.rightExactDiv(g)
+	}
+	factors[0] = rest.Multiply(factors[0])
+	return factors, nil
+}
+
+// primeOfNorm devuelve un cuaternión de Hurwitz de norma p, que debe ser
+// primo. Busca x, y con 1 + x² + y² ≡ 0 (mod p): p divide la norma de
+// 1 + xi + yj pero no a sus coeficientes, así que su divisor común con p
+// tiene norma p.
+func primeOfNorm(p int64) Hurwitz {
+	if p == 2 {
+		return NewHurwitz(1, 1, 0, 0)
+	}
+	for x := int64(0); x < p; x++ {
+		t := ((-1-x*x%p)%p + p) % p
+		y, ok := sqrtMod(t, p)
+		if !ok {
+			continue
+		}
+		// Representantes centrados para que la norma quede por debajo de p²
+		return RightGCD(NewHurwitz(p, 0, 0, 0), NewHurwitz(1, centered(x, p), centered(y, p), 0))
+	}
+	panic(fmt.Sprintf("quaternion: %d no es primo", p))
+}
+
+// FourSquares escribe n como suma de cuatro cuadrados, n = a² + b² + c² + d²
+// (teorema de Lagrange), multiplicando cuaterniones de Hurwitz de norma
+// prima. Devuelve los cuatro valores no negativos en orden decreciente, o
+// ErrOutOfRange si n es negativo o mayor que 2³⁰.
+func FourSquares(n int64) ([4]int64, error) {
+	if n < 0 || n > maxFourSquares {
+		return [4]int64{}, ErrOutOfRange
+	}
+	if n == 0 {
+		return [4]int64{}, nil
+	}
+
+	product := NewHurwitz(1, 0, 0, 0)
+	for _, p := range primeFactors(n) {
+		product = product.Multiply(primeOfNorm(p))
+	}
+
+	// Todo cuaternión de Hurwitz tiene un asociado de coeficientes enteros
+	// con la misma norma
+	for _, u := range hurwitzUnits {
+		l, ok := product.Multiply(u).Lipschitz()
+		if !ok {
+			continue
+		}
+		squares := [4]int64{abs64(l.A), abs64(l.B), abs64(l.C), abs64(l.D)}
+		sort.Slice(squares[:], func(i, j int) bool { return squares[i] > squares[j] })
+		return squares, nil
+	}
+	panic("quaternion: ningún asociado de Lipschitz")
+}
+
+// centered devuelve el representante de x módulo p en (-p/2, p/2]
+func centered(x, p int64) int64 {
+	if 2*x > p {
+		return x - p
+	}
+	return x
+}
+
+func abs64(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// primeFactors devuelve los factores primos de n > 0 en orden creciente,
+// repetidos según su multiplicidad, por división de prueba
+func primeFactors(n int64) []int64 {
+	var primes []int64
+	for p := int64(2); p*p <= n; p++ {
+		for n%p == 0 {
+			primes = append(primes, p)
+			n /= p
+		}
+	}
+	if n > 1 {
+		primes = append(primes, n)
+	}
+	return primes
+}
+
+// mulMod calcula a·b mod m sin desborde usando el producto de 128 bits
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	_, rem := bits.Div64(hi%m, lo, m)
+	return rem
+}
+
+// powMod calcula a^b mod m por cuadrados sucesivos. Es la misma potenciación
+// modulada de potenciacion_modular, pero iterativa y en O(log b) pasos, que
+// es lo que necesitan los módulos grandes de la teoría de números.
+func powMod(a, b, m uint64) uint64 {
+	result := uint64(1) % m
+	a %= m
+	for b > 0 {
+		if b&1 == 1 {
+			result = mulMod(result, a, m)
+		}
+		a = mulMod(a, a, m)
+		b >>= 1
+	}
+	return result
+}
+
+// sqrtMod calcula una raíz cuadrada de t módulo el primo impar p con el
+// algoritmo de Tonelli-Shanks. Devuelve false si t no es un cuadrado.
+func sqrtMod(t, p int64) (int64, bool) {
+	a, m := uint64(t), uint64(p)
+	if a == 0 {
+		return 0, true
+	}
+	// Criterio de Euler: t es un cuadrado si t^((p-1)/2) ≡ 1
+	if powMod(a, (m-1)/2, m) != 1 {
+		return 0, false
+	}
+
+	// p - 1 = q·2^s con q impar
+	q, s := m-1, 0
+	for q%2 == 0 {
+		q /= 2
+		s++
+	}
+	// z es un no cuadrado cualquiera
+	z := uint64(2)
+	for powMod(z, (m-1)/2, m) != m-1 {
+		z++
+	}
+
+	c := powMod(z, q, m)
+	x := powMod(a, (q+1)/2, m)
+	r := powMod(a, q, m)
+	for r != 1 {
+		// Menor i con r^(2^i) ≡ 1
+		i, r2 := 0, r
+		for r2 != 1 {
+			r2 = mulMod(r2, r2, m)
+			i++
+		}
+		b := c
+		for j := 0; j < s-i-1; j++ {
+			b = mulMod(b, b, m)
+		}
+		x = mulMod(x, b, m)
+		c = mulMod(b, b, m)
+		r = mulMod(r, c, m)
+		s = i
+	}
+	return int64(x), true
+}
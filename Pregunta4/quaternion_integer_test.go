@@ -0,0 +1,211 @@
+package quaternion
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// randomHurwitz genera cuaterniones de Hurwitz reproducibles, la mitad con
+// coeficientes semienteros
+func randomHurwitz(r *rand.Rand, limit int64) Hurwitz {
+	var halves [4]int64
+	parity := r.Int63n(2)
+	for i := range halves {
+		halves[i] = 2*(r.Int63n(2*limit+1)-limit) + parity
+	}
+	h, _ := NewHurwitzHalves(halves[0], halves[1], halves[2], halves[3])
+	return h
+}
+
+// TestLipschitzArithmetic prueba el producto y la norma de enteros
+func TestLipschitzArithmetic(t *testing.T) {
+	p := NewLipschitz(1, 2, 3, 4)
+	q := NewLipschitz(2, -3, 4, 5)
+	if r := p.Multiply(q).Quaternion(); !r.Equals(p.Quaternion().Multiply(q.Quaternion())) {
+		t.Errorf("Expected %s, got %s", p.Quaternion().Multiply(q.Quaternion()), r)
+	}
+	if n := p.Multiply(q).Norm(); n != p.Norm()*q.Norm() {
+		t.Errorf("Expected N(pq) = %d, got %d", p.Norm()*q.Norm(), n)
+	}
+	if s := p.Subtract(q).String(); s != "-1 + 5i + -1j + -1k" {
+		t.Errorf("Expected -1 + 5i + -1j + -1k, got %s", s)
+	}
+}
+
+// TestHurwitzHalfIntegers prueba los cuaterniones semienteros
+func TestHurwitzHalfIntegers(t *testing.T) {
+	omega, err := NewHurwitzHalves(-1, 1, 1, 1)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !omega.IsUnit() || omega.String() != "(-1 + 1i + 1j + 1k)/2" {
+		t.Errorf("Expected the unit (-1 + 1i + 1j + 1k)/2, got %s", omega)
+	}
+	// ω = (-1 + i + j + k)/2 es raíz cúbica de la unidad
+	if cube := omega.Multiply(omega).Multiply(omega); cube != NewHurwitz(1, 0, 0, 0) {
+		t.Errorf("Expected ω³ = 1, got %s", cube)
+	}
+	if _, ok := omega.Lipschitz(); ok {
+		t.Errorf("A half-integer quaternion is not Lipschitz")
+	}
+	if _, err := NewHurwitzHalves(1, 2, 1, 1); !errors.Is(err, ErrNotHurwitz) {
+		t.Errorf("Expected ErrNotHurwitz, got %v", err)
+	}
+	if len(hurwitzUnits) != 24 {
+		t.Errorf("Expected 24 units, got %d", len(hurwitzUnits))
+	}
+}
+
+// TestHurwitzDivMod prueba la división con resto por ambos lados
+func TestHurwitzDivMod(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for n := 0; n < 2000; n++ {
+		a := randomHurwitz(r, 1000)
+		b := randomHurwitz(r, 30)
+		if b.IsZero() {
+			continue
+		}
+
+		q, rest, err := a.RightDivMod(b)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if q.Multiply(b).Add(rest) != a || 2*rest.Norm() > b.Norm() {
+			t.Fatalf("RightDivMod(%s, %s): got q = %s, r = %s", a, b, q, rest)
+		}
+
+		q, rest, _ = a.LeftDivMod(b)
+		if b.Multiply(q).Add(rest) != a || 2*rest.Norm() > b.Norm() {
+			t.Fatalf("LeftDivMod(%s, %s): got q = %s, r = %s", a, b, q, rest)
+		}
+	}
+}
+
+// TestHurwitzDivModNeedsHalfIntegers prueba el caso en que el cociente de
+// Lipschitz no alcanza: 1 + i + j + k entre 2 deja resto de norma 4 = N(2)
+func TestHurwitzDivModNeedsHalfIntegers(t *testing.T) {
+	q, rest, _ := NewHurwitz(1, 1, 1, 1).RightDivMod(NewHurwitz(2, 0, 0, 0))
+	if expected, _ := NewHurwitzHalves(1, 1, 1, 1); q != expected || !rest.IsZero() {
+		t.Errorf("Expected q = %s and r = 0, got q = %s, r = %s", expected, q, rest)
+	}
+	if _, _, err := q.RightDivMod(Hurwitz{}); !errors.Is(err, ErrZeroNorm) {
+		t.Errorf("Expected ErrZeroNorm, got %v", err)
+	}
+}
+
+// TestHurwitzGCD prueba que el máximo común divisor divide a ambos y tiene
+// la norma esperada
+func TestHurwitzGCD(t *testing.T) {
+	g := NewHurwitz(1, 2, 0, 1) // norma 6
+	x := NewHurwitz(2, 1, 1, 1) // norma 7
+	y := NewHurwitz(3, 1, 0, 1) // norma 11
+	a, b := x.Multiply(g), y.Multiply(g)
+
+	right := RightGCD(a, b)
+	if right.Norm() != g.Norm() {
+		t.Errorf("Expected a right gcd of norm %d, got %s", g.Norm(), right)
+	}
+	for _, v := range []Hurwitz{a, b} {
+		if _, rest, _ := v.RightDivMod(right); !rest.IsZero() {
+			t.Errorf("%s should be a right divisor of %s", right, v)
+		}
+	}
+
+	a, b = g.Multiply(x), g.Multiply(y)
+	left := LeftGCD(a, b)
+	if left.Norm() != g.Norm() {
+		t.Errorf("Expected a left gcd of norm %d, got %s", g.Norm(), left)
+	}
+	for _, v := range []Hurwitz{a, b} {
+		if _, rest, _ := v.LeftDivMod(left); !rest.IsZero() {
+			t.Errorf("%s should be a left divisor of %s", left, v)
+		}
+	}
+}
+
+// TestHurwitzFactor prueba que el producto de los factores es el original y
+// que sus normas son los primos de la norma en orden creciente
+func TestHurwitzFactor(t *testing.T) {
+	cases := []Hurwitz{
+		NewHurwitz(1, 2, 3, 4),
+		NewHurwitz(7, -1, 0, 2),
+		NewHurwitz(3, 3, 0, 0),      // divisible por el entero 3
+		NewHurwitz(12, 0, 0, 0),     // un entero
+		NewHurwitz(0, 1, 0, 0),      // una unidad
+		NewHurwitz(1, 1, 1, 1),      // norma 4
+		NewHurwitz(101, 13, -7, 29), // norma con un primo grande
+	}
+	for _, h := range cases {
+		factors, err := h.Factor()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		product := NewHurwitz(1, 0, 0, 0)
+		var norms []int64
+		for _, f := range factors {
+			product = product.Multiply(f)
+			norms = append(norms, f.Norm())
+		}
+		if product != h {
+			t.Errorf("Factor(%s): the product of %v is %s", h, factors, product)
+		}
+		if h.IsUnit() {
+			continue
+		}
+		primes := primeFactors(h.Norm())
+		for i := range primes {
+			if norms[i] != primes[i] {
+				t.Errorf("Factor(%s): expected norms %v, got %v", h, primes, norms)
+				break
+			}
+		}
+	}
+
+	if _, err := (Hurwitz{}).Factor(); !errors.Is(err, ErrZeroNorm) {
+		t.Errorf("Expected ErrZeroNorm, got %v", err)
+	}
+}
+
+// TestFourSquares prueba la representación como suma de cuatro cuadrados
+func TestFourSquares(t *testing.T) {
+	values := []int64{0, 1, 2, 3, 7, 15, 28, 1000000007, maxFourSquares - 1, maxFourSquares}
+	for n := int64(1); n <= 2000; n++ {
+		values = append(values, n)
+	}
+	for _, n := range values {
+		s, err := FourSquares(n)
+		if err != nil {
+			t.Fatalf("FourSquares(%d): unexpected error: %v", n, err)
+		}
+		if sum := s[0]*s[0] + s[1]*s[1] + s[2]*s[2] + s[3]*s[3]; sum != n {
+			t.Fatalf("FourSquares(%d): %v adds up to %d", n, s, sum)
+		}
+		if s[0] < s[1] || s[1] < s[2] || s[2] < s[3] || s[3] < 0 {
+			t.Fatalf("FourSquares(%d): expected non-negative values in decreasing order, got %v", n, s)
+		}
+	}
+
+	for _, n := range []int64{-1, maxFourSquares + 1} {
+		if _, err := FourSquares(n); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("FourSquares(%d): expected ErrOutOfRange, got %v", n, err)
+		}
+	}
+}
+
+// TestSqrtMod prueba las raíces cuadradas modulares
+func TestSqrtMod(t *testing.T) {
+	for _, p := range []int64{3, 5, 13, 17, 41, 1000000007} {
+		for a := int64(0); a < 50; a++ {
+			x, ok := sqrtMod(a%p, p)
+			isSquare := a%p == 0 || powMod(uint64(a%p), uint64(p-1)/2, uint64(p)) == 1
+			if ok != isSquare {
+				t.Fatalf("sqrtMod(%d, %d): expected ok = %v", a, p, isSquare)
+			}
+			if ok && mulMod(uint64(x), uint64(x), uint64(p)) != uint64(a%p) {
+				t.Fatalf("sqrtMod(%d, %d) = %d is not a square root", a, p, x)
+			}
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package quaternion
+
+import (
+	"errors"
+	"math"
+)
+
+// Interpolación de orientaciones. Todas las funciones esperan cuaterniones
+// unitarios (ver Normalize y UnitQuaternion); t = 0 da el primer extremo y
+// t = 1 el segundo.
+
+// nlerpThreshold es el ángulo entre orientaciones por debajo del cual Slerp
+// usa interpolación lineal normalizada: ahí sen(θ) es tan chico que la
+// fórmula de Slerp pierde precisión y ambas coinciden dentro del redondeo
+const nlerpThreshold = 1e-6
+
+// ErrTooFewKeys indica que una spline necesita al menos dos orientaciones
+var ErrTooFewKeys = errors.New("quaternion: la spline necesita al menos dos orientaciones")
+
+// Dot calcula el producto escalar de los coeficientes de dos cuaterniones
+// a₁a₂ + b₁b₂ + c₁c₂ + d₁d₂
+func (q Quaternion) Dot(other Quaternion) float64 {
+	return q.A*other.A + q.B*other.B + q.C*other.C + q.D*other.D
+}
+
+// shortestPath devuelve q2 o -q2, el que esté más cerca de q1. Ambos
+// representan la misma rotación, pero interpolar hacia el más cercano
+// recorre el arco corto.
+func shortestPath(q1, q2 Quaternion) Quaternion {
+	if q1.Dot(q2) < 0 {
+		return q2.Negate()
+	}
+	return q2
+}
+
+// Slerp interpola esféricamente entre q1 y q2 a velocidad angular constante,
+// siguiendo el camino más corto entre las dos orientaciones
+func Slerp(q1, q2 Quaternion, t float64) Quaternion {
+	return slerp(q1, shortestPath(q1, q2), t)
+}
+
+// slerp interpola sobre el arco de q1 a q2 sin elegir el camino más corto;
+// Squad lo necesita así para no romper la continuidad entre tramos
+func slerp(q1, q2 Quaternion, t float64) Quaternion {
+	// θ/2 con atan2 en lugar de acos(q1·q2), que pierde precisión cuando
+	// los cuaterniones son casi paralelos
+	halfTheta := math.Atan2(q1.Subtract(q2).Abs(), q1.Add(q2).Abs())
+	theta := 2 * halfTheta
+	if theta < nlerpThreshold {
+		return nlerp(q1, q2, t)
+	}
+
+	sinTheta := math.Sin(theta)
+	w1 := math.Sin((1-t)*theta) / sinTheta
+	w2 := math.Sin(t*theta) / sinTheta
+	return q1.MultiplyReal(w1).Add(q2.MultiplyReal(w2))
+}
+
+// Nlerp interpola linealmente entre q1 y q2 por el camino más corto y
+// normaliza el resultado. Es más rápida que Slerp y sigue la misma curva,
+// pero la velocidad angular no es constante.
+func Nlerp(q1, q2 Quaternion, t float64) Quaternion {
+	return nlerp(q1, shortestPath(q1, q2), t)
+}
+
+func nlerp(q1, q2 Quaternion, t float64) Quaternion {
+	lerp := q1.MultiplyReal(1 - t).Add(q2.MultiplyReal(t))
+	n, err := lerp.Normalize()
+	if err != nil {
+		// Solo ocurre si q2 = -q1 y t = 1/2: no hay una orientación intermedia
+		return q1
+	}
+	return n
+}
+
+// SquadControlPoint calcula el punto de control de Squad para la orientación
+// current, dadas la anterior y la siguiente:
+// s = current * exp(-(log(current⁻¹ * prev) + log(current⁻¹ * next)) / 4)
+// Las tres deben estar en el mismo hemisferio (ver NewSquadSpline).
+func SquadControlPoint(prev, current, next Quaternion) Quaternion {
+	inverse := current.Conjugate()
+	sum := inverse.Multiply(prev).Log().Add(inverse.Multiply(next).Log())
+	return current.Multiply(sum.MultiplyReal(-0.25).Exp())
+}
+
+// Squad interpola entre q1 y q2 con los puntos de control s1 y s2 mediante
+// slerp(slerp(q1, q2, t), slerp(s1, s2, t), 2t(1 - t)). Con los puntos de
+// control de SquadControlPoint la curva tiene derivada continua al pasar
+// por cada orientación.
+func Squad(q1, q2, s1, s2 Quaternion, t float64) Quaternion {
+	return slerp(slerp(q1, q2, t), slerp(s1, s2, t), 2*t*(1-t))
+}
+
+// SquadSpline interpola con Squad una secuencia de orientaciones
+type SquadSpline struct {
+	keys     []Quaternion
+	controls []Quaternion
+}
+
+// NewSquadSpline prepara la spline que pasa por las orientaciones dadas.
+// Las orientaciones se normalizan y se eligen los signos de modo que cada
+// una quede en el hemisferio de la anterior, así cada tramo recorre el arco
+// corto. Devuelve ErrTooFewKeys con menos de dos orientaciones y
+// ErrZeroNorm si alguna es el cuaternión cero.
+func NewSquadSpline(keys []Quaternion) (*SquadSpline, error) {
+	if len(keys) < 2 {
+		return nil, ErrTooFewKeys
+	}
+
+	spline := &SquadSpline{
+		keys:     make([]Quaternion, len(keys)),
+		controls: make([]Quaternion, len(keys)),
+	}
+	for i, key := range keys {
+		n, err := key.Normalize()
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			n = shortestPath(spline.keys[i-1], n)
+		}
+		spline.keys[i] = n
+	}
+
+	// En los extremos no hay vecino: el punto de control es la propia orientación
+	last := len(keys) - 1
+	spline.controls[0] = spline.keys[0]
+	spline.controls[last] = spline.keys[last]
+	for i := 1; i < last; i++ {
+		spline.controls[i] = SquadControlPoint(spline.keys[i-1], spline.keys[i], spline.keys[i+1])
+	}
+	return spline, nil
+}
+
+// Evaluate devuelve la orientación en el parámetro t: la parte entera indica
+// el tramo y la fraccionaria la posición dentro de él, de modo que t = i da
+// la orientación i. Los valores fuera de [0, len-1] se recortan al extremo.
+func (s *SquadSpline) Evaluate(t float64) Quaternion {
+	last := len(s.keys) - 1
+	if t <= 0 {
+		return s.keys[0]
+	}
+	if t >= float64(last) {
+		return s.keys[last]
+	}
+	i := int(t)
+	return Squad(s.keys[i], s.keys[i+1], s.controls[i], s.controls[i+1], t-float64(i))
+}
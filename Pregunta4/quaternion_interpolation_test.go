@@ -0,0 +1,188 @@
+package quaternion
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// angleBetween calcula el ángulo de la rotación que lleva de q1 a q2
+func angleBetween(q1, q2 Quaternion) float64 {
+	_, angle := q1.Conjugate().Multiply(q2).ToAxisAngle()
+	return angle
+}
+
+// TestSlerpEndpoints prueba que t = 0 y t = 1 dan los extremos
+func TestSlerpEndpoints(t *testing.T) {
+	q1 := FromAxisAngle([3]float64{1, 0, 0}, 0.4)
+	q2 := FromAxisAngle([3]float64{0, 1, 1}, 2.1)
+
+	if r := Slerp(q1, q2, 0); !r.Equals(q1) {
+		t.Errorf("Expected %s, got %s", q1, r)
+	}
+	if r := Slerp(q1, q2, 1); !r.Equals(q2) {
+		t.Errorf("Expected %s, got %s", q2, r)
+	}
+}
+
+// TestSlerpMidpoint prueba que la mitad del camino es la mitad del ángulo
+func TestSlerpMidpoint(t *testing.T) {
+	z := [3]float64{0, 0, 1}
+	r := Slerp(Identity(), FromAxisAngle(z, math.Pi/2), 0.5)
+	if expected := FromAxisAngle(z, math.Pi/4); !r.Equals(expected) {
+		t.Errorf("Expected %s, got %s", expected, r)
+	}
+}
+
+// TestSlerpConstantVelocity prueba que pasos iguales de t giran ángulos iguales
+func TestSlerpConstantVelocity(t *testing.T) {
+	q1 := FromEuler([3]float64{0.2, -0.5, 1.0}, EulerZYX)
+	q2 := FromEuler([3]float64{-1.1, 0.7, 0.3}, EulerZYX)
+	total := angleBetween(q1, q2)
+
+	previous := q1
+	for i := 1; i <= 10; i++ {
+		current := Slerp(q1, q2, float64(i)/10)
+		if step := angleBetween(previous, current); math.Abs(step-total/10) > 1e-12 {
+			t.Errorf("Step %d: expected angle %g, got %g", i, total/10, step)
+		}
+		previous = current
+	}
+}
+
+// TestSlerpShortestPath prueba que q2 y -q2 dan la misma interpolación
+func TestSlerpShortestPath(t *testing.T) {
+	q1 := FromAxisAngle([3]float64{0, 0, 1}, 0.1)
+	q2 := FromAxisAngle([3]float64{0, 0, 1}, 1.2)
+
+	for _, f := range []func(Quaternion, Quaternion, float64) Quaternion{Slerp, Nlerp} {
+		if a, b := f(q1, q2, 0.3), f(q1, q2.Negate(), 0.3); !a.SameRotation(b, 1e-9) {
+			t.Errorf("Expected the same rotation, got %s and %s", a, b)
+		}
+		if angle := angleBetween(q1, f(q1, q2.Negate(), 0.5)); angle > 1.1/2+1e-3 {
+			t.Errorf("Expected the short arc, got an angle of %g", angle)
+		}
+	}
+}
+
+// TestSlerpNearlyParallel prueba la estabilidad con entradas casi iguales
+func TestSlerpNearlyParallel(t *testing.T) {
+	q1 := FromAxisAngle([3]float64{1, 2, 3}, 0.7)
+	for _, delta := range []float64{0, 1e-15, 1e-9, 1e-7} {
+		q2 := q1.Multiply(FromAxisAngle([3]float64{0, 1, 0}, delta))
+		r := Slerp(q1, q2, 0.5)
+		if math.IsNaN(r.A) || math.Abs(r.Abs()-1) > 1e-15 {
+			t.Errorf("Delta %g: expected a unit quaternion, got %s", delta, r)
+		}
+		if angle := angleBetween(q1, r); math.Abs(angle-delta/2) > 1e-12 {
+			t.Errorf("Delta %g: expected angle %g, got %g", delta, delta/2, angle)
+		}
+	}
+}
+
+// TestNlerp prueba los extremos y que el resultado es unitario
+func TestNlerp(t *testing.T) {
+	q1 := FromAxisAngle([3]float64{1, 0, 0}, 0.4)
+	q2 := FromAxisAngle([3]float64{0, 1, 1}, 2.1)
+
+	if r := Nlerp(q1, q2, 0); !r.Equals(q1) {
+		t.Errorf("Expected %s, got %s", q1, r)
+	}
+	if r := Nlerp(q1, q2, 1); !r.Equals(q2) {
+		t.Errorf("Expected %s, got %s", q2, r)
+	}
+	if r := Nlerp(q1, q2, 0.37); math.Abs(r.Abs()-1) > 1e-15 {
+		t.Errorf("Expected a unit quaternion, got |r| = %.17f", r.Abs())
+	}
+	// Con dos extremos simétricos la mitad coincide con Slerp
+	if a, b := Nlerp(q1, q2, 0.5), Slerp(q1, q2, 0.5); !a.Equals(b) {
+		t.Errorf("Expected %s, got %s", b, a)
+	}
+}
+
+// TestSquadWithoutNeighbors prueba que sin vecinos Squad se reduce a Slerp
+func TestSquadWithoutNeighbors(t *testing.T) {
+	q1 := FromAxisAngle([3]float64{1, 0, 0}, 0.4)
+	q2 := FromAxisAngle([3]float64{0, 1, 1}, 2.1)
+	for _, tt := range []float64{0, 0.25, 0.5, 0.9, 1} {
+		if a, b := Squad(q1, q2, q1, q2, tt), Slerp(q1, q2, tt); !a.Equals(b) {
+			t.Errorf("t = %g: expected %s, got %s", tt, b, a)
+		}
+	}
+}
+
+// splineKeysForTest son orientaciones de prueba con un cambio de signo
+// en medio, que la spline debe corregir
+func splineKeysForTest() []Quaternion {
+	return []Quaternion{
+		Identity(),
+		FromEuler([3]float64{0.5, 0.2, -0.1}, EulerZYX),
+		FromEuler([3]float64{1.2, -0.4, 0.6}, EulerZYX).Negate(),
+		FromEuler([3]float64{0.3, 0.9, 1.5}, EulerZYX).MultiplyReal(3),
+		FromEuler([3]float64{-0.7, 0.1, 2.0}, EulerZYX),
+	}
+}
+
+// TestSquadSplineThroughKeys prueba que la spline pasa por las orientaciones
+func TestSquadSplineThroughKeys(t *testing.T) {
+	keys := splineKeysForTest()
+	spline, err := NewSquadSpline(keys)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i, key := range keys {
+		if r := spline.Evaluate(float64(i)); !r.SameRotation(key, 1e-9) {
+			t.Errorf("Key %d: expected %s, got %s", i, key, r)
+		}
+	}
+	if r := spline.Evaluate(-1); !r.Equals(keys[0]) {
+		t.Errorf("Expected the first key before the start, got %s", r)
+	}
+}
+
+// TestSquadSplineC1 prueba que la derivada es continua en cada orientación
+// intermedia comparando diferencias finitas a izquierda y derecha
+func TestSquadSplineC1(t *testing.T) {
+	spline, err := NewSquadSpline(splineKeysForTest())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	const h = 1e-5
+	for i := 1; i < 4; i++ {
+		at := spline.Evaluate(float64(i))
+		left := at.Subtract(spline.Evaluate(float64(i) - h)).MultiplyReal(1 / h)
+		right := spline.Evaluate(float64(i) + h).Subtract(at).MultiplyReal(1 / h)
+		if diff := left.Subtract(right).Abs(); diff > 1e-3 {
+			t.Errorf("Key %d: the derivative jumps from %s to %s", i, left, right)
+		}
+	}
+
+	// Sin los puntos de control, encadenar Slerp tiene esquinas
+	keys := spline.keys
+	at := keys[2]
+	left := at.Subtract(Slerp(keys[1], keys[2], 1-h)).MultiplyReal(1 / h)
+	right := Slerp(keys[2], keys[3], h).Subtract(at).MultiplyReal(1 / h)
+	if left.Subtract(right).Abs() < 1e-2 {
+		t.Errorf("Expected piecewise Slerp to have a corner at key 2")
+	}
+}
+
+// TestSquadSplineErrors prueba los errores de construcción
+func TestSquadSplineErrors(t *testing.T) {
+	if _, err := NewSquadSpline([]Quaternion{Identity()}); !errors.Is(err, ErrTooFewKeys) {
+		t.Errorf("Expected ErrTooFewKeys, got %v", err)
+	}
+	if _, err := NewSquadSpline([]Quaternion{Identity(), {}}); !errors.Is(err, ErrZeroNorm) {
+		t.Errorf("Expected ErrZeroNorm, got %v", err)
+	}
+}
+
+// BenchmarkSlerp mide el costo de una interpolación
+func BenchmarkSlerp(b *testing.B) {
+	q1 := FromAxisAngle([3]float64{1, 0, 0}, 0.4)
+	q2 := FromAxisAngle([3]float64{0, 1, 1}, 2.1)
+	for i := 0; i < b.N; i++ {
+		Slerp(q1, q2, 0.3)
+	}
+}
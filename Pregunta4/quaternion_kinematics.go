@@ -0,0 +1,69 @@
+package quaternion
+
+// Cinemática de orientaciones. La velocidad angular ω se expresa en el
+// sistema del cuerpo, que es lo que miden los giróscopos de una IMU: la
+// orientación evoluciona según q̇ = ½ q ⊗ (0, ω). Para una velocidad en el
+// sistema del mundo basta rotarla antes al del cuerpo con
+// q.Conjugate().RotateVector(ω). Las velocidades van en rad/s y los pasos de
+// tiempo en segundos.
+
+// AngularRate da la velocidad angular en el sistema del cuerpo en el
+// instante t, para integrar movimientos con velocidad variable
+type AngularRate func(t float64) [3]float64
+
+// Derivative calcula la derivada de la orientación q con velocidad angular
+// omega: q̇ = ½ q ⊗ (0, ω)
+func Derivative(q Quaternion, omega [3]float64) Quaternion {
+	return q.Multiply(pure(omega)).MultiplyReal(0.5)
+}
+
+// IntegrateEuler avanza la orientación un paso dt con el método de Euler
+// explícito, q + q̇ dt, y normaliza el resultado para que no se aleje de la
+// esfera unitaria. Su error es de primer orden en dt; con velocidad
+// constante la normalización lo deja sobre el giro correcto y el error del
+// ángulo baja a segundo orden.
+func IntegrateEuler(q Quaternion, omega [3]float64, dt float64) Quaternion {
+	next := q.Add(Derivative(q, omega).MultiplyReal(dt))
+	n, err := next.Normalize()
+	if err != nil {
+		return q
+	}
+	return n
+}
+
+// IntegrateRK4 avanza la orientación de t a t + dt con Runge-Kutta de cuarto
+// orden, evaluando la velocidad en t, t + dt/2 y t + dt, y normaliza el
+// resultado. Conviene cuando la velocidad cambia durante el paso.
+func IntegrateRK4(q Quaternion, rate AngularRate, t, dt float64) Quaternion {
+	omega0 := rate(t)
+	omegaMid := rate(t + dt/2)
+	omega1 := rate(t + dt)
+
+	k1 := Derivative(q, omega0)
+	k2 := Derivative(q.Add(k1.MultiplyReal(dt/2)), omegaMid)
+	k3 := Derivative(q.Add(k2.MultiplyReal(dt/2)), omegaMid)
+	k4 := Derivative(q.Add(k3.MultiplyReal(dt)), omega1)
+
+	sum := k1.Add(k2.MultiplyReal(2)).Add(k3.MultiplyReal(2)).Add(k4)
+	n, err := q.Add(sum.MultiplyReal(dt / 6)).Normalize()
+	if err != nil {
+		return q
+	}
+	return n
+}
+
+// IntegrateExp avanza la orientación un paso dt con el mapa exponencial,
+// q ⊗ exp(½ ω dt), que es exacto si ω es constante durante el paso
+func IntegrateExp(q Quaternion, omega [3]float64, dt float64) Quaternion {
+	half := pure(omega).MultiplyReal(dt / 2)
+	return q.Multiply(half.Exp())
+}
+
+// AngularVelocity calcula la velocidad angular constante, en el sistema del
+// cuerpo, que lleva de q1 a q2 en el tiempo dt: la inversa de IntegrateExp.
+// Usa el camino más corto entre ambas orientaciones, así que solo recupera
+// giros de menos de media vuelta por paso. dt debe ser positivo.
+func AngularVelocity(q1, q2 Quaternion, dt float64) [3]float64 {
+	r := RotationVector(q1, q2)
+	return [3]float64{r[0] / dt, r[1] / dt, r[2] / dt}
+}
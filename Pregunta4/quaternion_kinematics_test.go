@@ -0,0 +1,122 @@
+package quaternion
+
+import (
+	"math"
+	"testing"
+)
+
+// constantRateOrientation es la solución analítica con velocidad constante
+// omega desde q0: q(t) = q0 ⊗ rotación de |ω|t alrededor de ω
+func constantRateOrientation(q0 Quaternion, omega [3]float64, t float64) Quaternion {
+	rate := math.Sqrt(omega[0]*omega[0] + omega[1]*omega[1] + omega[2]*omega[2])
+	return q0.Multiply(FromAxisAngle(omega, rate*t))
+}
+
+// integrationError integra durante un segundo con el paso dado y devuelve el
+// ángulo entre el resultado y la solución analítica
+func integrationError(step func(q Quaternion, t, dt float64) Quaternion, q0 Quaternion, omega [3]float64, dt float64) float64 {
+	q := q0
+	steps := int(math.Round(1 / dt))
+	for i := 0; i < steps; i++ {
+		q = step(q, float64(i)*dt, dt)
+	}
+	return angleBetween(q, constantRateOrientation(q0, omega, 1))
+}
+
+var (
+	kinematicsStart = FromEuler([3]float64{0.4, -0.3, 1.1}, EulerZYX)
+	kinematicsOmega = [3]float64{0.7, -1.3, 2.1}
+)
+
+// TestDerivativeMatchesFiniteDifference prueba q̇ contra la solución analítica
+func TestDerivativeMatchesFiniteDifference(t *testing.T) {
+	const h = 1e-6
+	before := constantRateOrientation(kinematicsStart, kinematicsOmega, -h)
+	after := constantRateOrientation(kinematicsStart, kinematicsOmega, h)
+	numeric := after.Subtract(before).MultiplyReal(1 / (2 * h))
+	if d := Derivative(kinematicsStart, kinematicsOmega); !relativelyClose(d, numeric, 1e-8) {
+		t.Errorf("Expected %s, got %s", numeric, d)
+	}
+}
+
+// TestIntegrateExpIsExact prueba que el mapa exponencial no acumula error
+// con velocidad constante
+func TestIntegrateExpIsExact(t *testing.T) {
+	step := func(q Quaternion, _, dt float64) Quaternion { return IntegrateExp(q, kinematicsOmega, dt) }
+	if e := integrationError(step, kinematicsStart, kinematicsOmega, 0.001); e > 1e-11 {
+		t.Errorf("Expected an error below 1e-11, got %g", e)
+	}
+}
+
+// TestIntegrateEulerConvergence prueba el orden de Euler con velocidad
+// constante, que por la normalización es 2, y que mantiene la norma
+func TestIntegrateEulerConvergence(t *testing.T) {
+	step := func(q Quaternion, _, dt float64) Quaternion { return IntegrateEuler(q, kinematicsOmega, dt) }
+	coarse := integrationError(step, kinematicsStart, kinematicsOmega, 0.01)
+	fine := integrationError(step, kinematicsStart, kinematicsOmega, 0.005)
+	if ratio := coarse / fine; ratio < 3.5 || ratio > 4.5 {
+		t.Errorf("Expected halving dt to divide the error by 4, got %g and %g", coarse, fine)
+	}
+	if coarse > 1e-3 {
+		t.Errorf("Expected a small error with dt = 0.01, got %g", coarse)
+	}
+
+	q := kinematicsStart
+	for i := 0; i < 10000; i++ {
+		q = IntegrateEuler(q, kinematicsOmega, 0.01)
+	}
+	if math.Abs(q.Abs()-1) > 1e-14 {
+		t.Errorf("Expected a unit quaternion, got |q| = %.17f", q.Abs())
+	}
+}
+
+// TestIntegrateRK4FourthOrder prueba que RK4 converge con orden 4
+func TestIntegrateRK4FourthOrder(t *testing.T) {
+	rate := func(float64) [3]float64 { return kinematicsOmega }
+	step := func(q Quaternion, t, dt float64) Quaternion { return IntegrateRK4(q, rate, t, dt) }
+	coarse := integrationError(step, kinematicsStart, kinematicsOmega, 0.04)
+	fine := integrationError(step, kinematicsStart, kinematicsOmega, 0.02)
+	if ratio := coarse / fine; ratio < 12 || ratio > 20 {
+		t.Errorf("Expected halving dt to divide the error by 16, got %g and %g", coarse, fine)
+	}
+	if fine > 1e-6 {
+		t.Errorf("Expected an error below 1e-6, got %g", fine)
+	}
+}
+
+// TestIntegrateRK4VaryingRate prueba RK4 con una velocidad que cambia de
+// módulo sobre un eje fijo, cuya solución es el giro por la integral
+func TestIntegrateRK4VaryingRate(t *testing.T) {
+	axis := [3]float64{0, 0.6, 0.8}
+	rate := func(t float64) [3]float64 {
+		s := 2 * math.Cos(3*t)
+		return [3]float64{axis[0] * s, axis[1] * s, axis[2] * s}
+	}
+
+	q := kinematicsStart
+	const dt = 0.01
+	for i := 0; i < 200; i++ {
+		q = IntegrateRK4(q, rate, float64(i)*dt, dt)
+	}
+	// ∫₀² 2cos(3t) dt = 2 sen(6) / 3
+	expected := kinematicsStart.Multiply(FromAxisAngle(axis, 2*math.Sin(6)/3))
+	if angle := angleBetween(q, expected); angle > 1e-8 {
+		t.Errorf("Expected %s, got %s (error %g)", expected, q, angle)
+	}
+}
+
+// TestAngularVelocityRoundTrip prueba que se recupera la velocidad usada
+// para integrar, también si q2 llega con el signo opuesto
+func TestAngularVelocityRoundTrip(t *testing.T) {
+	const dt = 0.1
+	q2 := IntegrateExp(kinematicsStart, kinematicsOmega, dt)
+	for _, target := range []Quaternion{q2, q2.Negate()} {
+		omega := AngularVelocity(kinematicsStart, target, dt)
+		if !vectorsClose(omega, kinematicsOmega, 1e-12) {
+			t.Errorf("Expected %v, got %v", kinematicsOmega, omega)
+		}
+	}
+	if omega := AngularVelocity(kinematicsStart, kinematicsStart, dt); omega != [3]float64{} {
+		t.Errorf("Expected no rotation, got %v", omega)
+	}
+}
@@ -91,13 +91,13 @@ func (q Quaternion) Abs() float64 {
 	return math.Sqrt(q.A*q.A + q.B*q.B + q.C*q.C + q.D*q.D)
 }
 
-// Equals compara dos cuaterniones con una tolerancia pequeña
+// Equals compara dos cuaterniones con AbsoluteTolerance(1e-10) (ver
+// ApproxEqual para elegir otra): acepta diferencias de hasta 1e-10 inclusive
+// en cada componente, un infinito solo es igual a otro del mismo signo y NaN
+// no es igual a nada
 func (q Quaternion) Equals(other Quaternion) bool {
 	const epsilon = 1e-10
-	return math.Abs(q.A-other.A) < epsilon &&
-		math.Abs(q.B-other.B) < epsilon &&
-		math.Abs(q.C-other.C) < epsilon &&
-		math.Abs(q.D-other.D) < epsilon
+	return q.ApproxEqual(other, AbsoluteTolerance(epsilon))
 }
 
 // Operadores adicionales para conveniencia
@@ -0,0 +1,158 @@
+package quaternion
+
+import (
+	"errors"
+	"math"
+)
+
+// Conversión entre cuaterniones y matrices de rotación. Las matrices se
+// indexan [fila][columna] y actúan sobre vectores columna: la matriz de q
+// lleva v a M·v, igual que RotateVector. Para subir la matriz a una API
+// gráfica que espera orden por columnas hay que trasponerla.
+
+// polarIterations limita las iteraciones de la ortonormalización; con
+// matrices casi ortogonales converge en tres o cuatro
+const polarIterations = 50
+
+// ErrNotRotation indica que una matriz no se puede interpretar como rotación
+// porque es singular, tiene determinante negativo (incluye una reflexión) o
+// tiene componentes no finitos
+var ErrNotRotation = errors.New("quaternion: la matriz no es una rotación")
+
+// ToRotationMatrix devuelve la matriz de rotación 3×3 del cuaternión. Si q no
+// es unitario se usa su normalización; el cuaternión cero da la identidad.
+func (q Quaternion) ToRotationMatrix() [3][3]float64 {
+	n := q.A*q.A + q.B*q.B + q.C*q.C + q.D*q.D
+	if n == 0 {
+		return [3][3]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+	}
+	s := 2 / n
+	w, x, y, z := q.A, q.B, q.C, q.D
+	return [3][3]float64{
+		{1 - s*(y*y+z*z), s * (x*y - w*z), s * (x*z + w*y)},
+		{s * (x*y + w*z), 1 - s*(x*x+z*z), s * (y*z - w*x)},
+		{s * (x*z - w*y), s * (y*z + w*x), 1 - s*(x*x+y*y)},
+	}
+}
+
+// ToHomogeneousMatrix devuelve la matriz homogénea 4×4 de la rotación, sin
+// traslación, para pipelines gráficos
+func (q Quaternion) ToHomogeneousMatrix() [4][4]float64 {
+	r := q.ToRotationMatrix()
+	return [4][4]float64{
+		{r[0][0], r[0][1], r[0][2], 0},
+		{r[1][0], r[1][1], r[1][2], 0},
+		{r[2][0], r[2][1], r[2][2], 0},
+		{0, 0, 0, 1},
+	}
+}
+
+// FromRotationMatrix devuelve el cuaternión unitario, con parte real no
+// negativa, de la matriz de rotación m. Si m no es exactamente ortogonal
+// (por ejemplo tras acumular errores de redondeo) se usa la rotación más
+// cercana. Devuelve ErrNotRotation si m es singular, tiene determinante
+// negativo o componentes no finitos.
+func FromRotationMatrix(m [3][3]float64) (Quaternion, error) {
+	r, err := orthonormalize(m)
+	if err != nil {
+		return Quaternion{}, err
+	}
+	return shepperd(r), nil
+}
+
+// shepperd extrae el cuaternión de una matriz de rotación con el método de
+// Shepperd: de los cuatro componentes se calcula primero el de mayor valor
+// absoluto a partir de la diagonal y los otros tres se obtienen dividiendo
+// por él, así nunca se divide por un número cercano a cero.
+func shepperd(r [3][3]float64) Quaternion {
+	trace := r[0][0] + r[1][1] + r[2][2]
+
+	var q Quaternion
+	switch {
+	case trace >= r[0][0] && trace >= r[1][1] && trace >= r[2][2]:
+		// 4w² = 1 + traza
+		s := 2 * math.Sqrt(1+trace)
+		q = Quaternion{
+			A: s / 4,
+			B: (r[2][1] - r[1][2]) / s,
+			C: (r[0][2] - r[2][0]) / s,
+			D: (r[1][0] - r[0][1]) / s,
+		}
+	case r[0][0] >= r[1][1] && r[0][0] >= r[2][2]:
+		// 4x² = 1 + 2r₀₀ - traza
+		s := 2 * math.Sqrt(1+2*r[0][0]-trace)
+		q = Quaternion{
+			A: (r[2][1] - r[1][2]) / s,
+			B: s / 4,
+			C: (r[0][1] + r[1][0]) / s,
+			D: (r[0][2] + r[2][0]) / s,
+		}
+	case r[1][1] >= r[2][2]:
+		s := 2 * math.Sqrt(1+2*r[1][1]-trace)
+		q = Quaternion{
+			A: (r[0][2] - r[2][0]) / s,
+			B: (r[0][1] + r[1][0]) / s,
+			C: s / 4,
+			D: (r[1][2] + r[2][1]) / s,
+		}
+	default:
+		s := 2 * math.Sqrt(1+2*r[2][2]-trace)
+		q = Quaternion{
+			A: (r[1][0] - r[0][1]) / s,
+			B: (r[0][2] + r[2][0]) / s,
+			C: (r[1][2] + r[2][1]) / s,
+			D: s / 4,
+		}
+	}
+
+	if q.A < 0 {
+		q = q.Negate()
+	}
+	// Quita el error de redondeo que queda en la norma
+	n, _ := q.Normalize()
+	return n
+}
+
+// orthonormalize devuelve la matriz de rotación más cercana a m (el factor
+// ortogonal de su descomposición polar) con la iteración de Newton
+// R ← (R + R⁻ᵀ) / 2, que converge cuadráticamente para matrices no singulares
+func orthonormalize(m [3][3]float64) ([3][3]float64, error) {
+	r := m
+	for iteration := 0; iteration < polarIterations; iteration++ {
+		cofactors, det := cofactorsAndDeterminant(r)
+		if !(det > 0) || math.IsInf(det, 0) {
+			// Cubre también NaN, porque toda comparación con NaN es falsa
+			return [3][3]float64{}, ErrNotRotation
+		}
+
+		// R⁻ᵀ es la matriz de cofactores dividida por el determinante
+		change := 0.0
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				next := (r[i][j] + cofactors[i][j]/det) / 2
+				change = math.Max(change, math.Abs(next-r[i][j]))
+				r[i][j] = next
+			}
+		}
+		if change < 1e-15 {
+			break
+		}
+	}
+	return r, nil
+}
+
+// cofactorsAndDeterminant calcula la matriz de cofactores de m y su
+// determinante
+func cofactorsAndDeterminant(m [3][3]float64) ([3][3]float64, float64) {
+	var c [3][3]float64
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			i1, i2 := (i+1)%3, (i+2)%3
+			j1, j2 := (j+1)%3, (j+2)%3
+			// Con índices cíclicos el signo (-1)^(i+j) ya queda incluido
+			c[i][j] = m[i1][j1]*m[i2][j2] - m[i1][j2]*m[i2][j1]
+		}
+	}
+	det := m[0][0]*c[0][0] + m[0][1]*c[0][1] + m[0][2]*c[0][2]
+	return c, det
+}
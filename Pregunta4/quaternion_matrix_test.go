@@ -0,0 +1,120 @@
+package quaternion
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// TestRotationMatrixMatchesRotateVector prueba que M·v coincide con RotateVector
+func TestRotationMatrixMatchesRotateVector(t *testing.T) {
+	q := FromEuler([3]float64{0.4, -1.1, 2.3}, EulerZYX)
+	m := q.ToRotationMatrix()
+	v := [3]float64{1, -2, 0.5}
+
+	var product [3]float64
+	for i := 0; i < 3; i++ {
+		product[i] = m[i][0]*v[0] + m[i][1]*v[1] + m[i][2]*v[2]
+	}
+	if expected := q.RotateVector(v); !vectorsClose(product, expected, 1e-12) {
+		t.Errorf("Expected %v, got %v", expected, product)
+	}
+}
+
+// TestRotationMatrixRoundTrip prueba la ida y vuelta con muchas rotaciones
+func TestRotationMatrixRoundTrip(t *testing.T) {
+	for _, q := range randomQuaternions(1000, 1) {
+		unit, err := q.Normalize()
+		if err != nil {
+			continue
+		}
+		back, err := FromRotationMatrix(unit.ToRotationMatrix())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !back.SameRotation(unit, 1e-9) || back.A < 0 {
+			t.Fatalf("Expected ±%s with a non-negative real part, got %s", unit, back)
+		}
+	}
+}
+
+// TestFromRotationMatrixBranches prueba cada rama del método de Shepperd,
+// incluidas las medias vueltas, donde la traza es -1
+func TestFromRotationMatrixBranches(t *testing.T) {
+	cases := []Quaternion{
+		Identity(),
+		FromAxisAngle([3]float64{1, 0, 0}, math.Pi),
+		FromAxisAngle([3]float64{0, 1, 0}, math.Pi),
+		FromAxisAngle([3]float64{0, 0, 1}, math.Pi),
+		FromAxisAngle([3]float64{1, 1, 0}, math.Pi),
+		FromAxisAngle([3]float64{0, 1, 1}, 3),
+	}
+	for _, q := range cases {
+		back, err := FromRotationMatrix(q.ToRotationMatrix())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !back.SameRotation(q, 1e-9) {
+			t.Errorf("Expected %s, got %s", q, back)
+		}
+	}
+}
+
+// TestFromRotationMatrixNearlyOrthogonal prueba que una matriz con ruido se
+// corrige a la rotación más cercana
+func TestFromRotationMatrixNearlyOrthogonal(t *testing.T) {
+	q := FromEuler([3]float64{0.3, 0.2, -0.9}, EulerXYZ)
+	m := q.ToRotationMatrix()
+	m[0][1] += 1e-6
+	m[2][0] -= 2e-6
+	m[1][1] *= 1 + 1e-6
+
+	back, err := FromRotationMatrix(m)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if math.Abs(back.Abs()-1) > 1e-15 {
+		t.Errorf("Expected a unit quaternion, got |q| = %.17f", back.Abs())
+	}
+	if angle := angleBetween(back, q); angle > 1e-5 {
+		t.Errorf("Expected a rotation close to %s, got %s", q, back)
+	}
+
+	// Una matriz escalada representa la misma rotación
+	for i := range m {
+		for j := range m[i] {
+			m[i][j] = 3 * q.ToRotationMatrix()[i][j]
+		}
+	}
+	if back, _ := FromRotationMatrix(m); !back.SameRotation(q, 1e-9) {
+		t.Errorf("Expected %s, got %s", q, back)
+	}
+}
+
+// TestFromRotationMatrixErrors prueba las matrices que no son rotaciones
+func TestFromRotationMatrixErrors(t *testing.T) {
+	cases := map[string][3][3]float64{
+		"reflection": {{1, 0, 0}, {0, 1, 0}, {0, 0, -1}},
+		"singular":   {{1, 0, 0}, {0, 1, 0}, {0, 0, 0}},
+		"NaN":        {{math.NaN(), 0, 0}, {0, 1, 0}, {0, 0, 1}},
+	}
+	for name, m := range cases {
+		if _, err := FromRotationMatrix(m); !errors.Is(err, ErrNotRotation) {
+			t.Errorf("%s: expected ErrNotRotation, got %v", name, err)
+		}
+	}
+}
+
+// TestHomogeneousMatrix prueba la matriz 4×4
+func TestHomogeneousMatrix(t *testing.T) {
+	q := FromAxisAngle([3]float64{0, 0, 1}, math.Pi/2)
+	h := q.ToHomogeneousMatrix()
+	expected := [4][4]float64{{0, -1, 0, 0}, {1, 0, 0, 0}, {0, 0, 1, 0}, {0, 0, 0, 1}}
+	for i := range h {
+		for j := range h[i] {
+			if math.Abs(h[i][j]-expected[i][j]) > 1e-15 {
+				t.Fatalf("Expected %v, got %v", expected, h)
+			}
+		}
+	}
+}
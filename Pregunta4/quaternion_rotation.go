@@ -0,0 +1,174 @@
+package quaternion
+
+import (
+	"fmt"
+	"math"
+)
+
+// Rotaciones en 3D con cuaterniones unitarios. Un cuaternión q rota el
+// vector v con q·v·q⁻¹, tomando v como el cuaternión puro 0 + xi + yj + zk.
+// Todas las funciones usan la regla de la mano derecha y ángulos en radianes.
+
+// gimbalLockTolerance es el valor de cos(β) por debajo del cual ToEuler
+// considera que el segundo ángulo está en bloqueo de cardán
+const gimbalLockTolerance = 1e-12
+
+// EulerOrder indica el orden de los ejes de unos ángulos de Euler (Tait-Bryan).
+// Los ángulos son intrínsecos: con el orden XYZ y ángulos (a, b, c) se rota
+// primero a sobre X, luego b sobre la Y ya rotada y por último c sobre la Z
+// resultante, es decir q = qx(a)·qy(b)·qz(c). Esto equivale a rotaciones
+// extrínsecas (sobre ejes fijos) en el orden inverso: ZYX con (c, b, a).
+type EulerOrder int
+
+// Órdenes de rotación admitidos
+const (
+	EulerXYZ EulerOrder = iota
+	EulerXZY
+	EulerYXZ
+	EulerYZX
+	EulerZXY
+	EulerZYX
+)
+
+// eulerAxes indica los índices de los ejes (0 = X, 1 = Y, 2 = Z) de cada orden
+var eulerAxes = map[EulerOrder][3]int{
+	EulerXYZ: {0, 1, 2},
+	EulerXZY: {0, 2, 1},
+	EulerYXZ: {1, 0, 2},
+	EulerYZX: {1, 2, 0},
+	EulerZXY: {2, 0, 1},
+	EulerZYX: {2, 1, 0},
+}
+
+// String devuelve el nombre del orden, por ejemplo "XYZ"
+func (o EulerOrder) String() string {
+	axes, ok := eulerAxes[o]
+	if !ok {
+		return fmt.Sprintf("EulerOrder(%d)", int(o))
+	}
+	names := "XYZ"
+	return string([]byte{names[axes[0]], names[axes[1]], names[axes[2]]})
+}
+
+// axes devuelve los ejes del orden; un orden desconocido provoca un pánico
+func (o EulerOrder) axes() [3]int {
+	axes, ok := eulerAxes[o]
+	if !ok {
+		panic(fmt.Sprintf("quaternion: orden de Euler desconocido %d", int(o)))
+	}
+	return axes
+}
+
+// Identity devuelve el cuaternión identidad 1 + 0i + 0j + 0k, que no rota
+func Identity() Quaternion {
+	return Quaternion{A: 1}
+}
+
+// FromAxisAngle crea el cuaternión unitario que rota angle radianes
+// alrededor de axis. El eje no necesita estar normalizado; si es el vector
+// cero no hay rotación definida y se devuelve la identidad.
+func FromAxisAngle(axis [3]float64, angle float64) Quaternion {
+	norm := math.Sqrt(axis[0]*axis[0] + axis[1]*axis[1] + axis[2]*axis[2])
+	if norm == 0 {
+		return Identity()
+	}
+	s := math.Sin(angle/2) / norm
+	return Quaternion{
+		A: math.Cos(angle / 2),
+		B: axis[0] * s,
+		C: axis[1] * s,
+		D: axis[2] * s,
+	}
+}
+
+// ToAxisAngle devuelve el eje unitario y el ángulo, en [0, π], de la
+// rotación que representa el cuaternión. q y -q representan la misma
+// rotación, así que el ángulo nunca supera π. Si no hay rotación (o q es
+// cero) se devuelve el eje X con ángulo 0.
+func (q Quaternion) ToAxisAngle() ([3]float64, float64) {
+	norm := q.Abs()
+	if norm == 0 {
+		return [3]float64{1, 0, 0}, 0
+	}
+	w, x, y, z := q.A/norm, q.B/norm, q.C/norm, q.D/norm
+	if w < 0 {
+		w, x, y, z = -w, -x, -y, -z
+	}
+
+	s := math.Sqrt(x*x + y*y + z*z)
+	if s == 0 {
+		return [3]float64{1, 0, 0}, 0
+	}
+	// atan2 es más preciso que 2·acos(w) para ángulos pequeños
+	return [3]float64{x / s, y / s, z / s}, 2 * math.Atan2(s, w)
+}
+
+// basisRotation devuelve la rotación de angle radianes sobre el eje axis
+// (0 = X, 1 = Y, 2 = Z)
+func basisRotation(axis int, angle float64) Quaternion {
+	var v [3]float64
+	v[axis] = 1
+	return FromAxisAngle(v, angle)
+}
+
+// FromEuler crea el cuaternión de los ángulos de Euler intrínsecos dados,
+// aplicados en el orden indicado (ver EulerOrder)
+func FromEuler(angles [3]float64, order EulerOrder) Quaternion {
+	axes := order.axes()
+	return basisRotation(axes[0], angles[0]).
+		Multiply(basisRotation(axes[1], angles[1])).
+		Multiply(basisRotation(axes[2], angles[2]))
+}
+
+// ToEuler devuelve los ángulos de Euler intrínsecos de la rotación en el
+// orden indicado. El primer y el tercer ángulo quedan en (-π, π] y el
+// segundo en [-π/2, π/2]. En el bloqueo de cardán (segundo ángulo ±π/2) el
+// primer y el tercer eje coinciden y solo se puede conocer su combinación:
+// en ese caso el tercer ángulo es 0 y toda la rotación queda en el primero.
+func (q Quaternion) ToEuler(order EulerOrder) [3]float64 {
+	axes := order.axes()
+	i, j, k := axes[0], axes[1], axes[2]
+	// sign es 1 si los ejes siguen el orden cíclico X → Y → Z y -1 si no
+	sign := 1.0
+	if (j-i+3)%3 != 1 {
+		sign = -1
+	}
+
+	r := q.ToRotationMatrix()
+	// β sale de atan2 y no de asin(sen β), que cerca de ±π/2 pierde la mitad
+	// de los dígitos
+	sinB := sign * r[i][k]
+	cosB := math.Hypot(r[i][i], r[i][j])
+	b := math.Atan2(sinB, cosB)
+
+	if cosB < gimbalLockTolerance {
+		// Con el tercer ángulo en 0 la columna j de la matriz solo depende
+		// de la rotación sobre el primer eje
+		a := math.Atan2(sign*r[k][j], r[j][j])
+		return [3]float64{a, b, 0}
+	}
+	a := math.Atan2(-sign*r[j][k], r[k][k])
+	c := math.Atan2(-sign*r[i][j], r[i][i])
+	return [3]float64{a, b, c}
+}
+
+// RotateVector rota v con la rotación que representa el cuaternión, es decir
+// calcula q·v·q⁻¹. Como se usa el inverso, la escala de q no afecta al
+// resultado; si q es cero se devuelve v sin cambios.
+func (q Quaternion) RotateVector(v [3]float64) [3]float64 {
+	norm := q.Abs()
+	if norm == 0 {
+		return v
+	}
+	w, x, y, z := q.A/norm, q.B/norm, q.C/norm, q.D/norm
+
+	// v' = v + 2w(u × v) + 2u × (u × v), con u = (x, y, z)
+	tx := 2 * (y*v[2] - z*v[1])
+	ty := 2 * (z*v[0] - x*v[2])
+	tz := 2 * (x*v[1] - y*v[0])
+	return [3]float64{
+		v[0] + w*tx + (y*tz - z*ty),
+		v[1] + w*ty + (z*tx - x*tz),
+		v[2] + w*tz + (x*ty - y*tx),
+	}
+}
@@ -0,0 +1,238 @@
+package quaternion
+
+import (
+	"math"
+	"testing"
+)
+
+// allEulerOrders contiene los seis órdenes de Tait-Bryan
+var allEulerOrders = []EulerOrder{EulerXYZ, EulerXZY, EulerYXZ, EulerYZX, EulerZXY, EulerZYX}
+
+// vectorsClose compara dos vectores con tolerancia
+func vectorsClose(a, b [3]float64, tolerance float64) bool {
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// TestFromAxisAngleKnownRotations prueba rotaciones de 90° sobre cada eje
+func TestFromAxisAngleKnownRotations(t *testing.T) {
+	cases := []struct {
+		axis     [3]float64
+		v        [3]float64
+		expected [3]float64
+	}{
+		{[3]float64{0, 0, 1}, [3]float64{1, 0, 0}, [3]float64{0, 1, 0}},
+		{[3]float64{1, 0, 0}, [3]float64{0, 1, 0}, [3]float64{0, 0, 1}},
+		{[3]float64{0, 1, 0}, [3]float64{0, 0, 1}, [3]float64{1, 0, 0}},
+		{[3]float64{0, 0, 5}, [3]float64{2, 0, 0}, [3]float64{0, 2, 0}},
+	}
+
+	for _, c := range cases {
+		q := FromAxisAngle(c.axis, math.Pi/2)
+		if math.Abs(q.Abs()-1) > 1e-12 {
+			t.Errorf("FromAxisAngle should return a unit quaternion, got |q| = %f", q.Abs())
+		}
+		if result := q.RotateVector(c.v); !vectorsClose(result, c.expected, 1e-12) {
+			t.Errorf("Rotating %v by 90° about %v: expected %v, got %v", c.v, c.axis, c.expected, result)
+		}
+	}
+}
+
+// TestFromAxisAngleHalfTurn prueba que 180° sobre Z es el cuaternión k
+func TestFromAxisAngleHalfTurn(t *testing.T) {
+	q := FromAxisAngle([3]float64{0, 0, 1}, math.Pi)
+	if !q.Equals(New(0, 0, 0, 1)) {
+		t.Errorf("Expected k, got %s", q)
+	}
+}
+
+// TestFromAxisAngleZeroAxis prueba que un eje nulo no rota
+func TestFromAxisAngleZeroAxis(t *testing.T) {
+	if q := FromAxisAngle([3]float64{0, 0, 0}, 1); !q.Equals(Identity()) {
+		t.Errorf("Expected identity for a zero axis, got %s", q)
+	}
+}
+
+// TestToAxisAngleRoundTrip prueba que ToAxisAngle invierte FromAxisAngle
+func TestToAxisAngleRoundTrip(t *testing.T) {
+	axis := [3]float64{1 / math.Sqrt(3), 1 / math.Sqrt(3), 1 / math.Sqrt(3)}
+	for _, angle := range []float64{1e-8, 0.3, math.Pi / 2, 3} {
+		gotAxis, gotAngle := FromAxisAngle(axis, angle).ToAxisAngle()
+		if !vectorsClose(gotAxis, axis, 1e-9) || math.Abs(gotAngle-angle) > 1e-12 {
+			t.Errorf("Angle %g: expected (%v, %g), got (%v, %g)", angle, axis, angle, gotAxis, gotAngle)
+		}
+	}
+}
+
+// TestToAxisAngleCanonical prueba que el ángulo devuelto no supera π
+func TestToAxisAngleCanonical(t *testing.T) {
+	// 270° sobre Z es lo mismo que 90° sobre -Z
+	axis, angle := FromAxisAngle([3]float64{0, 0, 1}, 3*math.Pi/2).ToAxisAngle()
+	if !vectorsClose(axis, [3]float64{0, 0, -1}, 1e-12) || math.Abs(angle-math.Pi/2) > 1e-12 {
+		t.Errorf("Expected (-Z, π/2), got (%v, %g)", axis, angle)
+	}
+
+	// Un cuaternión no unitario se normaliza
+	axis, angle = New(2, 0, 2, 0).ToAxisAngle()
+	if !vectorsClose(axis, [3]float64{0, 1, 0}, 1e-12) || math.Abs(angle-math.Pi/2) > 1e-12 {
+		t.Errorf("Expected (Y, π/2), got (%v, %g)", axis, angle)
+	}
+}
+
+// TestToAxisAngleIdentity prueba el eje por omisión cuando no hay rotación
+func TestToAxisAngleIdentity(t *testing.T) {
+	for _, q := range []Quaternion{Identity(), New(-3, 0, 0, 0), New(0, 0, 0, 0)} {
+		axis, angle := q.ToAxisAngle()
+		if axis != [3]float64{1, 0, 0} || angle != 0 {
+			t.Errorf("%s: expected (X, 0), got (%v, %g)", q, axis, angle)
+		}
+	}
+}
+
+// TestRotateVectorScaleInvariant prueba que la escala de q no afecta la rotación
+func TestRotateVectorScaleInvariant(t *testing.T) {
+	q := FromAxisAngle([3]float64{1, 2, 3}, 0.7)
+	v := [3]float64{4, -5, 6}
+	if !vectorsClose(q.RotateVector(v), q.MultiplyReal(3.5).RotateVector(v), 1e-12) {
+		t.Error("Scaling the quaternion should not change the rotation")
+	}
+	if New(0, 0, 0, 0).RotateVector(v) != v {
+		t.Error("The zero quaternion should leave the vector unchanged")
+	}
+}
+
+// TestRotateVectorMatchesSandwich compara con el producto q·v·q*
+func TestRotateVectorMatchesSandwich(t *testing.T) {
+	q := FromAxisAngle([3]float64{-1, 0.5, 2}, 2.1)
+	v := [3]float64{0.3, 1.7, -2.2}
+
+	p := q.Multiply(New(0, v[0], v[1], v[2])).Multiply(q.Conjugate())
+	expected := [3]float64{p.B, p.C, p.D}
+	if result := q.RotateVector(v); !vectorsClose(result, expected, 1e-12) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+// TestRotateVectorComposition prueba que q2·q1 aplica primero q1 y luego q2
+func TestRotateVectorComposition(t *testing.T) {
+	q1 := FromAxisAngle([3]float64{0, 0, 1}, math.Pi/2)
+	q2 := FromAxisAngle([3]float64{1, 0, 0}, math.Pi/2)
+	v := [3]float64{1, 0, 0}
+
+	// X → Y (sobre Z) → Z (sobre X)
+	if result := q2.Multiply(q1).RotateVector(v); !vectorsClose(result, [3]float64{0, 0, 1}, 1e-12) {
+		t.Errorf("Expected (0, 0, 1), got %v", result)
+	}
+}
+
+// TestFromEulerIntrinsic prueba la convención intrínseca con un caso conocido
+func TestFromEulerIntrinsic(t *testing.T) {
+	// XYZ intrínseco (90°, 90°, 0): primero X sobre X, luego sobre la nueva Y
+	q := FromEuler([3]float64{math.Pi / 2, math.Pi / 2, 0}, EulerXYZ)
+	expected := FromAxisAngle([3]float64{1, 0, 0}, math.Pi/2).Multiply(FromAxisAngle([3]float64{0, 1, 0}, math.Pi/2))
+	if !q.Equals(expected) {
+		t.Errorf("Expected %s, got %s", expected, q)
+	}
+
+	// Equivale a las rotaciones extrínsecas en orden inverso: primero sobre
+	// la Y fija y luego sobre la X fija
+	v := [3]float64{0, 0, 1}
+	extrinsic := FromAxisAngle([3]float64{1, 0, 0}, math.Pi/2).RotateVector(
+		FromAxisAngle([3]float64{0, 1, 0}, math.Pi/2).RotateVector(v))
+	if result := q.RotateVector(v); !vectorsClose(result, extrinsic, 1e-12) {
+		t.Errorf("Expected %v, got %v", extrinsic, result)
+	}
+}
+
+// TestFromEulerYawPitchRoll prueba el orden ZYX usado en aeronáutica
+func TestFromEulerYawPitchRoll(t *testing.T) {
+	// Guiñada de 90°: la nariz (X) pasa a apuntar a Y
+	q := FromEuler([3]float64{math.Pi / 2, 0, 0}, EulerZYX)
+	if result := q.RotateVector([3]float64{1, 0, 0}); !vectorsClose(result, [3]float64{0, 1, 0}, 1e-12) {
+		t.Errorf("Expected (0, 1, 0), got %v", result)
+	}
+
+	// Cabeceo de 90° hacia arriba (negativo sobre Y): la nariz apunta a Z
+	q = FromEuler([3]float64{0, -math.Pi / 2, 0}, EulerZYX)
+	if result := q.RotateVector([3]float64{1, 0, 0}); !vectorsClose(result, [3]float64{0, 0, 1}, 1e-12) {
+		t.Errorf("Expected (0, 0, 1), got %v", result)
+	}
+}
+
+// TestToEulerRoundTrip prueba que ToEuler invierte FromEuler en todos los órdenes
+func TestToEulerRoundTrip(t *testing.T) {
+	angles := [][3]float64{
+		{0.1, 0.2, 0.3},
+		{-2.5, 1.2, 3.0},
+		{3.1, -1.5, -0.4},
+		{0, 0, 0},
+	}
+	for _, order := range allEulerOrders {
+		for _, a := range angles {
+			result := FromEuler(a, order).ToEuler(order)
+			if !vectorsClose(result, a, 1e-9) {
+				t.Errorf("%s: expected %v, got %v", order, a, result)
+			}
+		}
+	}
+}
+
+// TestToEulerGimbalLock prueba el segundo ángulo en ±90° en todos los órdenes
+func TestToEulerGimbalLock(t *testing.T) {
+	for _, order := range allEulerOrders {
+		for _, b := range []float64{math.Pi / 2, -math.Pi / 2} {
+			q := FromEuler([3]float64{0.4, b, 0.9}, order)
+			result := q.ToEuler(order)
+
+			if math.Abs(result[1]-b) > 1e-6 || result[2] != 0 {
+				t.Errorf("%s with β=%g: expected (·, %g, 0), got %v", order, b, b, result)
+			}
+			if math.IsNaN(result[0]) || !FromEuler(result, order).SameRotation(q, 1e-9) {
+				t.Errorf("%s with β=%g: %v does not reproduce the rotation", order, b, result)
+			}
+		}
+	}
+}
+
+// TestToEulerNearGimbalLock prueba ángulos apenas lejos del bloqueo
+func TestToEulerNearGimbalLock(t *testing.T) {
+	for _, order := range allEulerOrders {
+		q := FromEuler([3]float64{0.4, math.Pi/2 - 1e-4, 0.9}, order)
+		if result := q.ToEuler(order); !FromEuler(result, order).SameRotation(q, 1e-9) {
+			t.Errorf("%s: %v does not reproduce the rotation", order, result)
+		}
+	}
+}
+
+// TestToEulerCloseToPole prueba que los ángulos se recuperan con precisión
+// muy cerca de ±90°, donde asin(sen β) perdería la mitad de los dígitos
+func TestToEulerCloseToPole(t *testing.T) {
+	for _, order := range allEulerOrders {
+		for _, b := range []float64{math.Pi/2 - 3e-5, -math.Pi/2 + 1e-6} {
+			a := [3]float64{0.4, b, 0.9}
+			if result := FromEuler(a, order).ToEuler(order); !vectorsClose(result, a, 1e-9) {
+				t.Errorf("%s: expected %v, got %v", order, a, result)
+			}
+		}
+	}
+}
+
+// TestEulerOrderString prueba los nombres de los órdenes
+func TestEulerOrderString(t *testing.T) {
+	if EulerYZX.String() != "YZX" || EulerOrder(42).String() != "EulerOrder(42)" {
+		t.Errorf("Unexpected names: %s, %s", EulerYZX, EulerOrder(42))
+	}
+}
+
+func BenchmarkRotateVector(b *testing.B) {
+	q := FromAxisAngle([3]float64{1, 2, 3}, 0.5)
+	v := [3]float64{1, 0, 0}
+
+	for i := 0; i < b.N; i++ {
+		v = q.RotateVector(v)
+	}
+}
@@ -0,0 +1,124 @@
+package quaternion
+
+import (
+	"math/big"
+	"strconv"
+)
+
+// Scalar es la restricción de los coeficientes de Q. Se expresa con métodos
+// y no con operadores para que también la cumplan tipos como *big.Rat, que
+// no admiten + ni *. Las operaciones no modifican el receptor, y el valor
+// cero del tipo debe ser el cero aditivo.
+type Scalar[T any] interface {
+	Add(T) T
+	Sub(T) T
+	Mul(T) T
+	// Quo divide; nunca se llama con divisor cero
+	Quo(T) T
+	Neg() T
+	IsZero() bool
+	// Equal compara exactamente, sin tolerancia
+	Equal(T) bool
+	String() string
+}
+
+// F32 es un float32 que cumple Scalar, para subir datos a la GPU
+type F32 float32
+
+func (x F32) Add(y F32) F32    { return x + y }
+func (x F32) Sub(y F32) F32    { return x - y }
+func (x F32) Mul(y F32) F32    { return x * y }
+func (x F32) Quo(y F32) F32    { return x / y }
+func (x F32) Neg() F32         { return -x }
+func (x F32) IsZero() bool     { return x == 0 }
+func (x F32) Equal(y F32) bool { return x == y }
+func (x F32) String() string   { return strconv.FormatFloat(float64(x), 'g', -1, 32) }
+
+// F64 es un float64 que cumple Scalar
+type F64 float64
+
+func (x F64) Add(y F64) F64    { return x + y }
+func (x F64) Sub(y F64) F64    { return x - y }
+func (x F64) Mul(y F64) F64    { return x * y }
+func (x F64) Quo(y F64) F64    { return x / y }
+func (x F64) Neg() F64         { return -x }
+func (x F64) IsZero() bool     { return x == 0 }
+func (x F64) Equal(y F64) bool { return x == y }
+func (x F64) String() string   { return strconv.FormatFloat(float64(x), 'g', -1, 64) }
+
+// Rat es un racional exacto que cumple Scalar. Envuelve un *big.Rat que
+// nunca se modifica después de creado, así que los valores pueden copiarse
+// y compartirse como los de F64. El valor cero es el racional 0.
+type Rat struct {
+	r *big.Rat
+}
+
+// NewRat crea el racional a/b. Entra en pánico si b es cero, como big.NewRat.
+func NewRat(a, b int64) Rat {
+	return Rat{big.NewRat(a, b)}
+}
+
+// RatFromBig crea un Rat con una copia de r
+func RatFromBig(r *big.Rat) Rat {
+	return Rat{new(big.Rat).Set(r)}
+}
+
+// Big devuelve una copia del valor como *big.Rat
+func (x Rat) Big() *big.Rat {
+	return new(big.Rat).Set(x.get())
+}
+
+// get devuelve el *big.Rat del valor, que es nil en el valor cero
+func (x Rat) get() *big.Rat {
+	if x.r == nil {
+		return new(big.Rat)
+	}
+	return x.r
+}
+
+func (x Rat) Add(y Rat) Rat    { return Rat{new(big.Rat).Add(x.get(), y.get())} }
+func (x Rat) Sub(y Rat) Rat    { return Rat{new(big.Rat).Sub(x.get(), y.get())} }
+func (x Rat) Mul(y Rat) Rat    { return Rat{new(big.Rat).Mul(x.get(), y.get())} }
+func (x Rat) Quo(y Rat) Rat    { return Rat{new(big.Rat).Quo(x.get(), y.get())} }
+func (x Rat) Neg() Rat         { return Rat{new(big.Rat).Neg(x.get())} }
+func (x Rat) IsZero() bool     { return x.get().Sign() == 0 }
+func (x Rat) Equal(y Rat) bool { return x.get().Cmp(y.get()) == 0 }
+func (x Rat) String() string   { return x.get().RatString() }
+
+// BigFloat es un número de coma flotante de precisión arbitraria que cumple
+// Scalar. Como Rat, envuelve un *big.Float que no se modifica. Cada resultado
+// usa la mayor precisión de sus operandos; el valor cero es 0.
+type BigFloat struct {
+	f *big.Float
+}
+
+// NewBigFloat crea el valor x con prec bits de mantisa
+func NewBigFloat(x float64, prec uint) BigFloat {
+	return BigFloat{new(big.Float).SetPrec(prec).SetFloat64(x)}
+}
+
+// BigFloatFromBig crea un BigFloat con una copia de f, con su misma precisión
+func BigFloatFromBig(f *big.Float) BigFloat {
+	return BigFloat{new(big.Float).Copy(f)}
+}
+
+// Big devuelve una copia del valor como *big.Float
+func (x BigFloat) Big() *big.Float {
+	return new(big.Float).Copy(x.get())
+}
+
+func (x BigFloat) get() *big.Float {
+	if x.f == nil {
+		return new(big.Float)
+	}
+	return x.f
+}
+
+func (x BigFloat) Add(y BigFloat) BigFloat { return BigFloat{new(big.Float).Add(x.get(), y.get())} }
+func (x BigFloat) Sub(y BigFloat) BigFloat { return BigFloat{new(big.Float).Sub(x.get(), y.get())} }
+func (x BigFloat) Mul(y BigFloat) BigFloat { return BigFloat{new(big.Float).Mul(x.get(), y.get())} }
+func (x BigFloat) Quo(y BigFloat) BigFloat { return BigFloat{new(big.Float).Quo(x.get(), y.get())} }
+func (x BigFloat) Neg() BigFloat           { return BigFloat{new(big.Float).Neg(x.get())} }
+func (x BigFloat) IsZero() bool            { return x.get().Sign() == 0 }
+func (x BigFloat) Equal(y BigFloat) bool   { return x.get().Cmp(y.get()) == 0 }
+func (x BigFloat) String() string          { return x.get().Text('g', -1) }
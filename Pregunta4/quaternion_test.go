@@ -65,7 +65,7 @@ func TestAdditionCommutative(t *testing.T) {
 	r1 := q1.Add(q2)
 	r2 := q2.Add(q1)
 	
-	if !almostEqual(r1, r2) {
+	if !r1.ApproxEqual(r2, testTolerance) {
 		t.Errorf("Addition should be commutative")
 	}
 }
@@ -74,7 +74,7 @@ func TestAdditionCommutative(t *testing.T) {
 func TestAdditionWithScalar(t *testing.T) {
 	q := New(1, 2, 3, 4)
 	
-	result := q.AddScalar(3)
+	result := q.AddReal(3)
 	
 	if result.A != 4 || result.B != 2 || result.C != 3 || result.D != 4 {
 		t.Errorf("Expected (4+2i+3j+4k), got (%f+%fi+%fj+%fk)", 
@@ -86,7 +86,7 @@ func TestAdditionWithScalar(t *testing.T) {
 func TestAdditionWithFloat(t *testing.T) {
 	q := New(1, 2, 3, 4)
 	
-	result := q.AddScalar(2.5)
+	result := q.AddReal(2.5)
 	
 	if !floatEqual(result.A, 3.5) || result.B != 2 || result.C != 3 || result.D != 4 {
 		t.Errorf("Expected (3.5+2i+3j+4k), got (%f+%fi+%fj+%fk)", 
@@ -161,7 +161,7 @@ func TestDoubleConjugate(t *testing.T) {
 	
 	result := q.Conjugate().Conjugate()
 	
-	if !almostEqual(q, result) {
+	if !q.ApproxEqual(result, testTolerance) {
 		t.Errorf("Double conjugate should equal original")
 	}
 }
@@ -204,7 +204,7 @@ func TestProductWithUnit(t *testing.T) {
 	
 	result := q.Multiply(unit)
 	
-	if !almostEqual(q, result) {
+	if !q.ApproxEqual(result, testTolerance) {
 		t.Errorf("Product with unit quaternion should not change quaternion")
 	}
 }
@@ -267,7 +267,7 @@ func TestProductNonCommutative(t *testing.T) {
 	r1 := qi.Multiply(qj)  // ij = k
 	r2 := qj.Multiply(qi)  // ji = -k
 	
-	if almostEqual(r1, r2) {
+	if r1.ApproxEqual(r2, testTolerance) {
 		t.Errorf("Product should NOT be commutative")
 	}
 }
@@ -276,7 +276,7 @@ func TestProductNonCommutative(t *testing.T) {
 func TestProductWithScalar(t *testing.T) {
 	q := New(1, 2, 3, 4)
 	
-	result := q.MultiplyScalar(3)
+	result := q.MultiplyReal(3)
 	
 	if result.A != 3 || result.B != 6 || result.C != 9 || result.D != 12 {
 		t.Errorf("Expected (3+6i+9j+12k), got (%f+%fi+%fj+%fk)", 
@@ -288,7 +288,7 @@ func TestProductWithScalar(t *testing.T) {
 func TestProductWithFloat(t *testing.T) {
 	q := New(1, 2, 3, 4)
 	
-	result := q.MultiplyScalar(2.5)
+	result := q.MultiplyReal(2.5)
 	
 	if !floatEqual(result.A, 2.5) || !floatEqual(result.B, 5.0) || 
 	   !floatEqual(result.C, 7.5) || !floatEqual(result.D, 10.0) {
@@ -306,7 +306,7 @@ func TestProductAssociative(t *testing.T) {
 	r1 := q1.Multiply(q2).Multiply(q3)
 	r2 := q1.Multiply(q2.Multiply(q3))
 	
-	if !almostEqual(r1, r2) {
+	if !r1.ApproxEqual(r2, testTolerance) {
 		t.Errorf("Product should be associative")
 	}
 }
@@ -315,7 +315,7 @@ func TestProductAssociative(t *testing.T) {
 func TestMagnitudeBasic(t *testing.T) {
 	q := New(1, 2, 3, 4)
 	
-	result := q.Magnitude()
+	result := q.Abs()
 	expected := math.Sqrt(1*1 + 2*2 + 3*3 + 4*4) // sqrt(30)
 	
 	if !floatEqual(result, expected) {
@@ -327,7 +327,7 @@ func TestMagnitudeBasic(t *testing.T) {
 func TestMagnitudeZero(t *testing.T) {
 	q := New(0, 0, 0, 0)
 	
-	result := q.Magnitude()
+	result := q.Abs()
 	
 	if result != 0 {
 		t.Errorf("Magnitude of zero quaternion should be 0, got %f", result)
@@ -344,7 +344,7 @@ func TestMagnitudeUnit(t *testing.T) {
 	}
 	
 	for _, q := range tests {
-		result := q.Magnitude()
+		result := q.Abs()
 		if !floatEqual(result, 1.0) {
 			t.Errorf("Magnitude of unit quaternion should be 1, got %f", result)
 		}
@@ -355,7 +355,7 @@ func TestMagnitudeUnit(t *testing.T) {
 func TestMagnitudePositive(t *testing.T) {
 	q := New(-1, -2, -3, -4)
 	
-	result := q.Magnitude()
+	result := q.Abs()
 	
 	if result < 0 {
 		t.Errorf("Magnitude should always be positive, got %f", result)
@@ -367,7 +367,7 @@ func TestMagnitudeProperty(t *testing.T) {
 	q := New(1, 2, 3, 4)
 	
 	product := q.Multiply(q.Conjugate())
-	magnitude := q.Magnitude()
+	magnitude := q.Abs()
 	
 	if !floatEqual(product.A, magnitude*magnitude) {
 		t.Errorf("q * conj(q) should equal |q|^2")
@@ -393,10 +393,9 @@ func TestComplexExpression(t *testing.T) {
 // TestMixedOperations prueba operaciones mixtas
 func TestMixedOperations(t *testing.T) {
 	a := New(1, 2, 3, 4)
-	b := New(5, 6, 7, 8)
 	
 	// a * 3.0 + 7.0
-	result := a.MultiplyScalar(3.0).AddScalar(7.0)
+	result := a.MultiplyReal(3.0).AddReal(7.0)
 	
 	if !floatEqual(result.A, 10.0) || result.B != 6 || result.C != 9 || result.D != 12 {
 		t.Errorf("Expected (10+6i+9j+12k), got (%f+%fi+%fj+%fk)", 
@@ -410,8 +409,8 @@ func TestOperationWithMagnitude(t *testing.T) {
 	c := New(5, 6, 7, 8)
 	
 	// (b + b) * |c|
-	mag := c.Magnitude()
-	result := b.Add(b).MultiplyScalar(mag)
+	mag := c.Abs()
+	result := b.Add(b).MultiplyReal(mag)
 	
 	if math.IsNaN(result.A) || math.IsInf(result.A, 0) {
 		t.Errorf("Operation with magnitude resulted in NaN or Inf")
@@ -420,16 +419,11 @@ func TestOperationWithMagnitude(t *testing.T) {
 
 // Funciones auxiliares
 
-func floatEqual(a, b float64) bool {
-	const epsilon = 1e-9
-	return math.Abs(a-b) < epsilon
-}
+// testTolerance es la tolerancia con que se comparan los resultados
+var testTolerance = AbsoluteTolerance(1e-9)
 
-func almostEqual(q1, q2 Quaternion) bool {
-	return floatEqual(q1.A, q2.A) && 
-	       floatEqual(q1.B, q2.B) && 
-	       floatEqual(q1.C, q2.C) && 
-	       floatEqual(q1.D, q2.D)
+func floatEqual(a, b float64) bool {
+	return math.Abs(a-b) <= testTolerance.Value
 }
 
 // Benchmarks
@@ -464,6 +458,6 @@ func BenchmarkMagnitude(b *testing.B) {
 	q := New(1, 2, 3, 4)
 	
 	for i := 0; i < b.N; i++ {
-		_ = q.Magnitude()
+		_ = q.Abs()
 	}
 }
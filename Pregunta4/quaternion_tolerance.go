@@ -0,0 +1,183 @@
+package quaternion
+
+import (
+	"fmt"
+	"math"
+)
+
+// Comparación con tolerancia configurable. Equals compara cada componente
+// con AbsoluteTolerance(1e-10), que es demasiado estricta para
+// cuaterniones grandes y demasiado laxa para los muy chicos. ApproxEqual
+// permite elegir el criterio y SameRotation compara rotaciones, donde q y
+// -q son iguales.
+
+// ToleranceMode es el criterio con que Tolerance compara los componentes
+type ToleranceMode int
+
+// Criterios de comparación
+const (
+	// Absolute acepta |a - b| ≤ Value
+	Absolute ToleranceMode = iota
+	// Relative acepta |a - b| ≤ Value · max(&q, &other): el error se mide
+	// contra la norma del cuaternión y no contra cada componente, así que
+	// un componente casi cero de un cuaternión grande no exige más
+	// precisión que los demás
+	Relative
+	// ULP acepta que entre a y b haya a lo sumo Value números de punto
+	// flotante representables
+	ULP
+)
+
+// String devuelve el nombre del criterio
+func (m ToleranceMode) String() string {
+	switch m {
+	case Absolute:
+		return "Absolute"
+	case Relative:
+		return "Relative"
+	case ULP:
+		return "ULP"
+	}
+	return fmt.Sprintf("ToleranceMode(%d)", int(m))
+}
+
+// Tolerance es el criterio y el margen de una comparación aproximada. En
+// todos los criterios NaN es distinto de todo, incluso de sí mismo, y los
+// infinitos solo son iguales a sí mismos; +0 y -0 son iguales.
+type Tolerance struct {
+	Mode  ToleranceMode
+	Value float64
+}
+
+// AbsoluteTolerance crea una tolerancia absoluta
+func AbsoluteTolerance(eps float64) Tolerance {
+	return Tolerance{Mode: Absolute, Value: eps}
+}
+
+// RelativeTolerance crea una tolerancia relativa a la norma
+func RelativeTolerance(eps float64) Tolerance {
+	return Tolerance{Mode: Relative, Value: eps}
+}
+
+// ULPTolerance crea una tolerancia de n unidades en la última posición
+func ULPTolerance(n uint64) Tolerance {
+	return Tolerance{Mode: ULP, Value: float64(n)}
+}
+
+// String describe la tolerancia, por ejemplo "Relative 1e-12"
+func (t Tolerance) String() string {
+	return fmt.Sprintf("%s %g", t.Mode, t.Value)
+}
+
+// ComponentDiff describe un componente que no cumple la tolerancia
+type ComponentDiff struct {
+	// Component es el nombre del componente: "A", "B", "C" o "D"
+	Component string
+	// Got y Expected son los valores comparados
+	Got, Expected float64
+	// Diff es la diferencia absoluta y ULPs la distancia en números
+	// representables
+	Diff float64
+	ULPs uint64
+}
+
+// String describe la diferencia, por ejemplo
+// "C: 1.5 vs 1.5000001 (diff 1e-07, 450359962 ULP)"
+func (d ComponentDiff) String() string {
+	return fmt.Sprintf("%s: %g vs %g (diff %g, %d ULP)", d.Component, d.Got, d.Expected, d.Diff, d.ULPs)
+}
+
+// ApproxEqual indica si q y other son iguales componente a componente
+// dentro de la tolerancia
+func (q Quaternion) ApproxEqual(other Quaternion, tol Tolerance) bool {
+	scale := tol.scale(q, other)
+	return tol.within(q.A, other.A, scale) &&
+		tol.within(q.B, other.B, scale) &&
+		tol.within(q.C, other.C, scale) &&
+		tol.within(q.D, other.D, scale)
+}
+
+// Differences devuelve los componentes de q que no coinciden con los de
+// expected dentro de la tolerancia, en el orden A, B, C, D. Está vacío
+// exactamente cuando ApproxEqual es verdadero.
+func (q Quaternion) Differences(expected Quaternion, tol Tolerance) []ComponentDiff {
+	scale := tol.scale(q, expected)
+	got := [4]float64{q.A, q.B, q.C, q.D}
+	want := [4]float64{expected.A, expected.B, expected.C, expected.D}
+
+	var diffs []ComponentDiff
+	for i := range got {
+		if !tol.within(got[i], want[i], scale) {
+			diffs = append(diffs, ComponentDiff{
+				Component: [4]string{"A", "B", "C", "D"}[i],
+				Got:       got[i],
+				Expected:  want[i],
+				Diff:      math.Abs(got[i] - want[i]),
+				ULPs:      ulpDistance(got[i], want[i]),
+			})
+		}
+	}
+	return diffs
+}
+
+// SameRotation indica si q y other representan la misma rotación salvo un
+// ángulo de a lo sumo angleTol radianes (ver AngularDistance). Trata a q y
+// -q como iguales y no depende de la escala; con un cuaternión cero o NaN
+// devuelve false.
+func (q Quaternion) SameRotation(other Quaternion, angleTol float64) bool {
+	return AngularDistance(q, other) <= angleTol
+}
+
+// scale devuelve la norma contra la que se mide la tolerancia relativa
+func (t Tolerance) scale(q, other Quaternion) float64 {
+	if t.Mode != Relative {
+		return 1
+	}
+	return math.Max(q.norm(), other.norm())
+}
+
+// within compara dos componentes
+func (t Tolerance) within(a, b, scale float64) bool {
+	if a == b {
+		return true
+	}
+	if math.IsNaN(a) || math.IsNaN(b) || math.IsInf(a, 0) || math.IsInf(b, 0) {
+		return false
+	}
+	switch t.Mode {
+	case Absolute:
+		return math.Abs(a-b) <= t.Value
+	case Relative:
+		return math.Abs(a-b) <= t.Value*scale
+	case ULP:
+		return float64(ulpDistance(a, b)) <= t.Value
+	}
+	return false
+}
+
+// ulpDistance cuenta los float64 representables entre a y b. Reinterpreta
+// los bits como enteros ordenados: los positivos crecen con sus bits y los
+// negativos se reflejan debajo de cero.
+func ulpDistance(a, b float64) uint64 {
+	if a == b {
+		return 0
+	}
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.MaxUint64
+	}
+	ia, ib := orderedBits(a), orderedBits(b)
+	if ia > ib {
+		return ia - ib
+	}
+	return ib - ia
+}
+
+// orderedBits lleva x a un entero que respeta el orden de los float64,
+// con -0 y +0 en el mismo punto
+func orderedBits(x float64) uint64 {
+	bits := math.Float64bits(x)
+	if bits>>63 == 1 {
+		return 1<<63 - (bits &^ (1 << 63))
+	}
+	return 1<<63 + bits
+}
@@ -0,0 +1,115 @@
+package quaternion
+
+import (
+	"math"
+	"testing"
+)
+
+// TestApproxEqualModes prueba los tres criterios con los mismos pares
+func TestApproxEqualModes(t *testing.T) {
+	big := New(1e6, 2e6, -3e6, 0)
+	cases := []struct {
+		name     string
+		q, other Quaternion
+		tol      Tolerance
+		expected bool
+	}{
+		{"absolute inside", New(1, 2, 3, 4), New(1, 2, 3, 4+1e-7), AbsoluteTolerance(1e-6), true},
+		{"absolute outside", New(1, 2, 3, 4), New(1, 2, 3, 4+1e-5), AbsoluteTolerance(1e-6), false},
+		{"absolute on a big quaternion", big, big.Add(New(0, 0, 0, 1e-4)), AbsoluteTolerance(1e-6), false},
+		{"relative on a big quaternion", big, big.Add(New(0, 0, 0, 1e-4)), RelativeTolerance(1e-9), true},
+		{"relative near zero component", big, big.Add(New(0, 0, 0, 1e-2)), RelativeTolerance(1e-9), false},
+		{"relative small quaternion", New(1e-20, 0, 0, 0), New(1.0000001e-20, 0, 0, 0), RelativeTolerance(1e-6), true},
+		{"absolute small quaternion", New(1e-20, 0, 0, 0), New(2e-20, 0, 0, 0), AbsoluteTolerance(1e-10), true},
+		{"ulp next float", New(1, 0, 0, 0), New(math.Nextafter(1, 2), 0, 0, 0), ULPTolerance(1), true},
+		{"ulp two floats", New(1, 0, 0, 0), New(math.Nextafter(math.Nextafter(1, 2), 2), 0, 0, 0), ULPTolerance(1), false},
+		{"ulp signed zeros", New(0, 0, 0, 0), New(math.Copysign(0, -1), 0, 0, 0), ULPTolerance(0), true},
+		{"ulp across zero", New(5e-324, 0, 0, 0), New(-5e-324, 0, 0, 0), ULPTolerance(2), true},
+		{"nan", New(math.NaN(), 0, 0, 0), New(math.NaN(), 0, 0, 0), AbsoluteTolerance(1), false},
+		{"inf", New(math.Inf(1), 0, 0, 0), New(math.Inf(1), 0, 0, 0), ULPTolerance(0), true},
+		{"inf and max", New(math.Inf(1), 0, 0, 0), New(math.MaxFloat64, 0, 0, 0), ULPTolerance(10), false},
+	}
+	for _, c := range cases {
+		if got := c.q.ApproxEqual(c.other, c.tol); got != c.expected {
+			t.Errorf("%s: expected %v with %s, got %v", c.name, c.expected, c.tol, got)
+		}
+		if got := len(c.q.Differences(c.other, c.tol)) == 0; got != c.expected {
+			t.Errorf("%s: Differences disagrees with ApproxEqual", c.name)
+		}
+	}
+}
+
+// TestEqualsBoundaries prueba los casos límite de Equals: el margen es
+// inclusivo, los infinitos del mismo signo son iguales y NaN no lo es
+func TestEqualsBoundaries(t *testing.T) {
+	inf := math.Inf(1)
+	cases := []struct {
+		q1, q2   Quaternion
+		expected bool
+	}{
+		{New(0, 0, 0, 0), New(1e-10, 0, 0, 0), true},
+		{New(0, 0, 0, 0), New(2e-10, 0, 0, 0), false},
+		{New(inf, 1, 0, 0), New(inf, 1, 0, 0), true},
+		{New(inf, 1, 0, 0), New(-inf, 1, 0, 0), false},
+		{New(math.NaN(), 0, 0, 0), New(math.NaN(), 0, 0, 0), false},
+	}
+	for _, c := range cases {
+		if r := c.q1.Equals(c.q2); r != c.expected {
+			t.Errorf("%g.Equals(%g): expected %v, got %v", c.q1, c.q2, c.expected, r)
+		}
+	}
+}
+
+// TestDifferences prueba el detalle de los componentes distintos
+func TestDifferences(t *testing.T) {
+	got := New(1, 2.5, 3, 4)
+	expected := New(1, 2, 3, math.Nextafter(4, 5))
+	diffs := got.Differences(expected, ULPTolerance(0))
+	if len(diffs) != 2 {
+		t.Fatalf("Expected 2 differences, got %v", diffs)
+	}
+	if d := diffs[0]; d.Component != "B" || d.Got != 2.5 || d.Expected != 2 || d.Diff != 0.5 || d.ULPs != 1<<50 {
+		t.Errorf("Unexpected difference %+v", d)
+	}
+	if s := diffs[1].String(); s != "D: 4 vs 4.000000000000001 (diff 8.881784197001252e-16, 1 ULP)" {
+		t.Errorf("Unexpected description %q", s)
+	}
+}
+
+// TestSameRotation prueba que q, -q y múltiplos de q son la misma rotación
+func TestSameRotation(t *testing.T) {
+	q := FromAxisAngle([3]float64{1, 2, 3}, 0.7)
+	near := q.Multiply(FromAxisAngle([3]float64{0, 0, 1}, 1e-7))
+	if !q.SameRotation(q.Negate(), 1e-15) || !q.SameRotation(q.MultiplyReal(5), 1e-15) {
+		t.Errorf("Expected q, -q and 5q to be the same rotation")
+	}
+	if q.ApproxEqual(q.Negate(), AbsoluteTolerance(1e-10)) {
+		t.Errorf("Expected q and -q to differ component-wise")
+	}
+	if !q.SameRotation(near, 2e-7) || q.SameRotation(near, 5e-8) {
+		t.Errorf("Expected a rotation of 1e-7 rad to be within 2e-7 and not within 5e-8")
+	}
+	if q.SameRotation(Quaternion{}, math.Pi) {
+		t.Errorf("Expected a zero quaternion to be no rotation")
+	}
+}
+
+// TestUlpDistance prueba la distancia en ULP alrededor de casos borde
+func TestUlpDistance(t *testing.T) {
+	cases := []struct {
+		a, b     float64
+		expected uint64
+	}{
+		{1, 1, 0},
+		{1, math.Nextafter(1, 0), 1},
+		{-1, math.Nextafter(-1, -2), 1},
+		{math.Copysign(0, -1), 5e-324, 1},
+		{-5e-324, 5e-324, 2},
+		{math.MaxFloat64, math.Inf(1), 1},
+	}
+	for _, c := range cases {
+		if d := ulpDistance(c.a, c.b); d != c.expected {
+			t.Errorf("ulpDistance(%g, %g): expected %d, got %d", c.a, c.b, c.expected, d)
+		}
+	}
+}
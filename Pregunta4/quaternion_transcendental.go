@@ -0,0 +1,115 @@
+package quaternion
+
+import "math"
+
+// Funciones trascendentes. Todo cuaternión no nulo se escribe en forma polar
+// q = &q (cos θ + n sen θ), con n el vector unitario de la parte imaginaria
+// y θ en [0, π]. Cuando la parte imaginaria es cero la dirección n queda
+// indefinida; igual que en los complejos se elige n = i, de modo que
+// Log(-1) = πi y Sqrt(-4) = 2i.
+
+// vectorNorm calcula la norma de la parte imaginaria sin desbordes
+func (q Quaternion) vectorNorm() float64 {
+	return math.Hypot(math.Hypot(q.B, q.C), q.D)
+}
+
+// norm calcula &q sin desbordes con componentes extremos
+func (q Quaternion) norm() float64 {
+	p, scale := q.scaled()
+	if scale == 0 {
+		return 0
+	}
+	return scale * math.Sqrt(p.Norm2())
+}
+
+// polar devuelve el ángulo θ y la dirección unitaria n de la forma polar,
+// con n = i si la parte imaginaria es cero
+func (q Quaternion) polar() (float64, [3]float64) {
+	s := q.vectorNorm()
+	if s == 0 {
+		if q.A < 0 {
+			return math.Pi, [3]float64{1, 0, 0}
+		}
+		return 0, [3]float64{1, 0, 0}
+	}
+	return math.Atan2(s, q.A), [3]float64{q.B / s, q.C / s, q.D / s}
+}
+
+// fromPolar construye r (cos θ + n sen θ)
+func fromPolar(r, theta float64, n [3]float64) Quaternion {
+	s := r * math.Sin(theta)
+	return Quaternion{A: r * math.Cos(theta), B: n[0] * s, C: n[1] * s, D: n[2] * s}
+}
+
+// Exp calcula la exponencial
+// e^(a + v) = e^a (cos &v + v/&v sen &v)
+func (q Quaternion) Exp() Quaternion {
+	s := q.vectorNorm()
+	if s == 0 {
+		return FromReal(math.Exp(q.A))
+	}
+	return fromPolar(math.Exp(q.A), s, [3]float64{q.B / s, q.C / s, q.D / s})
+}
+
+// Log calcula el logaritmo principal
+// log q = ln &q + n θ
+// La parte imaginaria tiene norma θ ≤ π, así que Exp(Log(q)) = q para todo q
+// no nulo. El logaritmo de un real negativo es ln|a| + πi y el de cero tiene
+// parte real -Inf, como math.Log.
+func (q Quaternion) Log() Quaternion {
+	theta, n := q.polar()
+	return Quaternion{A: math.Log(q.norm()), B: n[0] * theta, C: n[1] * theta, D: n[2] * theta}
+}
+
+// Pow eleva el cuaternión a un exponente real
+// q^t = &q^t (cos tθ + n sen tθ)
+// Con q = 0 el resultado es el real math.Pow(0, t).
+func (q Quaternion) Pow(t float64) Quaternion {
+	r := q.norm()
+	if r == 0 {
+		return FromReal(math.Pow(0, t))
+	}
+	theta, n := q.polar()
+	return fromPolar(math.Pow(r, t), t*theta, n)
+}
+
+// PowQ eleva el cuaternión a un exponente cuaternión
+// q^p = Exp(Log(q) * p)
+// Como el producto no es conmutativo el orden importa: en general difiere de
+// Exp(p * Log(q)). Si p es real coincide con Pow; con q = 0 y p no real el
+// resultado es NaN.
+func (q Quaternion) PowQ(p Quaternion) Quaternion {
+	if p.B == 0 && p.C == 0 && p.D == 0 {
+		return q.Pow(p.A)
+	}
+	return q.Log().Multiply(p).Exp()
+}
+
+// Sqrt calcula la raíz cuadrada principal, la de parte real no negativa.
+// Usa la fórmula cerrada en lugar de Pow(0.5) para no perder precisión:
+// Sqrt(q) = √((&q + a)/2) + v/&v √((&q - a)/2)
+func (q Quaternion) Sqrt() Quaternion {
+	r := q.norm()
+	if r == 0 {
+		return Quaternion{}
+	}
+	s := q.vectorNorm()
+	if s == 0 {
+		if q.A < 0 {
+			return Quaternion{B: math.Sqrt(-q.A)}
+		}
+		return FromReal(math.Sqrt(q.A))
+	}
+
+	// Se calcula primero la mitad que no sufre cancelación y la otra se
+	// obtiene de que el producto de ambas es &v / 2. Se divide cada término
+	// por 2 antes de sumar para que &q + |a| no desborde cerca de MaxFloat64.
+	if q.A >= 0 {
+		a := math.Sqrt(r/2 + q.A/2)
+		f := 1 / (2 * a)
+		return Quaternion{A: a, B: q.B * f, C: q.C * f, D: q.D * f}
+	}
+	imaginary := math.Sqrt(r/2 - q.A/2)
+	f := imaginary / s
+	return Quaternion{A: s / (2 * imaginary), B: q.B * f, C: q.C * f, D: q.D * f}
+}
@@ -0,0 +1,168 @@
+package quaternion
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// relativelyClose compara con tolerancia relativa a la norma del esperado
+func relativelyClose(got, expected Quaternion, tol float64) bool {
+	return got.Subtract(expected).Abs() <= tol*math.Max(1, expected.Abs())
+}
+
+// randomQuaternions genera cuaterniones de prueba reproducibles
+func randomQuaternions(n int, scale float64) []Quaternion {
+	r := rand.New(rand.NewSource(1))
+	qs := make([]Quaternion, n)
+	for i := range qs {
+		qs[i] = New(
+			scale*(2*r.Float64()-1), scale*(2*r.Float64()-1),
+			scale*(2*r.Float64()-1), scale*(2*r.Float64()-1),
+		)
+	}
+	return qs
+}
+
+// TestExpLogIdentity prueba que Exp(Log(q)) = q con entradas aleatorias
+func TestExpLogIdentity(t *testing.T) {
+	for _, q := range randomQuaternions(1000, 5) {
+		if r := q.Log().Exp(); !relativelyClose(r, q, 1e-13) {
+			t.Fatalf("Exp(Log(%s)) should be q, got %s", q, r)
+		}
+	}
+}
+
+// TestLogExpIdentity prueba que Log(Exp(q)) = q si la parte imaginaria
+// tiene norma menor que π, donde Log es la inversa de Exp
+func TestLogExpIdentity(t *testing.T) {
+	for _, q := range randomQuaternions(1000, 1.5) {
+		if r := q.Exp().Log(); !relativelyClose(r, q, 1e-13) {
+			t.Fatalf("Log(Exp(%s)) should be q, got %s", q, r)
+		}
+	}
+}
+
+// TestExpKnownValues prueba valores conocidos de la exponencial
+func TestExpKnownValues(t *testing.T) {
+	if r := New(0, math.Pi, 0, 0).Exp(); !r.Equals(FromReal(-1)) {
+		t.Errorf("e^(πi) should be -1, got %s", r)
+	}
+	if r := New(0, 0, 0, math.Pi/2).Exp(); !r.Equals(New(0, 0, 0, 1)) {
+		t.Errorf("e^(πk/2) should be k, got %s", r)
+	}
+	if r := FromReal(2).Exp(); !r.Equals(FromReal(math.Exp(2))) {
+		t.Errorf("Expected e², got %s", r)
+	}
+}
+
+// TestLogRealBranches prueba el logaritmo de reales positivos, negativos y cero
+func TestLogRealBranches(t *testing.T) {
+	if r := FromReal(math.E).Log(); !r.Equals(FromReal(1)) {
+		t.Errorf("Expected 1, got %s", r)
+	}
+	if r := FromReal(-4).Log(); !r.Equals(New(math.Log(4), math.Pi, 0, 0)) {
+		t.Errorf("Expected ln 4 + πi, got %s", r)
+	}
+	if r := FromReal(0).Log(); !math.IsInf(r.A, -1) {
+		t.Errorf("Expected -Inf, got %s", r)
+	}
+}
+
+// TestSqrtSquares prueba que Sqrt(q)² = q y que la raíz tiene parte real
+// no negativa
+func TestSqrtSquares(t *testing.T) {
+	for _, q := range randomQuaternions(1000, 10) {
+		s := q.Sqrt()
+		if s.A < 0 {
+			t.Fatalf("Sqrt(%s) should have a non-negative real part, got %s", q, s)
+		}
+		if r := s.Multiply(s); !relativelyClose(r, q, 1e-14) {
+			t.Fatalf("Sqrt(%s)² should be q, got %s", q, r)
+		}
+	}
+}
+
+// TestSqrtHuge prueba que la raíz no desborda con una norma cercana a
+// MaxFloat64
+func TestSqrtHuge(t *testing.T) {
+	for _, q := range []Quaternion{New(1, 1, 0, 0), New(-1, 0, 1, 1)} {
+		expected := q.Sqrt().MultiplyReal(1e154)
+		if r := q.MultiplyReal(1e308).Sqrt(); !relativelyClose(r, expected, 1e-14) {
+			t.Errorf("Expected %g, got %g", expected, r)
+		}
+	}
+}
+
+// TestSqrtRealBranches prueba la raíz de reales y de imaginarios casi reales
+func TestSqrtRealBranches(t *testing.T) {
+	if r := FromReal(9).Sqrt(); !r.Equals(FromReal(3)) {
+		t.Errorf("Expected 3, got %s", r)
+	}
+	if r := FromReal(-4).Sqrt(); !r.Equals(New(0, 2, 0, 0)) {
+		t.Errorf("Expected 2i, got %s", r)
+	}
+	if r := FromReal(0).Sqrt(); !r.Equals(FromReal(0)) {
+		t.Errorf("Expected 0, got %s", r)
+	}
+	// Cerca del eje real negativo la raíz sigue la dirección imaginaria
+	q := New(-4, 0, 1e-20, 0)
+	if r := q.Sqrt(); !r.Equals(New(0, 0, 2, 0)) {
+		t.Errorf("Expected 2j, got %s", r)
+	}
+}
+
+// TestPowMatchesProducts prueba Pow con exponentes enteros y fraccionarios
+func TestPowMatchesProducts(t *testing.T) {
+	q := New(1, -2, 0.5, 3)
+	if r := q.Pow(2); !relativelyClose(r, q.Multiply(q), 1e-14) {
+		t.Errorf("Expected %s, got %s", q.Multiply(q), r)
+	}
+	if r := q.Pow(3); !relativelyClose(r, q.Multiply(q).Multiply(q), 1e-14) {
+		t.Errorf("Expected %s, got %s", q.Multiply(q).Multiply(q), r)
+	}
+	inverse, _ := q.Inverse()
+	if r := q.Pow(-1); !relativelyClose(r, inverse, 1e-14) {
+		t.Errorf("Expected %s, got %s", inverse, r)
+	}
+	if r := q.Pow(0.5); !relativelyClose(r, q.Sqrt(), 1e-14) {
+		t.Errorf("Expected %s, got %s", q.Sqrt(), r)
+	}
+	if r := q.Pow(0); !r.Equals(FromReal(1)) {
+		t.Errorf("Expected 1, got %s", r)
+	}
+}
+
+// TestPowZero prueba las potencias de cero
+func TestPowZero(t *testing.T) {
+	zero := FromReal(0)
+	if r := zero.Pow(2); !r.Equals(zero) {
+		t.Errorf("Expected 0, got %s", r)
+	}
+	if r := zero.Pow(0); !r.Equals(FromReal(1)) {
+		t.Errorf("Expected 1, got %s", r)
+	}
+	if r := zero.Pow(-1); !math.IsInf(r.A, 1) {
+		t.Errorf("Expected +Inf, got %s", r)
+	}
+}
+
+// TestPowQ prueba la potencia con exponente cuaternión
+func TestPowQ(t *testing.T) {
+	q := New(1, -2, 0.5, 3)
+	if r := q.PowQ(FromReal(2.5)); !relativelyClose(r, q.Pow(2.5), 1e-14) {
+		t.Errorf("Expected %s, got %s", q.Pow(2.5), r)
+	}
+
+	// e^(i π/2) = i
+	i := New(0, 1, 0, 0)
+	if r := FromReal(math.E).PowQ(i.MultiplyReal(math.Pi / 2)); !r.Equals(i) {
+		t.Errorf("Expected i, got %s", r)
+	}
+
+	// El orden del producto importa: Exp(Log(q) * p) ≠ Exp(p * Log(q))
+	p := New(0, 0, 1, 0)
+	if q.PowQ(p).Equals(p.Multiply(q.Log()).Exp()) {
+		t.Errorf("Expected PowQ to depend on the order of the product")
+	}
+}
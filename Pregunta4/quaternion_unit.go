@@ -0,0 +1,107 @@
+package quaternion
+
+// UnitQuaternion es un cuaternión de norma 1, es decir una rotación en 3D.
+// Solo puede construirse con funciones que normalizan, de modo que el código
+// de rotación no puede recibir por accidente un cuaternión no unitario. El
+// valor cero de UnitQuaternion representa la identidad.
+type UnitQuaternion struct {
+	// q guarda el cuaternión tal cual; como un cuaternión unitario nunca es
+	// cero, Quaternion interpreta el cero del valor cero como la identidad
+	q Quaternion
+}
+
+// unitFrom envuelve un cuaternión que ya es unitario
+func unitFrom(q Quaternion) UnitQuaternion {
+	return UnitQuaternion{q: q}
+}
+
+// NewUnit crea el cuaternión unitario con la dirección de a + bi + cj + dk.
+// Devuelve ErrZeroNorm si todos los coeficientes son cero.
+func NewUnit(a, b, c, d float64) (UnitQuaternion, error) {
+	return New(a, b, c, d).Unit()
+}
+
+// Unit normaliza el cuaternión y lo devuelve como UnitQuaternion.
+// Devuelve ErrZeroNorm si el cuaternión es cero.
+func (q Quaternion) Unit() (UnitQuaternion, error) {
+	n, err := q.Normalize()
+	if err != nil {
+		return UnitQuaternion{}, err
+	}
+	return unitFrom(n), nil
+}
+
+// UnitIdentity devuelve la rotación identidad
+func UnitIdentity() UnitQuaternion {
+	return UnitQuaternion{}
+}
+
+// UnitFromAxisAngle crea la rotación de angle radianes alrededor de axis.
+// Un eje nulo da la identidad (ver FromAxisAngle).
+func UnitFromAxisAngle(axis [3]float64, angle float64) UnitQuaternion {
+	return unitFrom(FromAxisAngle(axis, angle))
+}
+
+// UnitFromEuler crea la rotación de los ángulos de Euler intrínsecos dados
+// (ver FromEuler)
+func UnitFromEuler(angles [3]float64, order EulerOrder) UnitQuaternion {
+	return unitFrom(FromEuler(angles, order))
+}
+
+// Quaternion devuelve el cuaternión unitario como Quaternion
+func (u UnitQuaternion) Quaternion() Quaternion {
+	if u.q == (Quaternion{}) {
+		return Identity()
+	}
+	return u.q
+}
+
+// String devuelve una representación en string del cuaternión
+func (u UnitQuaternion) String() string {
+	return u.Quaternion().String()
+}
+
+// Multiply compone dos rotaciones: u * other aplica primero other y luego u.
+// El resultado se vuelve a normalizar para que el error de redondeo no se
+// acumule al encadenar muchas rotaciones.
+func (u UnitQuaternion) Multiply(other UnitQuaternion) UnitQuaternion {
+	product := u.Quaternion().Multiply(other.Quaternion())
+	// El producto de dos cuaterniones unitarios nunca es cero
+	n, _ := product.Normalize()
+	return unitFrom(n)
+}
+
+// Conjugate calcula la conjugada, que para un cuaternión unitario es también
+// su inverso: la rotación opuesta
+func (u UnitQuaternion) Conjugate() UnitQuaternion {
+	return unitFrom(u.Quaternion().Conjugate())
+}
+
+// Inverse calcula la rotación inversa. A diferencia de Quaternion.Inverse
+// nunca falla, porque un cuaternión unitario no puede ser cero.
+func (u UnitQuaternion) Inverse() UnitQuaternion {
+	return u.Conjugate()
+}
+
+// RotateVector rota el vector v (ver Quaternion.RotateVector)
+func (u UnitQuaternion) RotateVector(v [3]float64) [3]float64 {
+	return u.Quaternion().RotateVector(v)
+}
+
+// ToAxisAngle devuelve el eje unitario y el ángulo de la rotación
+// (ver Quaternion.ToAxisAngle)
+func (u UnitQuaternion) ToAxisAngle() ([3]float64, float64) {
+	return u.Quaternion().ToAxisAngle()
+}
+
+// ToEuler devuelve los ángulos de Euler intrínsecos de la rotación
+// (ver Quaternion.ToEuler)
+func (u UnitQuaternion) ToEuler(order EulerOrder) [3]float64 {
+	return u.Quaternion().ToEuler(order)
+}
+
+// Equals compara dos cuaterniones unitarios con una tolerancia pequeña.
+// q y -q representan la misma rotación pero no se consideran iguales.
+func (u UnitQuaternion) Equals(other UnitQuaternion) bool {
+	return u.Quaternion().Equals(other.Quaternion())
+}
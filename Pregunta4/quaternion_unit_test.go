@@ -0,0 +1,108 @@
+package quaternion
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// TestNewUnitNormalizes prueba que los constructores normalizan
+func TestNewUnitNormalizes(t *testing.T) {
+	u, err := NewUnit(1, 2, 3, 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if math.Abs(u.Quaternion().Abs()-1) > 1e-15 {
+		t.Errorf("Expected a unit quaternion, got |u| = %.17f", u.Quaternion().Abs())
+	}
+
+	u, _ = New(0, 0, 2, 0).Unit()
+	if !u.Quaternion().Equals(New(0, 0, 1, 0)) {
+		t.Errorf("Expected j, got %s", u)
+	}
+}
+
+// TestNewUnitZero prueba que el cuaternión cero no puede ser unitario
+func TestNewUnitZero(t *testing.T) {
+	if _, err := NewUnit(0, 0, 0, 0); !errors.Is(err, ErrZeroNorm) {
+		t.Errorf("Expected ErrZeroNorm, got %v", err)
+	}
+}
+
+// TestUnitZeroValueIsIdentity prueba que el valor cero es la identidad
+func TestUnitZeroValueIsIdentity(t *testing.T) {
+	var u UnitQuaternion
+	if !u.Quaternion().Equals(Identity()) || !u.Equals(UnitIdentity()) {
+		t.Errorf("The zero value should be the identity, got %s", u)
+	}
+	if v := u.RotateVector([3]float64{1, 2, 3}); v != [3]float64{1, 2, 3} {
+		t.Errorf("The identity should not rotate, got %v", v)
+	}
+	step := UnitFromAxisAngle([3]float64{0, 0, 1}, 0.5)
+	if r := u.Multiply(step); !r.Equals(step) {
+		t.Errorf("Composing with the zero value should not change the rotation, got %s", r)
+	}
+}
+
+// TestUnitKeepsSmallComponents prueba que se conservan todos los bits de la
+// parte real, también cuando es muy pequeña frente a 1
+func TestUnitKeepsSmallComponents(t *testing.T) {
+	u, _ := NewUnit(1e-20, 1, 0, 0)
+	if q := u.Quaternion(); q.A != 1e-20 || q.B != 1 {
+		t.Errorf("Expected 1e-20 + 1i, got %v", q)
+	}
+}
+
+// TestUnitMultiplyStaysUnit prueba que encadenar rotaciones no acumula error
+func TestUnitMultiplyStaysUnit(t *testing.T) {
+	step := UnitFromAxisAngle([3]float64{1, 2, 3}, 0.001)
+	u := UnitIdentity()
+	for i := 0; i < 100000; i++ {
+		u = u.Multiply(step)
+	}
+	if math.Abs(u.Quaternion().Abs()-1) > 1e-14 {
+		t.Errorf("Expected a unit quaternion, got |u| = %.17f", u.Quaternion().Abs())
+	}
+
+	axis, angle := u.ToAxisAngle()
+	expected := math.Mod(100, 2*math.Pi)
+	if expected > math.Pi {
+		expected = 2*math.Pi - expected
+	}
+	if math.Abs(angle-expected) > 1e-6 || axis[2] > 0 {
+		t.Errorf("Expected angle %g about the reversed axis, got %g about %v", expected, angle, axis)
+	}
+}
+
+// TestUnitInverse prueba que la inversa deshace la rotación
+func TestUnitInverse(t *testing.T) {
+	u := UnitFromEuler([3]float64{0.3, -0.8, 1.2}, EulerZYX)
+	v := [3]float64{1, -2, 0.5}
+
+	back := u.Inverse().RotateVector(u.RotateVector(v))
+	for i := range v {
+		if math.Abs(back[i]-v[i]) > 1e-12 {
+			t.Errorf("Expected %v, got %v", v, back)
+			break
+		}
+	}
+	if !u.Multiply(u.Inverse()).Equals(UnitIdentity()) {
+		t.Errorf("u * u⁻¹ should be the identity, got %s", u.Multiply(u.Inverse()))
+	}
+}
+
+// TestUnitMatchesQuaternion prueba que las conversiones coinciden con las de Quaternion
+func TestUnitMatchesQuaternion(t *testing.T) {
+	angles := [3]float64{0.1, 0.2, 0.3}
+	u := UnitFromEuler(angles, EulerXYZ)
+	if !u.Quaternion().Equals(FromEuler(angles, EulerXYZ)) {
+		t.Errorf("Expected %s, got %s", FromEuler(angles, EulerXYZ), u)
+	}
+	result := u.ToEuler(EulerXYZ)
+	for i := range angles {
+		if math.Abs(result[i]-angles[i]) > 1e-12 {
+			t.Errorf("Expected %v, got %v", angles, result)
+			break
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Package quaterniontest ofrece comprobaciones para pruebas con
+// cuaterniones. Cuando una comprobación falla, el mensaje indica qué
+// componentes difieren y por cuánto, o el ángulo entre las rotaciones, en
+// lugar de solo mostrar los dos valores.
+package quaterniontest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"quaternion"
+)
+
+// ApproxEqual comprueba que got coincida con expected dentro de la
+// tolerancia. Si no coinciden registra un error con cada componente
+// distinto y devuelve false; la prueba sigue corriendo.
+func ApproxEqual(t testing.TB, got, expected quaternion.Quaternion, tol quaternion.Tolerance) bool {
+	t.Helper()
+	diffs := got.Differences(expected, tol)
+	if len(diffs) == 0 {
+		return true
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Expected %g, got %g (tolerance %s)", expected, got, tol)
+	for _, d := range diffs {
+		fmt.Fprintf(&b, "\n\t%s", d)
+	}
+	t.Error(b.String())
+	return false
+}
+
+// SameRotation comprueba que got y expected representen la misma rotación
+// salvo angleTol radianes, con q y -q iguales. Si no, registra un error con
+// el ángulo entre ambas y devuelve false.
+func SameRotation(t testing.TB, got, expected quaternion.Quaternion, angleTol float64) bool {
+	t.Helper()
+	if got.SameRotation(expected, angleTol) {
+		return true
+	}
+	t.Errorf("Expected the rotation %g, got %g: they differ by %g rad (tolerance %g)",
+		expected, got, quaternion.AngularDistance(got, expected), angleTol)
+	return false
+}
@@ -0,0 +1,69 @@
+package quaterniontest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"quaternion"
+)
+
+// recorder guarda los errores en lugar de hacer fallar la prueba
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Error(args ...any) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// TestApproxEqualReportsComponents prueba que el mensaje nombra cada
+// componente distinto con su diferencia
+func TestApproxEqualReportsComponents(t *testing.T) {
+	r := &recorder{}
+	got := quaternion.New(1, 2.5, 3, 4.001)
+	expected := quaternion.New(1, 2, 3, 4)
+	if ApproxEqual(r, got, expected, quaternion.AbsoluteTolerance(1e-6)) {
+		t.Fatalf("Expected the comparison to fail")
+	}
+	if len(r.errors) != 1 {
+		t.Fatalf("Expected one error, got %v", r.errors)
+	}
+	for _, part := range []string{"tolerance Absolute 1e-06", "\n\tB: 2.5 vs 2 (diff 0.5,", "\n\tD: 4.001 vs 4 (diff"} {
+		if !strings.Contains(r.errors[0], part) {
+			t.Errorf("Expected the message to contain %q, got %q", part, r.errors[0])
+		}
+	}
+	if strings.Contains(r.errors[0], "\n\tA:") || strings.Contains(r.errors[0], "\n\tC:") {
+		t.Errorf("Expected only B and D in the message, got %q", r.errors[0])
+	}
+
+	r = &recorder{}
+	if !ApproxEqual(r, got, got, quaternion.ULPTolerance(0)) || len(r.errors) != 0 {
+		t.Errorf("Expected equal quaternions to pass, got %v", r.errors)
+	}
+}
+
+// TestSameRotationReportsAngle prueba el mensaje con el ángulo
+func TestSameRotationReportsAngle(t *testing.T) {
+	q := quaternion.FromAxisAngle([3]float64{0, 0, 1}, 0.5)
+	p := quaternion.FromAxisAngle([3]float64{0, 0, 1}, 0.75)
+
+	r := &recorder{}
+	if !SameRotation(r, q.Negate(), q, 1e-12) || len(r.errors) != 0 {
+		t.Errorf("Expected q and -q to pass, got %v", r.errors)
+	}
+	if SameRotation(r, p, q, 0.1) {
+		t.Fatalf("Expected the comparison to fail")
+	}
+	if len(r.errors) != 1 || !strings.Contains(r.errors[0], "differ by 0.25 rad") {
+		t.Errorf("Expected the angle in the message, got %v", r.errors)
+	}
+}